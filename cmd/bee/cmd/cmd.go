@@ -59,6 +59,8 @@ func newCommand(opts ...option) (c *command, err error) {
 		return nil, err
 	}
 
+	c.initDBCmd()
+	c.initStateStoreCmd()
 	c.initVersionCmd()
 	return c, nil
 }
@@ -0,0 +1,604 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/puller"
+	"github.com/ethersphere/bee/pkg/statestore"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/validator"
+	"github.com/spf13/cobra"
+)
+
+const (
+	optionNameDBDataDir   = "data-dir"
+	optionNameDBVerbosity = "verbosity"
+)
+
+// progressInterval is the interval at which the progress
+// of export and import commands is printed.
+var progressInterval = time.Second
+
+func (c *command) initDBCmd() {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Maintain the local store of a node that is not running",
+	}
+	cmd.PersistentFlags().String(optionNameDBDataDir, filepath.Join(c.homeDir, ".bee"), "data directory")
+	cmd.PersistentFlags().String(optionNameDBVerbosity, "warn", "log verbosity level 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=trace")
+
+	cmd.AddCommand(
+		c.newDBInfoCmd(),
+		c.newDBVerifyCmd(),
+		c.newDBCompactCmd(),
+		c.newDBExportCmd(),
+		c.newDBImportCmd(),
+		c.newDBNukeCmd(),
+	)
+
+	c.root.AddCommand(cmd)
+}
+
+func (c *command) newDBInfoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "info",
+		Short:   "Print the local store schema and index counts",
+		Args:    cobra.NoArgs,
+		PreRunE: c.bindDBFlags,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			db, err := c.openLocalstore(cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if e := db.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+
+			schema, err := db.SchemaName()
+			if err != nil {
+				return err
+			}
+			stats, err := db.Stats()
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			fmt.Fprintf(w, "base key: %s\n", swarm.NewAddress(db.BaseKey()))
+			fmt.Fprintf(w, "schema: %s\n", schema)
+			fmt.Fprintf(w, "gc policy: %s\n", stats.GCPolicy)
+			fmt.Fprintf(w, "gc size: %d\n", stats.GCSize)
+			fmt.Fprintf(w, "pinned chunks: %d\n", stats.PinnedChunks)
+			names := make([]string, 0, len(stats.Indexes))
+			for name := range stats.Indexes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(w, "%s: %d\n", name, stats.Indexes[name])
+			}
+			return nil
+		},
+	}
+}
+
+func (c *command) newDBVerifyCmd() *cobra.Command {
+	const optionNameRepair = "repair"
+
+	cmd := &cobra.Command{
+		Use:     "verify",
+		Short:   "Validate the data of all chunks and the consistency of the local store indexes",
+		Args:    cobra.NoArgs,
+		PreRunE: c.bindDBFlags,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			db, err := c.openLocalstore(cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if e := db.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+
+			r, err := db.Verify(localstore.VerifyOptions{
+				Validator: validator.NewContentAddressValidator(),
+				Repair:    c.config.GetBool(optionNameRepair),
+			})
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			for _, addr := range r.InvalidChunks {
+				fmt.Fprintf(w, "invalid chunk %s\n", addr)
+			}
+			for _, addr := range r.MissingData {
+				fmt.Fprintf(w, "missing data of chunk %s\n", addr)
+			}
+			for _, e := range r.IndexErrors {
+				fmt.Fprintln(w, e)
+			}
+			fmt.Fprintf(w, "verified %d chunks: %d invalid, %d without data, %d index errors\n", r.Chunks, len(r.InvalidChunks), len(r.MissingData), len(r.IndexErrors))
+			if r.OK() {
+				return nil
+			}
+			if r.Repaired {
+				fmt.Fprintln(w, "all problems are repaired")
+				return nil
+			}
+			return errors.New("local store verification failed")
+		},
+	}
+	cmd.Flags().Bool(optionNameRepair, false, "remove invalid chunks and chunks without data, and repair inconsistent indexes")
+	return cmd
+}
+
+func (c *command) newDBCompactCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "compact",
+		Short:   "Compact the local store to reclaim disk space",
+		Args:    cobra.NoArgs,
+		PreRunE: c.bindDBFlags,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			db, err := c.openLocalstore(cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if e := db.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+
+			start := time.Now()
+			if err := db.Compact(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "compacted in %s\n", time.Since(start).Round(time.Millisecond))
+			return nil
+		},
+	}
+}
+
+func (c *command) newDBExportCmd() *cobra.Command {
+	const (
+		optionNamePinned      = "pinned"
+		optionNameRoot        = "root"
+		optionNameBins        = "bins"
+		optionNameStoredAfter = "stored-after"
+		optionNameAfterBinIDs = "after-bin-ids"
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export chunks to a tar file, or to standard output if the file is -",
+		Long: `Export chunks to a tar file, or to standard output if the file is -.
+
+All chunks stored before the export starts are exported unless they are
+filtered by the flags. The last bin IDs of the exported chunks are printed
+after the export, passing them to the next export with the --after-bin-ids
+flag exports only chunks that are stored since.`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.bindDBFlags,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			o := &localstore.ExportOptions{
+				Pinned: c.config.GetBool(optionNamePinned),
+			}
+			if v := c.config.GetString(optionNameRoot); v != "" {
+				o.Root, err = swarm.ParseHexAddress(v)
+				if err != nil {
+					return fmt.Errorf("%s: %w", optionNameRoot, err)
+				}
+			}
+			if v := c.config.GetString(optionNameBins); v != "" {
+				o.Bins, err = parseBinRange(v)
+				if err != nil {
+					return fmt.Errorf("%s: %w", optionNameBins, err)
+				}
+			}
+			if v := c.config.GetString(optionNameStoredAfter); v != "" {
+				t, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					return fmt.Errorf("%s: %w", optionNameStoredAfter, err)
+				}
+				o.StoredAfter = t.UnixNano()
+			}
+			if v := c.config.GetString(optionNameAfterBinIDs); v != "" {
+				o.AfterBinIDs, err = parseBinIDs(v)
+				if err != nil {
+					return fmt.Errorf("%s: %w", optionNameAfterBinIDs, err)
+				}
+			}
+
+			db, err := c.openLocalstore(cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if e := db.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+
+			var out io.Writer = cmd.OutOrStdout()
+			if args[0] != "-" {
+				f, err := os.Create(args[0])
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if e := f.Close(); e != nil && err == nil {
+						err = e
+					}
+				}()
+				out = f
+			}
+
+			// chunks stored during the export are left for the next one
+			binIDs := make(map[uint8]uint64)
+			for bin := uint8(0); bin <= swarm.MaxPO; bin++ {
+				id, err := db.LastPullSubscriptionBinID(bin)
+				if err != nil {
+					return err
+				}
+				binIDs[bin] = id
+			}
+			o.UpToBinIDs = binIDs
+
+			p := newProgress(cmd.ErrOrStderr(), "exported", 0)
+			count, err := db.Export(p.writer(out), o)
+			p.stop()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "exported %d chunks\n", count)
+			fmt.Fprintf(cmd.ErrOrStderr(), "last bin ids: %s\n", formatBinIDs(binIDs))
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(optionNamePinned, false, "export only pinned chunks")
+	cmd.Flags().String(optionNameRoot, "", "export only chunks of the tree of the root reference")
+	cmd.Flags().String(optionNameBins, "", "export only chunks in the range of proximity order bins, like 4-31")
+	cmd.Flags().String(optionNameStoredAfter, "", "export only chunks stored after the RFC 3339 time")
+	cmd.Flags().String(optionNameAfterBinIDs, "", "export only chunks with greater bin ids than the ones of their bins, like 0:120,1:84, as printed by the previous export")
+	return cmd
+}
+
+func (c *command) newDBImportCmd() *cobra.Command {
+	const optionNameMode = "mode"
+
+	cmd := &cobra.Command{
+		Use:     "import <file>",
+		Short:   "Import chunks from a tar file, or from standard input if the file is -",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.bindDBFlags,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			mode, err := parseModePut(c.config.GetString(optionNameMode))
+			if err != nil {
+				return fmt.Errorf("%s: %w", optionNameMode, err)
+			}
+
+			db, err := c.openLocalstore(cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if e := db.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+
+			in := cmd.InOrStdin()
+			var size int64
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				fi, err := f.Stat()
+				if err != nil {
+					return err
+				}
+				in = f
+				size = fi.Size()
+			}
+
+			p := newProgress(cmd.ErrOrStderr(), "imported", size)
+			count, err := db.Import(p.reader(in), mode)
+			p.stop()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "imported %d chunks\n", count)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(optionNameMode, "upload", "how chunks are stored, upload to push them to the network when the node starts, or sync to only store them")
+	return cmd
+}
+
+func (c *command) newDBNukeCmd() *cobra.Command {
+	const optionNameYes = "yes"
+
+	cmd := &cobra.Command{
+		Use:     "nuke",
+		Short:   "Remove all chunks from the local store, keeping the keys and the state store",
+		Long:    "Remove all chunks from the local store, keeping the keys and the state store. Intervals of pull syncing are removed from the state store, so that the chunks are synced again.",
+		Args:    cobra.NoArgs,
+		PreRunE: c.bindDBFlags,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			logger, err := newLogger(cmd.ErrOrStderr(), c.config.GetString(optionNameDBVerbosity))
+			if err != nil {
+				return err
+			}
+			dataDir := c.config.GetString(optionNameDBDataDir)
+
+			if !c.config.GetBool(optionNameYes) {
+				fmt.Fprintf(cmd.OutOrStdout(), "All chunks in %s will be removed. Continue? [y/N] ", filepath.Join(dataDir, "localstore"))
+				answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if err != nil && err != io.EOF {
+					return err
+				}
+				if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+					return errors.New("nuke is not confirmed")
+				}
+			}
+
+			// the base key is kept in the new local store, the
+			// database is opened to check that it is not in use
+			db, err := c.openLocalstore(cmd)
+			if err != nil {
+				return err
+			}
+			baseKey := db.BaseKey()
+			if err := db.Close(); err != nil {
+				return err
+			}
+			path := filepath.Join(dataDir, "localstore")
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+			db, err = localstore.New(path, baseKey, &localstore.Options{
+				DisableBackgroundWorkers: true,
+			}, logger)
+			if err != nil {
+				return fmt.Errorf("localstore: %w", err)
+			}
+			if err := db.Close(); err != nil {
+				return err
+			}
+
+			stateStore, err := statestore.Open("", filepath.Join(dataDir, "statestore"), logger)
+			if err != nil {
+				return fmt.Errorf("statestore: %w", err)
+			}
+			defer func() {
+				if e := stateStore.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+			if err := puller.ResetIntervals(stateStore); err != nil {
+				return fmt.Errorf("statestore: %w", err)
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "local store is cleared")
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(optionNameYes, false, "remove the chunks without confirmation")
+	return cmd
+}
+
+func (c *command) bindDBFlags(cmd *cobra.Command, args []string) error {
+	return c.config.BindPFlags(cmd.Flags())
+}
+
+// openLocalstore opens the local store in the data directory with
+// the base key and the garbage collection policy that it is stored with.
+func (c *command) openLocalstore(cmd *cobra.Command) (*localstore.DB, error) {
+	logger, err := newLogger(cmd.ErrOrStderr(), c.config.GetString(optionNameDBVerbosity))
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(c.config.GetString(optionNameDBDataDir), "localstore")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no local store in %s", path)
+		}
+		return nil, err
+	}
+	// the database is changed only by the commands, as the node
+	// capacity is not known and the reserve is not set
+	db, err := localstore.New(path, nil, &localstore.Options{
+		DisableBackgroundWorkers: true,
+	}, logger)
+	if err != nil {
+		if errors.Is(err, localstore.ErrBaseKeyUnknown) {
+			return nil, errors.New("localstore: base key is not stored, the node must be started at least once with this version")
+		}
+		return nil, fmt.Errorf("localstore: %w", err)
+	}
+	return db, nil
+}
+
+// parseBinRange parses an inclusive range of proximity
+// order bins, like 4-31, or a single bin.
+func parseBinRange(v string) (*localstore.BinRange, error) {
+	from, to := v, v
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		from, to = v[:i], v[i+1:]
+	}
+	f, err := parseBin(from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseBin(to)
+	if err != nil {
+		return nil, err
+	}
+	if f > t {
+		return nil, fmt.Errorf("invalid bin range %q", v)
+	}
+	return &localstore.BinRange{From: f, To: t}, nil
+}
+
+// parseBinIDs parses comma separated bins and bin ids, like 0:120,1:84.
+func parseBinIDs(v string) (map[uint8]uint64, error) {
+	ids := make(map[uint8]uint64)
+	for _, p := range strings.Split(v, ",") {
+		i := strings.IndexByte(p, ':')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid bin id %q", p)
+		}
+		bin, err := parseBin(p[:i])
+		if err != nil {
+			return nil, err
+		}
+		id, err := strconv.ParseUint(strings.TrimSpace(p[i+1:]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bin id %q", p)
+		}
+		ids[bin] = id
+	}
+	return ids, nil
+}
+
+// formatBinIDs returns bin ids in the format of parseBinIDs.
+func formatBinIDs(ids map[uint8]uint64) string {
+	bins := make([]int, 0, len(ids))
+	for bin := range ids {
+		bins = append(bins, int(bin))
+	}
+	sort.Ints(bins)
+	s := make([]string, len(bins))
+	for i, bin := range bins {
+		s[i] = fmt.Sprintf("%d:%d", bin, ids[uint8(bin)])
+	}
+	return strings.Join(s, ",")
+}
+
+func parseBin(v string) (uint8, error) {
+	bin, err := strconv.ParseUint(strings.TrimSpace(v), 10, 8)
+	if err != nil || bin > uint64(swarm.MaxPO) {
+		return 0, fmt.Errorf("invalid bin %q", v)
+	}
+	return uint8(bin), nil
+}
+
+// parseModePut returns the put mode of the import by its name.
+func parseModePut(v string) (storage.ModePut, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "upload":
+		return storage.ModePutUpload, nil
+	case "sync":
+		return storage.ModePutSync, nil
+	}
+	return 0, fmt.Errorf("unknown mode %q", v)
+}
+
+// progress periodically prints the number of bytes
+// read or written until it is stopped.
+type progress struct {
+	n        int64 // atomic
+	w        io.Writer
+	action   string
+	total    int64 // zero if unknown
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newProgress(w io.Writer, action string, total int64) *progress {
+	p := &progress{
+		w:      w,
+		action: action,
+		total:  total,
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.print()
+			case <-p.quit:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *progress) print() {
+	n := atomic.LoadInt64(&p.n)
+	if p.total > 0 {
+		fmt.Fprintf(p.w, "%s %d of %d bytes (%.1f%%)\n", p.action, n, p.total, float64(n)*100/float64(p.total))
+		return
+	}
+	fmt.Fprintf(p.w, "%s %d bytes\n", p.action, n)
+}
+
+func (p *progress) stop() {
+	p.stopOnce.Do(func() {
+		close(p.quit)
+		<-p.done
+	})
+}
+
+func (p *progress) reader(r io.Reader) io.Reader {
+	return progressReader{r: r, p: p}
+}
+
+func (p *progress) writer(w io.Writer) io.Writer {
+	return progressWriter{w: w, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *progress
+}
+
+func (r progressReader) Read(b []byte) (n int, err error) {
+	n, err = r.r.Read(b)
+	atomic.AddInt64(&r.p.n, int64(n))
+	return n, err
+}
+
+type progressWriter struct {
+	w io.Writer
+	p *progress
+}
+
+func (w progressWriter) Write(b []byte) (n int, err error) {
+	n, err = w.w.Write(b)
+	atomic.AddInt64(&w.p.n, int64(n))
+	return n, err
+}
@@ -0,0 +1,240 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+	"github.com/ethersphere/bee/pkg/bmtpool"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/statestore/leveldb"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/swarm/test"
+)
+
+func TestDBCmd(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "bee-db-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	// data directory of a node with stored chunks and synced intervals
+	baseKey := test.RandomAddress()
+	db, err := localstore.New(filepath.Join(dataDir, "localstore"), baseKey.Bytes(), nil, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := make([]swarm.Chunk, 10)
+	for i := range chunks {
+		chunks[i] = newTestChunk(t)
+	}
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	stateStore, err := leveldb.NewStateStore(filepath.Join(dataDir, "statestore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	intervalKey := fmt.Sprintf("%s|%d", test.RandomAddress(), 3)
+	for _, k := range []string{intervalKey, "overlay"} {
+		if err := stateStore.Put(k, "value"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stateStore.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var errOut bytes.Buffer
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+
+		var out bytes.Buffer
+		errOut.Reset()
+		err := newCommand(t,
+			cmd.WithArgs(append([]string{"db"}, append(args, "--data-dir", dataDir)...)...),
+			cmd.WithOutput(&out),
+			cmd.WithErrorOutput(&errOut),
+		).Execute()
+		return out.String(), err
+	}
+
+	t.Run("info", func(t *testing.T) {
+		out, err := run(t, "info")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"base key: " + baseKey.String(),
+			"schema: " + localstore.DbSchemaCurrent,
+			"retrievalDataIndex: 10",
+			"pushIndex: 10",
+		} {
+			if !strings.Contains(out, want+"\n") {
+				t.Errorf("output %q does not contain %q", out, want)
+			}
+		}
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"verify"},
+			{"verify", "--repair"},
+		} {
+			out, err := run(t, args...)
+			if err != nil {
+				t.Fatal(err, out)
+			}
+			if want := "verified 10 chunks: 0 invalid, 0 without data, 0 index errors\n"; out != want {
+				t.Errorf("%v: got output %q, want %q", args, out, want)
+			}
+		}
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		if _, err := run(t, "compact"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	exportFile := filepath.Join(dataDir, "export.tar")
+
+	t.Run("export", func(t *testing.T) {
+		if _, err := run(t, "export", exportFile); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(errOut.String(), "exported 10 chunks\n") {
+			t.Fatalf("got output %q", errOut.String())
+		}
+
+		// nothing is stored since the last bin ids
+		i := strings.Index(errOut.String(), "last bin ids: ")
+		if i < 0 {
+			t.Fatalf("got output %q", errOut.String())
+		}
+		binIDs := strings.TrimSpace(errOut.String()[i+len("last bin ids: "):])
+		if _, err := run(t, "export", filepath.Join(dataDir, "incremental.tar"), "--after-bin-ids", binIDs); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(errOut.String(), "exported 0 chunks\n") {
+			t.Errorf("got output %q", errOut.String())
+		}
+	})
+
+	t.Run("nuke", func(t *testing.T) {
+		// chunks are kept if the nuke is not confirmed
+		err := newCommand(t,
+			cmd.WithArgs("db", "nuke", "--data-dir", dataDir),
+			cmd.WithInput(strings.NewReader("n\n")),
+			cmd.WithOutput(ioutil.Discard),
+			cmd.WithErrorOutput(ioutil.Discard),
+		).Execute()
+		if err == nil {
+			t.Fatal("nuke is not confirmed, but there is no error")
+		}
+		out, err := run(t, "info")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(out, "retrievalDataIndex: 0\n") {
+			t.Fatal("chunks are removed without confirmation")
+		}
+
+		if _, err := run(t, "nuke", "--yes"); err != nil {
+			t.Fatal(err)
+		}
+		out, err = run(t, "info")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"base key: " + baseKey.String(),
+			"retrievalDataIndex: 0",
+		} {
+			if !strings.Contains(out, want+"\n") {
+				t.Errorf("output %q does not contain %q", out, want)
+			}
+		}
+
+		stateStore, err := leveldb.NewStateStore(filepath.Join(dataDir, "statestore"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stateStore.Close()
+		var v string
+		if err := stateStore.Get(intervalKey, &v); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("got interval error %v, want %v", err, storage.ErrNotFound)
+		}
+		if err := stateStore.Get("overlay", &v); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		if _, err := run(t, "import", exportFile, "--mode", "sync"); err != nil {
+			t.Fatal(err)
+		}
+
+		db, err := localstore.New(filepath.Join(dataDir, "localstore"), nil, nil, logging.New(ioutil.Discard, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		stats, err := db.Stats()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// chunks are not pushed to the network
+		if n := stats.Indexes["pushIndex"]; n != 0 {
+			t.Errorf("got %v chunks in push index, want 0", n)
+		}
+		for _, ch := range chunks {
+			got, err := db.Get(context.Background(), storage.ModeGetLookup, ch.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(ch) {
+				t.Errorf("got chunk %s, want %s", got.Address(), ch.Address())
+			}
+		}
+	})
+}
+
+// newTestChunk returns a random chunk with its content address.
+func newTestChunk(t *testing.T) swarm.Chunk {
+	t.Helper()
+
+	data := make([]byte, 8+swarm.ChunkSize)
+	binary.LittleEndian.PutUint64(data, swarm.ChunkSize)
+	if _, err := rand.Read(data[8:]); err != nil {
+		t.Fatal(err)
+	}
+	hasher := bmtpool.Get()
+	defer bmtpool.Put(hasher)
+	if err := hasher.SetSpan(swarm.ChunkSize); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hasher.Write(data[8:]); err != nil {
+		t.Fatal(err)
+	}
+	return swarm.NewChunk(swarm.NewAddress(hasher.Sum(nil)), data)
+}
@@ -8,14 +8,18 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/bytesize"
+	"github.com/ethersphere/bee/pkg/localstore"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/node"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -28,6 +32,12 @@ func (c *command) initStartCmd() (err error) {
 	const (
 		optionNameDataDir            = "data-dir"
 		optionNameDBCapacity         = "db-capacity"
+		optionNameDBMinFreeSpace     = "db-min-free-space"
+		optionNameDBGCPolicy         = "db-gc-policy"
+		optionNameDBGCProximity      = "db-gc-proximity-weight"
+		optionNameDBCacheCapacity    = "db-cache-capacity"
+		optionNameDBIntegrityCheck   = "db-integrity-check-interval"
+		optionNameStateStoreBackend  = "statestore-backend"
 		optionNamePassword           = "password"
 		optionNamePasswordFile       = "password-file"
 		optionNameAPIAddr            = "api-addr"
@@ -45,6 +55,19 @@ func (c *command) initStartCmd() (err error) {
 		optionNameTracingEndpoint    = "tracing-endpoint"
 		optionNameTracingServiceName = "tracing-service-name"
 		optionNameVerbosity          = "verbosity"
+		optionNameLogFormat          = "log-format"
+		optionNameENSEndpoint        = "ens-endpoint"
+		optionNameNamesFile          = "names-file"
+		optionNameResolverCacheTTL   = "resolver-cache-ttl"
+		optionNameGatewayMode        = "gateway-mode"
+		optionNameMaxUploadSize      = "gateway-max-upload-size"
+		optionNameUploadRateLimit    = "gateway-upload-rate-limit"
+		optionNameTrustedProxies     = "gateway-trusted-proxies"
+		optionNameDenylistFile       = "denylist-file"
+		optionNameRestricted         = "restricted"
+		optionNameAdminPassword      = "admin-password"
+		optionNameAPISpecValidation  = "api-spec-validation"
+		optionNameReadinessMinPeers  = "readiness-min-peers"
 	)
 
 	cmd := &cobra.Command{
@@ -55,22 +78,18 @@ func (c *command) initStartCmd() (err error) {
 				return cmd.Help()
 			}
 
-			var logger logging.Logger
-			switch v := strings.ToLower(c.config.GetString(optionNameVerbosity)); v {
-			case "0", "silent":
-				logger = logging.New(ioutil.Discard, 0)
-			case "1", "error":
-				logger = logging.New(cmd.OutOrStdout(), logrus.ErrorLevel)
-			case "2", "warn":
-				logger = logging.New(cmd.OutOrStdout(), logrus.WarnLevel)
-			case "3", "info":
-				logger = logging.New(cmd.OutOrStdout(), logrus.InfoLevel)
-			case "4", "debug":
-				logger = logging.New(cmd.OutOrStdout(), logrus.DebugLevel)
-			case "5", "trace":
-				logger = logging.New(cmd.OutOrStdout(), logrus.TraceLevel)
+			var logOptions []logging.Option
+			switch f := strings.ToLower(c.config.GetString(optionNameLogFormat)); f {
+			case "text":
+			case "json":
+				logOptions = append(logOptions, logging.WithJSONFormat())
 			default:
-				return fmt.Errorf("unknown verbosity level %q", v)
+				return fmt.Errorf("unknown log format %q", f)
+			}
+
+			logger, err := newLogger(cmd.OutOrStdout(), c.config.GetString(optionNameVerbosity), logOptions...)
+			if err != nil {
+				return err
 			}
 			bee := `
 Welcome to the Swarm.... Bzzz Bzzzz Bzzzz
@@ -111,24 +130,58 @@ Welcome to the Swarm.... Bzzz Bzzzz Bzzzz
 				password = p
 			}
 
+			dbCapacity, err := parseDBCapacity(c.config.GetString(optionNameDBCapacity), logger)
+			if err != nil {
+				return fmt.Errorf("%s: %w", optionNameDBCapacity, err)
+			}
+			dbMinFreeSpace, err := bytesize.Parse(c.config.GetString(optionNameDBMinFreeSpace))
+			if err != nil {
+				return fmt.Errorf("%s: %w", optionNameDBMinFreeSpace, err)
+			}
+			dbGCPolicy, err := parseGCPolicy(c.config.GetString(optionNameDBGCPolicy), c.config.GetDuration(optionNameDBGCProximity))
+			if err != nil {
+				return fmt.Errorf("%s: %w", optionNameDBGCPolicy, err)
+			}
+			dbCacheCapacity, err := bytesize.Parse(c.config.GetString(optionNameDBCacheCapacity))
+			if err != nil {
+				return fmt.Errorf("%s: %w", optionNameDBCacheCapacity, err)
+			}
+
 			b, err := node.NewBee(node.Options{
-				DataDir:            c.config.GetString(optionNameDataDir),
-				DBCapacity:         c.config.GetUint64(optionNameDBCapacity),
-				Password:           password,
-				APIAddr:            c.config.GetString(optionNameAPIAddr),
-				DebugAPIAddr:       debugAPIAddr,
-				Addr:               c.config.GetString(optionNameP2PAddr),
-				NATAddr:            c.config.GetString(optionNameNATAddr),
-				EnableWS:           c.config.GetBool(optionNameP2PWSEnable),
-				EnableQUIC:         c.config.GetBool(optionNameP2PQUICEnable),
-				NetworkID:          c.config.GetUint64(optionNameNetworkID),
-				WelcomeMessage:     c.config.GetString(optionWelcomeMessage),
-				Bootnodes:          c.config.GetStringSlice(optionNameBootnodes),
-				CORSAllowedOrigins: c.config.GetStringSlice(optionCORSAllowedOrigins),
-				TracingEnabled:     c.config.GetBool(optionNameTracingEnabled),
-				TracingEndpoint:    c.config.GetString(optionNameTracingEndpoint),
-				TracingServiceName: c.config.GetString(optionNameTracingServiceName),
-				Logger:             logger,
+				DataDir:                  c.config.GetString(optionNameDataDir),
+				DBCapacity:               dbCapacity,
+				DBMinFreeSpace:           dbMinFreeSpace,
+				DBGCPolicy:               dbGCPolicy,
+				DBCacheCapacity:          dbCacheCapacity,
+				DBIntegrityCheckInterval: c.config.GetDuration(optionNameDBIntegrityCheck),
+				StateStoreBackend:        c.config.GetString(optionNameStateStoreBackend),
+				Password:                 password,
+				APIAddr:                  c.config.GetString(optionNameAPIAddr),
+				DebugAPIAddr:             debugAPIAddr,
+				Addr:                     c.config.GetString(optionNameP2PAddr),
+				NATAddr:                  c.config.GetString(optionNameNATAddr),
+				EnableWS:                 c.config.GetBool(optionNameP2PWSEnable),
+				EnableQUIC:               c.config.GetBool(optionNameP2PQUICEnable),
+				NetworkID:                c.config.GetUint64(optionNameNetworkID),
+				WelcomeMessage:           c.config.GetString(optionWelcomeMessage),
+				Bootnodes:                c.config.GetStringSlice(optionNameBootnodes),
+				CORSAllowedOrigins:       c.config.GetStringSlice(optionCORSAllowedOrigins),
+				TracingEnabled:           c.config.GetBool(optionNameTracingEnabled),
+				TracingEndpoint:          c.config.GetString(optionNameTracingEndpoint),
+				TracingServiceName:       c.config.GetString(optionNameTracingServiceName),
+				ENSEndpoint:              c.config.GetString(optionNameENSEndpoint),
+				NamesFile:                c.config.GetString(optionNameNamesFile),
+				ResolverCacheTTL:         c.config.GetDuration(optionNameResolverCacheTTL),
+				GatewayMode:              c.config.GetBool(optionNameGatewayMode),
+				MaxUploadSize:            c.config.GetInt64(optionNameMaxUploadSize),
+				UploadRateLimit:          c.config.GetInt(optionNameUploadRateLimit),
+				TrustedProxies:           c.config.GetStringSlice(optionNameTrustedProxies),
+				DenylistFile:             c.config.GetString(optionNameDenylistFile),
+				Restricted:               c.config.GetBool(optionNameRestricted),
+				AdminPassword:            c.config.GetString(optionNameAdminPassword),
+				APISpecValidation:        c.config.GetBool(optionNameAPISpecValidation),
+				ReadinessMinPeers:        c.config.GetInt(optionNameReadinessMinPeers),
+				Logger:                   logger,
 			})
 			if err != nil {
 				return err
@@ -174,7 +227,13 @@ Welcome to the Swarm.... Bzzz Bzzzz Bzzzz
 	}
 
 	cmd.Flags().String(optionNameDataDir, filepath.Join(c.homeDir, ".bee"), "data directory")
-	cmd.Flags().Uint64(optionNameDBCapacity, 5000000, fmt.Sprintf("db capacity in chunks, multiply by %d to get approximate capacity in bytes", swarm.ChunkSize))
+	cmd.Flags().String(optionNameDBCapacity, "20GB", "db capacity with a unit, like 20GB or 512MiB, of the disk usage measured every minute, including pinned chunks and index overhead, a number without a unit is a deprecated capacity in chunks")
+	cmd.Flags().String(optionNameDBMinFreeSpace, "1GB", "free disk space below which uploads are refused and garbage collection frees space, 0 disables the check")
+	cmd.Flags().String(optionNameDBGCPolicy, "lru", "order in which chunks are garbage collected, lru, lfu or proximity, changing it rebuilds the garbage collection index on start")
+	cmd.Flags().Duration(optionNameDBGCProximity, time.Hour, "time for which every proximity order keeps a chunk longer with the proximity garbage collection policy")
+	cmd.Flags().String(optionNameDBCacheCapacity, "0", "size of the in-memory cache of requested chunks with a unit, like 64MB, 0 disables the cache")
+	cmd.Flags().Duration(optionNameDBIntegrityCheck, 0, "interval at which db indexes are verified and repaired in the background, 0 disables the check")
+	cmd.Flags().String(optionNameStateStoreBackend, "", "state store backend of a new state store, leveldb or journal, an existing state store is converted with statestore dump and restore")
 	cmd.Flags().String(optionNamePassword, "", "password for decrypting keys")
 	cmd.Flags().String(optionNamePasswordFile, "", "path to a file that contains password for decrypting keys")
 	cmd.Flags().String(optionNameAPIAddr, ":8080", "HTTP API listen address")
@@ -191,8 +250,69 @@ Welcome to the Swarm.... Bzzz Bzzzz Bzzzz
 	cmd.Flags().String(optionNameTracingEndpoint, "127.0.0.1:6831", "endpoint to send tracing data")
 	cmd.Flags().String(optionNameTracingServiceName, "bee", "service name identifier for tracing")
 	cmd.Flags().String(optionNameVerbosity, "info", "log verbosity level 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=trace")
+	cmd.Flags().String(optionNameLogFormat, "text", "log message format, text or json")
 	cmd.Flags().String(optionWelcomeMessage, "", "send a welcome message string during handshakes")
+	cmd.Flags().String(optionNameENSEndpoint, "", "Ethereum JSON-RPC endpoint for resolving ENS names in API routes")
+	cmd.Flags().String(optionNameNamesFile, "", "path to a JSON file with names and references for resolving names in API routes")
+	cmd.Flags().Duration(optionNameResolverCacheTTL, 5*time.Minute, "time to keep resolved names in cache, 0 disables the cache")
+	cmd.Flags().Bool(optionNameGatewayMode, false, "run the HTTP API as a public gateway with disabled chunk uploads and restricted file and bytes uploads")
+	cmd.Flags().Int64(optionNameMaxUploadSize, 0, "maximum upload size in bytes in gateway mode, 0 disables the limit")
+	cmd.Flags().Int(optionNameUploadRateLimit, 0, "maximum number of uploads per client IP per minute in gateway mode, 0 disables the limit")
+	cmd.Flags().StringSlice(optionNameTrustedProxies, nil, "IP addresses or CIDR networks of reverse proxies whose X-Forwarded-For and X-Real-IP headers are used to get the client IP in gateway mode")
+	cmd.Flags().Bool(optionNameRestricted, false, "require scoped bearer tokens for the HTTP API and debug HTTP API")
+	cmd.Flags().String(optionNameAdminPassword, "", "password for issuing and revoking HTTP API tokens in restricted mode")
+	cmd.Flags().Bool(optionNameAPISpecValidation, false, "validate HTTP API and debug HTTP API requests and responses against the OpenAPI specification, rejecting invalid requests")
+	cmd.Flags().String(optionNameDenylistFile, "", "path to a file with references, one per line, that are not served by the HTTP API")
+	cmd.Flags().Int(optionNameReadinessMinPeers, 1, "minimal number of connected peers for the node to be reported as ready")
 
 	c.root.AddCommand(cmd)
 	return nil
 }
+
+// parseDBCapacity returns the db capacity in bytes. A number without a unit
+// is a capacity in chunks, as it was before units were supported.
+func parseDBCapacity(v string, logger logging.Logger) (uint64, error) {
+	if chunks, err := strconv.ParseUint(strings.TrimSpace(v), 10, 64); err == nil {
+		logger.Warningf("db capacity without a unit is deprecated, using %d chunks of %d bytes", chunks, swarm.ChunkSize)
+		return chunks * swarm.ChunkSize, nil
+	}
+	return bytesize.Parse(v)
+}
+
+// parseGCPolicy returns the localstore garbage collection policy by its name.
+func parseGCPolicy(name string, proximityWeight time.Duration) (localstore.GCPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "lru":
+		return localstore.NewLRUPolicy(), nil
+	case "lfu":
+		return localstore.NewLFUPolicy(), nil
+	case "proximity":
+		if proximityWeight <= 0 {
+			return nil, fmt.Errorf("invalid proximity weight %v", proximityWeight)
+		}
+		return localstore.NewProximityPolicy(proximityWeight), nil
+	}
+	return nil, fmt.Errorf("unknown policy %q", name)
+}
+
+// newLogger returns a logger with the verbosity level
+// given by its number or name.
+func newLogger(w io.Writer, verbosity string, opts ...logging.Option) (logging.Logger, error) {
+	switch v := strings.ToLower(verbosity); v {
+	case "0", "silent":
+		// the output is kept for levels raised at runtime
+		return logging.New(w, 0, opts...), nil
+	case "1", "error":
+		return logging.New(w, logrus.ErrorLevel, opts...), nil
+	case "2", "warn":
+		return logging.New(w, logrus.WarnLevel, opts...), nil
+	case "3", "info":
+		return logging.New(w, logrus.InfoLevel, opts...), nil
+	case "4", "debug":
+		return logging.New(w, logrus.DebugLevel, opts...), nil
+	case "5", "trace":
+		return logging.New(w, logrus.TraceLevel, opts...), nil
+	default:
+		return nil, fmt.Errorf("unknown verbosity level %q", v)
+	}
+}
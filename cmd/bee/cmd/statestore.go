@@ -0,0 +1,241 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethersphere/bee/pkg/statestore"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+const optionNameStateStoreNamespace = "namespace"
+
+// stateStoreEntry is a line of the state store dump.
+type stateStoreEntry struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+func (c *command) initStateStoreCmd() {
+	cmd := &cobra.Command{
+		Use:   "statestore",
+		Short: "Maintain the state store of a node that is not running",
+	}
+	cmd.PersistentFlags().String(optionNameDBDataDir, filepath.Join(c.homeDir, ".bee"), "data directory")
+	cmd.PersistentFlags().String(optionNameDBVerbosity, "warn", "log verbosity level 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=trace")
+
+	cmd.AddCommand(
+		c.newStateStoreDumpCmd(),
+		c.newStateStoreRestoreCmd(),
+	)
+
+	c.root.AddCommand(cmd)
+}
+
+func (c *command) newStateStoreDumpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump <file>",
+		Short: "Dump state store entries to a file, or to standard output if the file is -",
+		Long: `Dump state store entries to a file, or to standard output if the file is -.
+
+Every line of the dump is a JSON object with the key and the base64 encoded
+value of an entry. All entries are dumped unless the --namespace flag selects
+the entries of a component.`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.bindDBFlags,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			logger, err := newLogger(cmd.ErrOrStderr(), c.config.GetString(optionNameDBVerbosity))
+			if err != nil {
+				return err
+			}
+			prefix, err := stateStoreNamespacePrefix(c.config.GetString(optionNameStateStoreNamespace))
+			if err != nil {
+				return err
+			}
+
+			dir := filepath.Join(c.config.GetString(optionNameDBDataDir), "statestore")
+			backend, err := statestore.DetectBackend(dir)
+			if err != nil {
+				return err
+			}
+			if backend == "" {
+				return fmt.Errorf("no state store in %s", dir)
+			}
+			s, err := statestore.Open(backend, dir, logger)
+			if err != nil {
+				return fmt.Errorf("statestore: %w", err)
+			}
+			defer func() {
+				if e := s.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+
+			var out io.Writer = cmd.OutOrStdout()
+			if args[0] != "-" {
+				f, err := os.Create(args[0])
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if e := f.Close(); e != nil && err == nil {
+						err = e
+					}
+				}()
+				out = f
+			}
+
+			count, err := dumpStateStore(out, s, prefix)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "dumped %d entries\n", count)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(optionNameStateStoreNamespace, "", "dump only entries of the namespace, "+stateStoreNamespaceNames())
+	return cmd
+}
+
+func (c *command) newStateStoreRestoreCmd() *cobra.Command {
+	const optionNameBackend = "backend"
+
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Restore state store entries from a dump file, or from standard input if the file is -",
+		Long: `Restore state store entries from a dump file, or from standard input if the file is -.
+
+All entries are restored in a single transaction, replacing the values of
+existing keys. A new state store is created with the backend of the --backend
+flag if there is none. To change the backend of a state store, dump it, move
+its directory away and restore the dump with the new backend.`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.bindDBFlags,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			logger, err := newLogger(cmd.ErrOrStderr(), c.config.GetString(optionNameDBVerbosity))
+			if err != nil {
+				return err
+			}
+			in := cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+
+			dir := filepath.Join(c.config.GetString(optionNameDBDataDir), "statestore")
+			s, err := statestore.Open(c.config.GetString(optionNameBackend), dir, logger)
+			if err != nil {
+				return fmt.Errorf("statestore: %w", err)
+			}
+			defer func() {
+				if e := s.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+
+			count, err := restoreStateStore(in, s)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "restored %d entries\n", count)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(optionNameBackend, "", "backend of a new state store, leveldb or journal, the backend of the existing state store if empty")
+	return cmd
+}
+
+// dumpStateStore writes the entries with keys of the prefix
+// to the writer, and returns the number of written entries.
+func dumpStateStore(w io.Writer, s storage.StateStorer, prefix string) (count int, err error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	err = s.Iterate(prefix, func(key, value []byte) (stop bool, err error) {
+		if !utf8.Valid(key) {
+			return true, fmt.Errorf("key %x is not valid utf-8", key)
+		}
+		if err := enc.Encode(stateStoreEntry{Key: string(key), Value: value}); err != nil {
+			return true, err
+		}
+		count++
+		return false, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// restoreStateStore puts entries read from the reader in a single
+// transaction, and returns the number of restored entries.
+func restoreStateStore(r io.Reader, s storage.StateStorer) (count int, err error) {
+	dec := json.NewDecoder(r)
+	var entries []stateStoreEntry
+	for {
+		var e stateStoreEntry
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return 0, fmt.Errorf("entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, e)
+	}
+
+	err = s.Update(func(tx storage.StateTx) error {
+		for _, e := range entries {
+			if err := tx.Put(e.Key, statestore.RawValue(e.Value)); err != nil {
+				return fmt.Errorf("key %q: %w", e.Key, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// stateStoreNamespacePrefix returns the key prefix of the namespace
+// name, or an empty prefix if the name is empty.
+func stateStoreNamespacePrefix(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	ns, ok := statestore.Namespaces[name]
+	if !ok {
+		return "", fmt.Errorf("%s: unknown namespace %q, want %s", optionNameStateStoreNamespace, name, stateStoreNamespaceNames())
+	}
+	return string(ns), nil
+}
+
+// stateStoreNamespaceNames returns names of all namespaces.
+func stateStoreNamespaceNames() string {
+	names := make([]string, 0, len(statestore.Namespaces))
+	for name := range statestore.Namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
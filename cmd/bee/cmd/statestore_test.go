@@ -0,0 +1,123 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+	"github.com/ethersphere/bee/pkg/intervalstore"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/statestore"
+)
+
+func TestStateStoreCmd(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "bee-statestore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	dir := filepath.Join(dataDir, "statestore")
+	s, err := statestore.Open(statestore.BackendLevelDB, dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	intervals := intervalstore.NewIntervals(1)
+	intervals.Add(1, 10)
+	values := map[string]interface{}{
+		string(statestore.AddressbookNamespace) + "overlay": "address",
+		string(statestore.PullerNamespace) + "interval":     intervals,
+		"overlay": "value",
+	}
+	for k, v := range values {
+		if err := s.Put(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(t *testing.T, args ...string) (string, string, error) {
+		t.Helper()
+
+		var out, errOut bytes.Buffer
+		err := newCommand(t,
+			cmd.WithArgs(append([]string{"statestore"}, append(args, "--data-dir", dataDir)...)...),
+			cmd.WithOutput(&out),
+			cmd.WithErrorOutput(&errOut),
+		).Execute()
+		return out.String(), errOut.String(), err
+	}
+
+	t.Run("dump namespace", func(t *testing.T) {
+		out, errOut, err := run(t, "dump", "-", "--namespace", "addressbook")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Count(out, "\n") != 1 || !strings.Contains(out, `"key":"addressbook_entry_overlay"`) {
+			t.Errorf("got dump %q, want the addressbook entry", out)
+		}
+		if !strings.Contains(errOut, "dumped 1 entries") {
+			t.Errorf("got output %q", errOut)
+		}
+
+		if _, _, err := run(t, "dump", "-", "--namespace", "unknown"); err == nil {
+			t.Error("dumped an unknown namespace")
+		}
+	})
+
+	t.Run("change backend", func(t *testing.T) {
+		dump := filepath.Join(dataDir, "dump")
+		if _, errOut, err := run(t, "dump", dump); err != nil {
+			t.Fatal(err)
+		} else if !strings.Contains(errOut, "dumped 3 entries") {
+			t.Errorf("got output %q", errOut)
+		}
+
+		if err := os.Rename(dir, dir+".old"); err != nil {
+			t.Fatal(err)
+		}
+		if _, errOut, err := run(t, "restore", dump, "--backend", statestore.BackendJournal); err != nil {
+			t.Fatal(err)
+		} else if !strings.Contains(errOut, "restored 3 entries") {
+			t.Errorf("got output %q", errOut)
+		}
+
+		backend, err := statestore.DetectBackend(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if backend != statestore.BackendJournal {
+			t.Errorf("got backend %q, want %q", backend, statestore.BackendJournal)
+		}
+
+		s, err := statestore.Open("", dir, logging.New(ioutil.Discard, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		var v string
+		if err := s.Get("overlay", &v); err != nil {
+			t.Fatal(err)
+		}
+		if v != "value" {
+			t.Errorf("got value %q, want %q", v, "value")
+		}
+		i := &intervalstore.Intervals{}
+		if err := s.Get(string(statestore.PullerNamespace)+"interval", i); err != nil {
+			t.Fatal(err)
+		}
+		if i.String() != intervals.String() {
+			t.Errorf("got intervals %s, want %s", i, intervals)
+		}
+	})
+}
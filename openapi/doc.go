@@ -0,0 +1,12 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package openapi provides the OpenAPI documents of the HTTP APIs,
+// so that they can be served by the node.
+//
+// The documents are generated from the YAML files in this directory
+// and they have to be regenerated with go generate after any change.
+package openapi
+
+//go:generate go run gen.go
@@ -0,0 +1,54 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+// +build ignore
+
+// This program generates specs.go with the contents of the OpenAPI documents.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+var documents = []struct {
+	name string
+	file string
+}{
+	{name: "Swarm", file: "Swarm.yaml"},
+	{name: "SwarmCommon", file: "SwarmCommon.yaml"},
+	{name: "SwarmDebug", file: "SwarmDebug.yaml"},
+}
+
+func main() {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by go generate; DO NOT EDIT.\n\n")
+	b.WriteString("package openapi\n\n")
+	b.WriteString("const (\n")
+	for _, d := range documents {
+		data, err := ioutil.ReadFile(d.file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if bytes.ContainsRune(data, '`') {
+			log.Fatalf("%s: backquotes are not supported", d.file)
+		}
+		fmt.Fprintf(&b, "\t// %s is the content of %s.\n", d.name, d.file)
+		fmt.Fprintf(&b, "\t%s = `%s`\n\n", d.name, strings.ReplaceAll(string(data), "\r", ""))
+	}
+	b.WriteString(")\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("specs.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
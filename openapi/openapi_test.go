@@ -0,0 +1,30 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/ethersphere/bee/openapi"
+)
+
+// TestGenerated checks that the generated documents are up to date
+// with the YAML files.
+func TestGenerated(t *testing.T) {
+	for file, content := range map[string]string{
+		"Swarm.yaml":       openapi.Swarm,
+		"SwarmCommon.yaml": openapi.SwarmCommon,
+		"SwarmDebug.yaml":  openapi.SwarmDebug,
+	} {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s has changed, run go generate in the openapi directory", file)
+		}
+	}
+}
@@ -6,16 +6,13 @@ package addressbook
 
 import (
 	"errors"
-	"fmt"
-	"strings"
 
 	"github.com/ethersphere/bee/pkg/bzz"
+	"github.com/ethersphere/bee/pkg/statestore"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
-const keyPrefix = "addressbook_entry_"
-
 var _ Interface = (*store)(nil)
 
 var ErrNotFound = errors.New("addressbook: not found")
@@ -48,14 +45,16 @@ type store struct {
 	store storage.StateStorer
 }
 
+// New returns the addressbook that keeps entries in
+// the addressbook namespace of the state store.
 func New(storer storage.StateStorer) Interface {
 	return &store{
-		store: storer,
+		store: statestore.WithNamespace(storer, statestore.AddressbookNamespace),
 	}
 }
 
 func (s *store) Get(overlay swarm.Address) (*bzz.Address, error) {
-	key := keyPrefix + overlay.String()
+	key := overlay.String()
 	v := &bzz.Address{}
 	err := s.store.Get(key, &v)
 	if err != nil {
@@ -69,25 +68,17 @@ func (s *store) Get(overlay swarm.Address) (*bzz.Address, error) {
 }
 
 func (s *store) Put(overlay swarm.Address, addr bzz.Address) (err error) {
-	key := keyPrefix + overlay.String()
+	key := overlay.String()
 	return s.store.Put(key, &addr)
 }
 
 func (s *store) Remove(overlay swarm.Address) error {
-	return s.store.Delete(keyPrefix + overlay.String())
+	return s.store.Delete(overlay.String())
 }
 
 func (s *store) Overlays() (overlays []swarm.Address, err error) {
-	err = s.store.Iterate(keyPrefix, func(key, _ []byte) (stop bool, err error) {
-		k := string(key)
-		if !strings.HasPrefix(k, keyPrefix) {
-			return true, nil
-		}
-		split := strings.SplitAfter(k, keyPrefix)
-		if len(split) != 2 {
-			return true, fmt.Errorf("invalid overlay key: %s", k)
-		}
-		addr, err := swarm.ParseHexAddress(split[1])
+	err = s.store.Iterate("", func(key, _ []byte) (stop bool, err error) {
+		addr, err := swarm.ParseHexAddress(string(key))
 		if err != nil {
 			return true, err
 		}
@@ -102,7 +93,7 @@ func (s *store) Overlays() (overlays []swarm.Address, err error) {
 }
 
 func (s *store) Addresses() (addresses []bzz.Address, err error) {
-	err = s.store.Iterate(keyPrefix, func(_, value []byte) (stop bool, err error) {
+	err = s.store.Iterate("", func(_, value []byte) (stop bool, err error) {
 		entry := &bzz.Address{}
 		err = entry.UnmarshalJSON(value)
 		if err != nil {
@@ -5,13 +5,23 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 
+	"github.com/ethersphere/bee/pkg/apispec"
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/logging"
 	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/ethersphere/bee/pkg/resolver"
 	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
 	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
 )
 
 type Service interface {
@@ -22,24 +32,143 @@ type Service interface {
 type server struct {
 	Options
 	http.Handler
-	metrics metrics
+	router   *mux.Router
+	limiter  *rateLimiter
+	denylist map[string]struct{}
+	specs    map[*apiVersion]*apispec.Spec
+	metrics  metrics
 }
 
 type Options struct {
 	Tags               *tags.Tags
 	Storer             storage.Storer
+	Resolver           resolver.Interface
 	CORSAllowedOrigins []string
 	Logger             logging.Logger
 	Tracer             *tracing.Tracer
+
+	// Authenticator authorizes requests with bearer tokens.
+	// If it is nil, the API is not protected.
+	Authenticator *auth.Authenticator
+
+	// GatewayMode disables chunk uploads and restricts other uploads
+	// with MaxUploadSize and UploadRateLimit.
+	GatewayMode bool
+	// MaxUploadSize is the maximum size of an upload request body in
+	// bytes in gateway mode. Zero means no limit.
+	MaxUploadSize int64
+	// UploadRateLimit is the maximum number of uploads per client IP
+	// per minute in gateway mode. Zero means no limit.
+	UploadRateLimit int
+	// TrustedProxies are networks of reverse proxies whose
+	// X-Forwarded-For and X-Real-IP headers are used to get the
+	// client IP for UploadRateLimit.
+	TrustedProxies []*net.IPNet
+	// Denylist contains references that are not served, responding
+	// with the 451 status code instead.
+	Denylist []swarm.Address
+
+	// ValidateSpec enables validation of requests and responses against
+	// the OpenAPI document of the requested API version. Invalid requests
+	// are rejected and invalid responses are logged.
+	ValidateSpec bool
 }
 
 func New(o Options) Service {
 	s := &server{
-		Options: o,
-		metrics: newMetrics(),
+		Options:  o,
+		denylist: make(map[string]struct{}, len(o.Denylist)),
+		metrics:  newMetrics(),
+	}
+	if o.GatewayMode && o.UploadRateLimit > 0 {
+		s.limiter = newRateLimiter(o.UploadRateLimit, rateLimitWindow)
+	}
+	for _, addr := range o.Denylist {
+		s.denylist[addr.String()] = struct{}{}
+	}
+	if o.ValidateSpec {
+		s.specs = make(map[*apiVersion]*apispec.Spec, len(apiVersions))
+		for _, v := range apiVersions {
+			spec, err := v.spec()
+			if err != nil {
+				s.Logger.Debugf("api: load openapi document of version %s: %v", v.name, err)
+				s.Logger.Errorf("api: spec validation disabled for version %s", v.name)
+				continue
+			}
+			s.specs[v] = spec
+		}
 	}
 
 	s.setupRouting()
 
 	return s
 }
+
+var (
+	errInvalidNameOrAddress = errors.New("invalid name or address")
+	errNameResolution       = errors.New("name resolution")
+	errResolverUnavailable  = errors.New("name resolver unavailable")
+	errDenied               = errors.New("denied reference")
+)
+
+// resolveNameOrAddress parses the hex encoded address or, if the string is
+// not a hex encoded address and a resolver is configured, resolves it as a
+// name to a reference.
+//
+// It returns errInvalidNameOrAddress if the string is not an address and no
+// resolver is configured, resolver.ErrNotFound if the name is not found,
+// errResolverUnavailable if the resolver could not reach its service and
+// errNameResolution if the name could not be resolved for other reasons. If
+// the resulting reference is in the denylist, errDenied is returned.
+func (s *server) resolveNameOrAddress(ctx context.Context, str string) (swarm.Address, error) {
+	addr, err := swarm.ParseHexAddress(str)
+	if err != nil {
+		if s.Resolver == nil {
+			return swarm.ZeroAddress, fmt.Errorf("%w: %v", errInvalidNameOrAddress, err)
+		}
+		addr, err = s.Resolver.Resolve(ctx, str)
+		if err != nil {
+			if errors.Is(err, resolver.ErrNotFound) {
+				return swarm.ZeroAddress, err
+			}
+			if errors.Is(err, resolver.ErrUnavailable) || errors.Is(err, context.DeadlineExceeded) {
+				return swarm.ZeroAddress, fmt.Errorf("%w: %v", errResolverUnavailable, err)
+			}
+			return swarm.ZeroAddress, fmt.Errorf("%w: %v", errNameResolution, err)
+		}
+	}
+	if s.denied(addr) {
+		return swarm.ZeroAddress, errDenied
+	}
+	return addr, nil
+}
+
+// resolveRequestAddress resolves the name or address of the request with
+// resolveNameOrAddress. If that fails, the error response is written with
+// the invalid message for strings that are neither names nor addresses,
+// and false is returned. The handler name prefixes the log messages.
+func (s *server) resolveRequestAddress(w http.ResponseWriter, r *http.Request, str, handler, invalidMessage string) (swarm.Address, bool) {
+	addr, err := s.resolveNameOrAddress(r.Context(), str)
+	if err == nil {
+		return addr, true
+	}
+	s.Logger.Debugf("%s: resolve address %s: %v", handler, str, err)
+	switch {
+	case errors.Is(err, resolver.ErrNotFound):
+		s.Logger.Errorf("%s: name %s not found", handler, str)
+		jsonhttp.NotFound(w, jsonhttp.Error(jsonhttp.ReasonNameNotFound, "name not found"))
+	case errors.Is(err, errDenied):
+		s.Logger.Errorf("%s: denied reference %s", handler, str)
+		jsonhttp.UnavailableForLegalReasons(w, jsonhttp.Error(jsonhttp.ReasonDeniedReference, "denied reference"))
+	case errors.Is(err, errResolverUnavailable):
+		s.Logger.Errorf("%s: resolve name %s: resolver unavailable", handler, str)
+		jsonhttp.ServiceUnavailable(w, jsonhttp.Error(jsonhttp.ReasonNameNotResolved, "name resolver unavailable"))
+	case errors.Is(err, errNameResolution):
+		s.Logger.Errorf("%s: resolve name %s", handler, str)
+		jsonhttp.BadGateway(w, jsonhttp.Error(jsonhttp.ReasonNameNotResolved, "name not resolved"))
+	default:
+		s.Logger.Errorf("%s: parse address %s", handler, str)
+		jsonhttp.BadRequest(w, jsonhttp.Error(jsonhttp.ReasonInvalidAddress, invalidMessage))
+	}
+	return swarm.ZeroAddress, false
+}
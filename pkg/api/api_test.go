@@ -6,24 +6,37 @@ package api_test
 
 import (
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/pingpong"
+	"github.com/ethersphere/bee/pkg/resolver"
 	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
 	"resenje.org/web"
 )
 
 type testServerOptions struct {
-	Pingpong pingpong.Interface
-	Storer   storage.Storer
-	Tags     *tags.Tags
-	Logger   logging.Logger
+	Pingpong        pingpong.Interface
+	Storer          storage.Storer
+	Resolver        resolver.Interface
+	Tags            *tags.Tags
+	Logger          logging.Logger
+	GatewayMode     bool
+	MaxUploadSize   int64
+	UploadRateLimit int
+	TrustedProxies  []*net.IPNet
+	Denylist        []swarm.Address
+	Authenticator   *auth.Authenticator
+	ValidateSpec    bool
 }
 
 func newTestServer(t *testing.T, o testServerOptions) *http.Client {
@@ -31,14 +44,22 @@ func newTestServer(t *testing.T, o testServerOptions) *http.Client {
 		o.Logger = logging.New(ioutil.Discard, 0)
 	}
 	s := api.New(api.Options{
-		Tags:   o.Tags,
-		Storer: o.Storer,
-		Logger: o.Logger,
+		Tags:            o.Tags,
+		Storer:          o.Storer,
+		Resolver:        o.Resolver,
+		Logger:          o.Logger,
+		GatewayMode:     o.GatewayMode,
+		MaxUploadSize:   o.MaxUploadSize,
+		UploadRateLimit: o.UploadRateLimit,
+		TrustedProxies:  o.TrustedProxies,
+		Denylist:        o.Denylist,
+		Authenticator:   o.Authenticator,
+		ValidateSpec:    o.ValidateSpec,
 	})
 	ts := httptest.NewServer(s)
 	t.Cleanup(ts.Close)
 
-	return &http.Client{
+	client := &http.Client{
 		Transport: web.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
 			u, err := url.Parse(ts.URL + r.URL.String())
 			if err != nil {
@@ -48,4 +69,11 @@ func newTestServer(t *testing.T, o testServerOptions) *http.Client {
 			return ts.Client().Transport.RoundTrip(r)
 		}),
 	}
+
+	// all responses are validated against the api spec
+	specs, err := api.VersionSpecs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jsonhttptest.WithSpecValidation(t, client, specs[api.LatestVersion()])
 }
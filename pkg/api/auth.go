@@ -0,0 +1,125 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+type authRequest struct {
+	Scopes []auth.Scope `json:"scopes"`
+	// Expiry is the token lifetime in seconds.
+	Expiry int64 `json:"expiry"`
+}
+
+type authResponse struct {
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires"`
+}
+
+type revokeRequest struct {
+	Key string `json:"key"`
+}
+
+// authHandler issues a new token in exchange for the password
+// provided with the basic authentication scheme.
+func (s *server) authHandler(w http.ResponseWriter, r *http.Request) {
+	_, password, ok := r.BasicAuth()
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="bee"`)
+		jsonhttp.Unauthorized(w, "password required")
+		return
+	}
+
+	var req authRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.Logger.Debugf("auth: decode request: %v", err)
+		s.Logger.Error("auth: decode request")
+		jsonhttp.BadRequest(w, "invalid request")
+		return
+	}
+
+	key, expires, err := s.Authenticator.Issue(password, req.Scopes, time.Duration(req.Expiry)*time.Second)
+	if err != nil {
+		s.Logger.Debugf("auth: issue token: %v", err)
+		switch {
+		case errors.Is(err, auth.ErrInvalidPassword):
+			s.Logger.Error("auth: invalid password")
+			jsonhttp.Unauthorized(w, "invalid password")
+		case errors.Is(err, auth.ErrInvalidScope):
+			s.Logger.Error("auth: invalid scope")
+			jsonhttp.BadRequest(w, "invalid scope")
+		default:
+			s.Logger.Error("auth: issue token")
+			jsonhttp.InternalServerError(w, nil)
+		}
+		return
+	}
+
+	jsonhttp.Created(w, authResponse{
+		Key:     key,
+		Expires: expires,
+	})
+}
+
+// authRevokeHandler revokes the token from the request body if the
+// password is provided with the basic authentication scheme.
+func (s *server) authRevokeHandler(w http.ResponseWriter, r *http.Request) {
+	_, password, ok := r.BasicAuth()
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="bee"`)
+		jsonhttp.Unauthorized(w, "password required")
+		return
+	}
+
+	var req revokeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.Logger.Debugf("auth revoke: decode request: %v", err)
+		s.Logger.Error("auth revoke: decode request")
+		jsonhttp.BadRequest(w, "invalid request")
+		return
+	}
+
+	if err := s.Authenticator.Revoke(password, req.Key); err != nil {
+		s.Logger.Debugf("auth revoke: %v", err)
+		switch {
+		case errors.Is(err, auth.ErrInvalidPassword):
+			s.Logger.Error("auth revoke: invalid password")
+			jsonhttp.Unauthorized(w, "invalid password")
+		case errors.Is(err, auth.ErrUnauthorized):
+			s.Logger.Error("auth revoke: unknown token")
+			jsonhttp.NotFound(w, "token not found")
+		default:
+			s.Logger.Error("auth revoke: revoke token")
+			jsonhttp.InternalServerError(w, nil)
+		}
+		return
+	}
+
+	jsonhttp.OK(w, nil)
+}
+
+// authScope returns the scope that is required to access the resource
+// of the request. Index, robots.txt, OpenAPI documents and the
+// authentication resources are not protected.
+func authScope(r *http.Request) auth.Scope {
+	_, path := pathVersion(r.URL.Path)
+	switch {
+	case r.Method == http.MethodOptions,
+		path == "/", path == "/robots.txt", strings.HasSuffix(path, ".yaml"),
+		path == "/auth", strings.HasPrefix(path, "/auth/"):
+		return ""
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		return auth.ScopeRead
+	}
+	return auth.ScopeUpload
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auth"
+	mockstatestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// TestAuth tests that the api requires tokens with the scopes for the
+// requested resources when the authenticator is configured.
+func TestAuth(t *testing.T) {
+	const password = "secret"
+
+	client := newTestServer(t, testServerOptions{
+		Storer:        mock.NewStorer(),
+		Tags:          tags.NewTags(),
+		Authenticator: auth.New(mockstatestore.NewStateStore(), password),
+	})
+
+	authRequest := func(t *testing.T, method, resource, password, token string, body interface{}, responseCode int) *http.Response {
+		t.Helper()
+
+		var r io.Reader
+		if body != nil {
+			b, err := json.Marshal(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r = bytes.NewReader(b)
+		}
+		req, err := http.NewRequest(method, resource, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if password != "" {
+			req.SetBasicAuth("", password)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		if resp.StatusCode != responseCode {
+			t.Fatalf("got response status %s, want %v %s", resp.Status, responseCode, http.StatusText(responseCode))
+		}
+		return resp
+	}
+
+	issue := func(t *testing.T, scopes ...auth.Scope) string {
+		t.Helper()
+
+		resp := authRequest(t, http.MethodPost, "/auth", password, "", api.AuthRequest{Scopes: scopes}, http.StatusCreated)
+		var ar api.AuthResponse
+		if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+			t.Fatal(err)
+		}
+		return ar.Key
+	}
+
+	t.Run("issue without password", func(t *testing.T) {
+		_ = authRequest(t, http.MethodPost, "/auth", "", "", api.AuthRequest{Scopes: []auth.Scope{auth.ScopeRead}}, http.StatusUnauthorized)
+	})
+
+	t.Run("issue with wrong password", func(t *testing.T) {
+		_ = authRequest(t, http.MethodPost, "/auth", "wrong", "", api.AuthRequest{Scopes: []auth.Scope{auth.ScopeRead}}, http.StatusUnauthorized)
+	})
+
+	t.Run("issue with invalid scope", func(t *testing.T) {
+		_ = authRequest(t, http.MethodPost, "/auth", password, "", api.AuthRequest{Scopes: []auth.Scope{"unknown"}}, http.StatusBadRequest)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		_ = authRequest(t, http.MethodPost, "/bytes", "", "", nil, http.StatusUnauthorized)
+		_ = authRequest(t, http.MethodGet, "/v1/bytes/aabbcc", "", "", nil, http.StatusUnauthorized)
+	})
+
+	t.Run("scopes", func(t *testing.T) {
+		read := issue(t, auth.ScopeRead)
+		upload := issue(t, auth.ScopeUpload)
+
+		_ = authRequest(t, http.MethodPost, "/bytes", "", read, nil, http.StatusForbidden)
+		_ = authRequest(t, http.MethodGet, "/bytes/aabbcc", "", upload, nil, http.StatusForbidden)
+
+		_ = authRequest(t, http.MethodPost, "/bytes", "", upload, nil, http.StatusOK)
+		_ = authRequest(t, http.MethodGet, "/bytes/aabbcc", "", read, nil, http.StatusNotFound)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		token := issue(t, auth.ScopeRead)
+		_ = authRequest(t, http.MethodGet, "/bytes/aabbcc", "", token, nil, http.StatusNotFound)
+
+		_ = authRequest(t, http.MethodPost, "/auth/revoke", "wrong", "", api.RevokeRequest{Key: token}, http.StatusUnauthorized)
+		_ = authRequest(t, http.MethodPost, "/auth/revoke", password, "", api.RevokeRequest{Key: token}, http.StatusOK)
+		_ = authRequest(t, http.MethodPost, "/auth/revoke", password, "", api.RevokeRequest{Key: token}, http.StatusNotFound)
+
+		_ = authRequest(t, http.MethodGet, "/bytes/aabbcc", "", token, nil, http.StatusUnauthorized)
+	})
+}
@@ -27,10 +27,14 @@ type bytesPostResponse struct {
 // bytesUploadHandler handles upload of raw binary data of arbitrary length.
 func (s *server) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	sp := splitter.NewSimpleSplitter(s.Storer)
+	sp := splitter.NewPipelineSplitter(s.Storer)
 	address, err := file.SplitWriteAll(ctx, sp, r.Body, r.ContentLength)
 	if err != nil {
 		s.Logger.Debugf("bytes upload: %v", err)
+		if errors.Is(err, storage.ErrInsufficientSpace) {
+			jsonhttp.InsufficientStorage(w, "insufficient disk space")
+			return
+		}
 		jsonhttp.InternalServerError(w, nil)
 		return
 	}
@@ -44,11 +48,8 @@ func (s *server) bytesGetHandler(w http.ResponseWriter, r *http.Request) {
 	addressHex := mux.Vars(r)["address"]
 	ctx := r.Context()
 
-	address, err := swarm.ParseHexAddress(addressHex)
-	if err != nil {
-		s.Logger.Debugf("bytes: parse address %s: %v", addressHex, err)
-		s.Logger.Error("bytes: parse address error")
-		jsonhttp.BadRequest(w, "invalid address")
+	address, ok := s.resolveRequestAddress(w, r, addressHex, "bytes", "invalid address")
+	if !ok {
 		return
 	}
 
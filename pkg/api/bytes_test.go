@@ -14,6 +14,7 @@ import (
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
@@ -45,6 +46,18 @@ func TestBytes(t *testing.T) {
 		})
 	})
 
+	t.Run("insufficient space", func(t *testing.T) {
+		client := newTestServer(t, testServerOptions{
+			Storer: mock.NewStorer(mock.WithPutError(storage.ErrInsufficientSpace)),
+			Tags:   tags.NewTags(),
+			Logger: logging.New(ioutil.Discard, 5),
+		})
+		jsonhttptest.ResponseDirect(t, client, http.MethodPost, resource, bytes.NewReader(content), http.StatusInsufficientStorage, jsonhttp.StatusResponse{
+			Message: "insufficient disk space",
+			Code:    http.StatusInsufficientStorage,
+		})
+	})
+
 	t.Run("download", func(t *testing.T) {
 		resp := request(t, client, http.MethodGet, resource+"/"+expHash, nil, http.StatusOK)
 		data, err := ioutil.ReadAll(resp.Body)
@@ -36,7 +36,7 @@ func (s *server) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		s.Logger.Debugf("chunk upload: parse chunk address %s: %v", addr, err)
 		s.Logger.Error("chunk upload: parse chunk address")
-		jsonhttp.BadRequest(w, "invalid chunk address")
+		jsonhttp.BadRequest(w, jsonhttp.Error(jsonhttp.ReasonInvalidAddress, "invalid chunk address"))
 		return
 	}
 
@@ -88,6 +88,10 @@ func (s *server) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		s.Logger.Debugf("chunk upload: chunk write error: %v, addr %s", err, address)
 		s.Logger.Error("chunk upload: chunk write error")
+		if errors.Is(err, storage.ErrInsufficientSpace) {
+			jsonhttp.InsufficientStorage(w, "insufficient disk space")
+			return
+		}
 		jsonhttp.BadRequest(w, "chunk write error")
 		return
 	} else if len(seen) > 0 && seen[0] {
@@ -118,11 +122,8 @@ func (s *server) chunkGetHandler(w http.ResponseWriter, r *http.Request) {
 	addr := mux.Vars(r)["addr"]
 	ctx := r.Context()
 
-	address, err := swarm.ParseHexAddress(addr)
-	if err != nil {
-		s.Logger.Debugf("chunk: parse chunk address %s: %v", addr, err)
-		s.Logger.Error("chunk: parse chunk address error")
-		jsonhttp.BadRequest(w, "invalid chunk address")
+	address, ok := s.resolveRequestAddress(w, r, addr, "chunk", "invalid chunk address")
+	if !ok {
 		return
 	}
 
@@ -130,7 +131,7 @@ func (s *server) chunkGetHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			s.Logger.Trace("chunk: chunk not found. addr %s", address)
-			jsonhttp.NotFound(w, "chunk not found")
+			jsonhttp.NotFound(w, jsonhttp.Error(jsonhttp.ReasonChunkNotFound, "chunk not found"))
 			return
 
 		}
@@ -79,6 +79,17 @@ func TestChunkUploadDownload(t *testing.T) {
 		}
 	})
 
+	t.Run("insufficient space", func(t *testing.T) {
+		client := newTestServer(t, testServerOptions{
+			Storer: mock.NewStorer(mock.WithPutError(storage.ErrInsufficientSpace)),
+			Tags:   tag,
+		})
+		jsonhttptest.ResponseDirect(t, client, http.MethodPost, resource(validHash), bytes.NewReader(validContent), http.StatusInsufficientStorage, jsonhttp.StatusResponse{
+			Message: "insufficient disk space",
+			Code:    http.StatusInsufficientStorage,
+		})
+	})
+
 	t.Run("pin-invalid-value", func(t *testing.T) {
 		headers := make(map[string][]string)
 		headers[api.PinHeaderName] = []string{"hdgdh"}
@@ -4,7 +4,55 @@
 
 package api
 
+import (
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/apispec"
+	"github.com/gorilla/mux"
+)
+
 type (
 	BytesPostResponse  = bytesPostResponse
 	FileUploadResponse = fileUploadResponse
+	AuthRequest        = authRequest
+	AuthResponse       = authResponse
+	RevokeRequest      = revokeRequest
 )
+
+// AddDeprecatedVersion adds a deprecated API version with the routes of the
+// latest version for the duration of the test. Servers have to be created
+// after it is added.
+func AddDeprecatedVersion(t *testing.T, name string, deprecation, sunset time.Time) {
+	v := *latestVersion()
+	v.name = name
+	v.deprecation = deprecation
+	v.sunset = sunset
+	apiVersions = append([]*apiVersion{&v}, apiVersions...)
+	t.Cleanup(func() {
+		apiVersions = apiVersions[1:]
+	})
+}
+
+// Router returns the router with all API routes of the service.
+func Router(s Service) *mux.Router {
+	return s.(*server).router
+}
+
+// VersionSpecs returns the OpenAPI documents of all API versions by their names.
+func VersionSpecs() (map[string]*apispec.Spec, error) {
+	specs := make(map[string]*apispec.Spec, len(apiVersions))
+	for _, v := range apiVersions {
+		spec, err := v.spec()
+		if err != nil {
+			return nil, err
+		}
+		specs[v.name] = spec
+	}
+	return specs, nil
+}
+
+// LatestVersion returns the name of the latest API version.
+func LatestVersion() string {
+	return latestVersion().name
+}
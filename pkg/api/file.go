@@ -132,11 +132,15 @@ func (s *server) fileUploadHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// first store the file and get its reference
-	sp := splitter.NewSimpleSplitter(s.Storer)
+	sp := splitter.NewPipelineSplitter(s.Storer)
 	fr, err := file.SplitWriteAll(ctx, sp, reader, int64(fileSize))
 	if err != nil {
 		s.Logger.Debugf("file upload: file store, file %q: %v", fileName, err)
 		s.Logger.Errorf("file upload: file store, file %q", fileName)
+		if errors.Is(err, storage.ErrInsufficientSpace) {
+			jsonhttp.InsufficientStorage(w, "insufficient disk space")
+			return
+		}
 		jsonhttp.InternalServerError(w, "could not store file data")
 		return
 	}
@@ -156,11 +160,15 @@ func (s *server) fileUploadHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.InternalServerError(w, "metadata marshal error")
 		return
 	}
-	sp = splitter.NewSimpleSplitter(s.Storer)
+	sp = splitter.NewPipelineSplitter(s.Storer)
 	mr, err := file.SplitWriteAll(ctx, sp, bytes.NewReader(metadataBytes), int64(len(metadataBytes)))
 	if err != nil {
 		s.Logger.Debugf("file upload: metadata store, file %q: %v", fileName, err)
 		s.Logger.Errorf("file upload: metadata store, file %q", fileName)
+		if errors.Is(err, storage.ErrInsufficientSpace) {
+			jsonhttp.InsufficientStorage(w, "insufficient disk space")
+			return
+		}
 		jsonhttp.InternalServerError(w, "could not store metadata")
 		return
 	}
@@ -175,11 +183,15 @@ func (s *server) fileUploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sp = splitter.NewSimpleSplitter(s.Storer)
+	sp = splitter.NewPipelineSplitter(s.Storer)
 	reference, err := file.SplitWriteAll(ctx, sp, bytes.NewReader(fileEntryBytes), int64(len(fileEntryBytes)))
 	if err != nil {
 		s.Logger.Debugf("file upload: entry store, file %q: %v", fileName, err)
 		s.Logger.Errorf("file upload: entry store, file %q", fileName)
+		if errors.Is(err, storage.ErrInsufficientSpace) {
+			jsonhttp.InsufficientStorage(w, "insufficient disk space")
+			return
+		}
 		jsonhttp.InternalServerError(w, "could not store entry")
 		return
 	}
@@ -192,18 +204,15 @@ func (s *server) fileUploadHandler(w http.ResponseWriter, r *http.Request) {
 // fileDownloadHandler downloads the file given the entry's reference.
 func (s *server) fileDownloadHandler(w http.ResponseWriter, r *http.Request) {
 	addr := mux.Vars(r)["addr"]
-	address, err := swarm.ParseHexAddress(addr)
-	if err != nil {
-		s.Logger.Debugf("file download: parse file address %s: %v", addr, err)
-		s.Logger.Errorf("file download: parse file address %s", addr)
-		jsonhttp.BadRequest(w, "invalid file address")
+	address, ok := s.resolveRequestAddress(w, r, addr, "file download", "invalid file address")
+	if !ok {
 		return
 	}
 
 	// read entry.
 	j := joiner.NewSimpleJoiner(s.Storer)
 	buf := bytes.NewBuffer(nil)
-	_, err = file.JoinReadAll(j, address, buf)
+	_, err := file.JoinReadAll(j, address, buf)
 	if err != nil {
 		s.Logger.Debugf("file download: read entry %s: %v", addr, err)
 		s.Logger.Errorf("file download: read entry %s", addr)
@@ -218,6 +227,11 @@ func (s *server) fileDownloadHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.InternalServerError(w, "error unmarshaling entry")
 		return
 	}
+	if s.denied(e.Reference()) {
+		s.Logger.Errorf("file download: denied file reference %s", e.Reference())
+		jsonhttp.UnavailableForLegalReasons(w, jsonhttp.Error(jsonhttp.ReasonDeniedReference, "denied reference"))
+		return
+	}
 
 	// If none match header is set always send the reply as not modified
 	// TODO: when SOC comes, we need to revisit this concept
@@ -0,0 +1,189 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// rateLimitWindow is the time window in which the number of uploads
+// of a single client is limited in gateway mode.
+var rateLimitWindow = time.Minute
+
+// gatewayUploadHandler restricts uploads in gateway mode by limiting the
+// size of the request body and the number of uploads per client IP.
+func (s *server) gatewayUploadHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ip := s.clientIP(r); s.limiter != nil && !s.limiter.allow(ip) {
+			s.Logger.Debugf("gateway: upload rate limit exceeded for %s", ip)
+			jsonhttp.TooManyRequests(w, "upload rate limit exceeded")
+			return
+		}
+		if s.MaxUploadSize > 0 {
+			if r.ContentLength < 0 {
+				jsonhttp.LengthRequired(w, "content length required")
+				return
+			}
+			if r.ContentLength > s.MaxUploadSize {
+				s.Logger.Debugf("gateway: upload size %d exceeds limit %d", r.ContentLength, s.MaxUploadSize)
+				jsonhttp.RequestEntityTooLarge(w, "upload too large")
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, s.MaxUploadSize)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP address of the client that sent the request.
+// Requests from trusted proxies are sent by the client in the last
+// X-Forwarded-For header address that is not of a trusted proxy, or in
+// the X-Real-IP header if there is no X-Forwarded-For header.
+func (s *server) clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !s.trustedProxy(ip) {
+		return ip
+	}
+	if v := r.Header.Values("X-Forwarded-For"); len(v) > 0 {
+		addrs := strings.Split(strings.Join(v, ","), ",")
+		for i := len(addrs) - 1; i >= 0; i-- {
+			addr := strings.TrimSpace(addrs[i])
+			if net.ParseIP(addr) == nil {
+				// the header is not set by a trusted proxy
+				return ip
+			}
+			ip = addr
+			if !s.trustedProxy(ip) {
+				return ip
+			}
+		}
+		return ip
+	}
+	if addr := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(addr) != nil {
+		return addr
+	}
+	return ip
+}
+
+// trustedProxy reports whether the IP address is of a trusted proxy.
+func (s *server) trustedProxy(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, n := range s.TrustedProxies {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies parses IP addresses and CIDR networks of trusted proxies.
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(values))
+	for _, v := range values {
+		if !strings.Contains(v, "/") {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip address %q", v)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// rateLimiter limits the number of events per key in a fixed time window.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+	start  time.Time
+	counts map[string]int
+	mu     sync.Mutex
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		window: window,
+		counts: make(map[string]int),
+	}
+}
+
+// allow records an event for the key and reports whether
+// the number of events in the current window is within the limit.
+func (l *rateLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now := time.Now(); now.Sub(l.start) >= l.window {
+		l.start = now
+		l.counts = make(map[string]int)
+	}
+	if l.counts[key] >= l.limit {
+		return false
+	}
+	l.counts[key]++
+	return true
+}
+
+// denied reports whether the reference is in the denylist.
+func (s *server) denied(addr swarm.Address) bool {
+	_, ok := s.denylist[addr.String()]
+	return ok
+}
+
+// ReadDenylistFile reads references from a file which contains one hex
+// encoded reference per line. Empty lines and lines starting with # are
+// ignored.
+func ReadDenylistFile(path string) ([]swarm.Address, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var addrs []swarm.Address
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		addr, err := swarm.ParseHexAddress(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		addrs = append(addrs, addr)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
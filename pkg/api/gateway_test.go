@@ -0,0 +1,206 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// TestGatewayMode tests that uploads are restricted in gateway mode.
+func TestGatewayMode(t *testing.T) {
+	client := newTestServer(t, testServerOptions{
+		Storer:          mock.NewStorer(),
+		Tags:            tags.NewTags(),
+		Logger:          logging.New(ioutil.Discard, 0),
+		GatewayMode:     true,
+		MaxUploadSize:   10,
+		UploadRateLimit: 2,
+	})
+
+	t.Run("chunk upload disabled", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, client, http.MethodPost, "/chunks/"+swarm.NewAddress(make([]byte, 32)).String(), bytes.NewReader([]byte("data")), http.StatusMethodNotAllowed, jsonhttp.StatusResponse{
+			Message: http.StatusText(http.StatusMethodNotAllowed),
+			Code:    http.StatusMethodNotAllowed,
+		})
+	})
+
+	t.Run("upload too large", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, client, http.MethodPost, "/bytes", bytes.NewReader(make([]byte, 11)), http.StatusRequestEntityTooLarge, jsonhttp.StatusResponse{
+			Message: "upload too large",
+			Code:    http.StatusRequestEntityTooLarge,
+		})
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		// the rejected upload above is counted as well
+		var resp api.BytesPostResponse
+		jsonhttptest.ResponseUnmarshal(t, client, http.MethodPost, "/bytes", bytes.NewReader([]byte("data")), http.StatusOK, &resp)
+
+		jsonhttptest.ResponseDirect(t, client, http.MethodPost, "/bytes", bytes.NewReader([]byte("data")), http.StatusTooManyRequests, jsonhttp.StatusResponse{
+			Message: "upload rate limit exceeded",
+			Code:    http.StatusTooManyRequests,
+		})
+
+		// downloads are not limited
+		_ = request(t, client, http.MethodGet, "/bytes/"+resp.Reference.String(), nil, http.StatusOK)
+	})
+}
+
+// TestGatewayTrustedProxies tests that uploads are limited by the
+// client IP sent by trusted proxies.
+func TestGatewayTrustedProxies(t *testing.T) {
+	upload := func(t *testing.T, client *http.Client, header http.Header, code int) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodPost, "/bytes", bytes.NewReader([]byte("data")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != code {
+			t.Fatalf("got response status %s, want %v %s", resp.Status, code, http.StatusText(code))
+		}
+	}
+	forwardedFor := func(values ...string) http.Header {
+		return http.Header{"X-Forwarded-For": values}
+	}
+
+	t.Run("trusted", func(t *testing.T) {
+		trustedProxies, err := api.ParseTrustedProxies([]string{"127.0.0.1", "10.0.0.0/8"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := newTestServer(t, testServerOptions{
+			Storer:          mock.NewStorer(),
+			Tags:            tags.NewTags(),
+			GatewayMode:     true,
+			UploadRateLimit: 1,
+			TrustedProxies:  trustedProxies,
+		})
+
+		upload(t, client, forwardedFor("192.0.2.1"), http.StatusOK)
+		upload(t, client, forwardedFor("192.0.2.1"), http.StatusTooManyRequests)
+		// addresses of trusted proxies are skipped
+		upload(t, client, forwardedFor("192.0.2.2, 192.0.2.1", "10.0.0.1"), http.StatusTooManyRequests)
+		upload(t, client, forwardedFor("192.0.2.2"), http.StatusOK)
+		upload(t, client, http.Header{"X-Real-Ip": {"192.0.2.3"}}, http.StatusOK)
+		upload(t, client, http.Header{"X-Real-Ip": {"192.0.2.3"}}, http.StatusTooManyRequests)
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		client := newTestServer(t, testServerOptions{
+			Storer:          mock.NewStorer(),
+			Tags:            tags.NewTags(),
+			GatewayMode:     true,
+			UploadRateLimit: 1,
+		})
+
+		upload(t, client, forwardedFor("192.0.2.1"), http.StatusOK)
+		upload(t, client, forwardedFor("192.0.2.2"), http.StatusTooManyRequests)
+	})
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	nets, err := api.ParseTrustedProxies([]string{"192.0.2.1", "2001:db8::1", "10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"192.0.2.1/32", "2001:db8::1/128", "10.0.0.0/8"}
+	if len(nets) != len(want) {
+		t.Fatalf("got %v networks, want %v", len(nets), len(want))
+	}
+	for i, n := range nets {
+		if n.String() != want[i] {
+			t.Errorf("got network %s, want %s", n, want[i])
+		}
+	}
+
+	for _, v := range []string{"invalid", "10.0.0.0/33"} {
+		if _, err := api.ParseTrustedProxies([]string{v}); err == nil {
+			t.Errorf("%q: expected error", v)
+		}
+	}
+}
+
+// TestDenylist tests that references in the denylist are not served.
+func TestDenylist(t *testing.T) {
+	var (
+		mockStorer = mock.NewStorer()
+		content    = []byte("denied content")
+	)
+
+	var resp api.BytesPostResponse
+	jsonhttptest.ResponseUnmarshal(t, newTestServer(t, testServerOptions{
+		Storer: mockStorer,
+		Tags:   tags.NewTags(),
+	}), http.MethodPost, "/bytes", bytes.NewReader(content), http.StatusOK, &resp)
+
+	client := newTestServer(t, testServerOptions{
+		Storer:   mockStorer,
+		Tags:     tags.NewTags(),
+		Denylist: []swarm.Address{resp.Reference},
+	})
+
+	for _, resource := range []string{"/bytes/", "/chunks/", "/files/"} {
+		jsonhttptest.ResponseDirect(t, client, http.MethodGet, resource+resp.Reference.String(), nil, http.StatusUnavailableForLegalReasons, jsonhttp.StatusResponse{
+			Message: "denied reference",
+			Code:    http.StatusUnavailableForLegalReasons,
+			Reason:  jsonhttp.ReasonDeniedReference,
+		})
+	}
+}
+
+func TestReadDenylistFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bee-denylist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "denylist")
+	data := "# denied references\n\naabbcc\n  ddeeff  \n"
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	addrs, err := api.ReadDenylistFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []swarm.Address{swarm.MustParseHexAddress("aabbcc"), swarm.MustParseHexAddress("ddeeff")}
+	if len(addrs) != len(want) {
+		t.Fatalf("got %d addresses, want %d", len(addrs), len(want))
+	}
+	for i, addr := range addrs {
+		if !addr.Equal(want[i]) {
+			t.Errorf("got address %s, want %s", addr, want[i])
+		}
+	}
+
+	if err := ioutil.WriteFile(path, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.ReadDenylistFile(path); err == nil {
+		t.Fatal("expected error for invalid reference")
+	}
+}
@@ -6,9 +6,11 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -16,9 +18,12 @@ type metrics struct {
 	// all metrics fields must be exported
 	// to be able to return them by Metrics()
 	// using reflection
-	RequestCount     prometheus.Counter
-	ResponseDuration prometheus.Histogram
-	PingRequestCount prometheus.Counter
+	RequestCount          prometheus.Counter
+	ResponseDuration      prometheus.Histogram
+	PingRequestCount      prometheus.Counter
+	RouteRequestCount     *prometheus.CounterVec
+	RouteResponseDuration *prometheus.HistogramVec
+	PanicCount            prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -38,6 +43,25 @@ func newMetrics() metrics {
 			Help:      "Histogram of API response durations.",
 			Buckets:   []float64{0.01, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
 		}),
+		RouteRequestCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "route_request_count",
+			Help:      "Number of API requests per route, method and response status code.",
+		}, []string{"route", "method", "code"}),
+		RouteResponseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "route_response_duration_seconds",
+			Help:      "Histogram of API response durations per route.",
+			Buckets:   []float64{0.01, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"route", "method"}),
+		PanicCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "panic_count",
+			Help:      "Number of panics recovered in API handlers.",
+		}),
 	}
 }
 
@@ -49,7 +73,60 @@ func (s *server) pageviewMetricsHandler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		s.metrics.RequestCount.Inc()
-		h.ServeHTTP(w, r)
-		s.metrics.ResponseDuration.Observe(time.Since(start).Seconds())
+		sr := &statusRecorder{ResponseWriter: w}
+		h.ServeHTTP(sr, r)
+		duration := time.Since(start).Seconds()
+		s.metrics.ResponseDuration.Observe(duration)
+
+		route := s.routeTemplate(r)
+		s.metrics.RouteRequestCount.WithLabelValues(route, r.Method, strconv.Itoa(sr.status())).Inc()
+		s.metrics.RouteResponseDuration.WithLabelValues(route, r.Method).Observe(duration)
 	})
 }
+
+// routeTemplate returns the path template of the route that matches the
+// request, so that requests for different references are counted together.
+func (s *server) routeTemplate(r *http.Request) string {
+	var match mux.RouteMatch
+	if s.router == nil || !s.router.Match(r, &match) || match.Route == nil {
+		return "unknown"
+	}
+	t, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return "unknown"
+	}
+	return t
+}
+
+// statusRecorder records the status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	if sr.code == 0 {
+		sr.code = code
+	}
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.code == 0 {
+		sr.code = http.StatusOK
+	}
+	return sr.ResponseWriter.Write(b)
+}
+
+func (sr *statusRecorder) Flush() {
+	if f, ok := sr.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (sr *statusRecorder) status() int {
+	if sr.code == 0 {
+		return http.StatusOK
+	}
+	return sr.code
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// TestResolveNames tests that names in the api routes are resolved
+// to references using the configured resolver.
+func TestResolveNames(t *testing.T) {
+	var (
+		content    = []byte("hello, resolved world")
+		mockStorer = mock.NewStorer()
+		reference  swarm.Address
+		client     = newTestServer(t, testServerOptions{
+			Storer: mockStorer,
+			Resolver: resolverFunc(func(_ context.Context, name string) (swarm.Address, error) {
+				switch name {
+				case "mysite.eth":
+					return reference, nil
+				case "failing.eth":
+					return swarm.ZeroAddress, errors.New("invalid response")
+				case "unreachable.eth":
+					return swarm.ZeroAddress, fmt.Errorf("%w: connection refused", resolver.ErrUnavailable)
+				}
+				return swarm.ZeroAddress, resolver.ErrNotFound
+			}),
+			Tags:   tags.NewTags(),
+			Logger: logging.New(ioutil.Discard, 0),
+		})
+	)
+
+	var resp api.BytesPostResponse
+	jsonhttptest.ResponseUnmarshal(t, client, http.MethodPost, "/bytes", bytes.NewReader(content), http.StatusOK, &resp)
+	reference = resp.Reference
+
+	t.Run("resolved", func(t *testing.T) {
+		r := request(t, client, http.MethodGet, "/bytes/mysite.eth", nil, http.StatusOK)
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, content) {
+			t.Fatalf("got data %q, want %q", data, content)
+		}
+	})
+
+	t.Run("address", func(t *testing.T) {
+		_ = request(t, client, http.MethodGet, "/bytes/"+reference.String(), nil, http.StatusOK)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		for _, resource := range []string{"/bytes/unknown.eth", "/files/unknown.eth", "/chunks/unknown.eth"} {
+			jsonhttptest.ResponseDirect(t, client, http.MethodGet, resource, nil, http.StatusNotFound, jsonhttp.StatusResponse{
+				Message: "name not found",
+				Code:    http.StatusNotFound,
+				Reason:  jsonhttp.ReasonNameNotFound,
+			})
+		}
+	})
+
+	t.Run("resolution error", func(t *testing.T) {
+		for _, resource := range []string{"/bytes/failing.eth", "/files/failing.eth", "/chunks/failing.eth"} {
+			jsonhttptest.ResponseDirect(t, client, http.MethodGet, resource, nil, http.StatusBadGateway, jsonhttp.StatusResponse{
+				Message: "name not resolved",
+				Code:    http.StatusBadGateway,
+				Reason:  jsonhttp.ReasonNameNotResolved,
+			})
+		}
+	})
+
+	t.Run("resolver unavailable", func(t *testing.T) {
+		for _, resource := range []string{"/bytes/unreachable.eth", "/files/unreachable.eth", "/chunks/unreachable.eth"} {
+			jsonhttptest.ResponseDirect(t, client, http.MethodGet, resource, nil, http.StatusServiceUnavailable, jsonhttp.StatusResponse{
+				Message: "name resolver unavailable",
+				Code:    http.StatusServiceUnavailable,
+				Reason:  jsonhttp.ReasonNameNotResolved,
+			})
+		}
+	})
+}
+
+type resolverFunc func(ctx context.Context, name string) (swarm.Address, error)
+
+func (f resolverFunc) Resolve(ctx context.Context, name string) (swarm.Address, error) {
+	return f(ctx, name)
+}
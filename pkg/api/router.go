@@ -10,6 +10,7 @@ import (
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
@@ -17,13 +18,6 @@ import (
 )
 
 func (s *server) setupRouting() {
-	apiVersion := "v1" // only one api version exists, this should be configurable with more
-
-	handle := func(router *mux.Router, path string, handler http.Handler) {
-		router.Handle(path, handler)
-		router.Handle("/"+apiVersion+path, handler)
-	}
-
 	router := mux.NewRouter()
 	router.NotFoundHandler = http.HandlerFunc(jsonhttp.NotFoundHandler)
 
@@ -35,29 +29,24 @@ func (s *server) setupRouting() {
 		fmt.Fprintln(w, "User-agent: *\nDisallow: /")
 	})
 
-	handle(router, "/files", jsonhttp.MethodHandler{
-		"POST": http.HandlerFunc(s.fileUploadHandler),
-	})
-	handle(router, "/files/{addr}", jsonhttp.MethodHandler{
-		"GET": http.HandlerFunc(s.fileDownloadHandler),
-	})
+	s.versionRoutes(router)
 
-	handle(router, "/bytes", jsonhttp.MethodHandler{
-		"POST": http.HandlerFunc(s.bytesUploadHandler),
-	})
-	handle(router, "/bytes/{address}", jsonhttp.MethodHandler{
-		"GET": http.HandlerFunc(s.bytesGetHandler),
-	})
+	s.router = router
 
-	handle(router, "/chunks/{addr}", jsonhttp.MethodHandler{
-		"GET":  http.HandlerFunc(s.chunkGetHandler),
-		"POST": http.HandlerFunc(s.chunkUploadHandler),
-	})
+	authHandler := func(h http.Handler) http.Handler { return h }
+	if s.Authenticator != nil {
+		authHandler = s.Authenticator.Middleware(s.Logger, authScope)
+	}
+
+	specValidationHandler := func(h http.Handler) http.Handler { return h }
+	if s.ValidateSpec {
+		specValidationHandler = s.specValidationHandler
+	}
 
 	s.Handler = web.ChainHandlers(
 		logging.NewHTTPAccessLogHandler(s.Logger, logrus.InfoLevel, "api access"),
 		handlers.CompressHandler,
-		// todo: add recovery handler
+		s.recoveryHandler,
 		s.pageviewMetricsHandler,
 		func(h http.Handler) http.Handler {
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -71,10 +60,65 @@ func (s *server) setupRouting() {
 				h.ServeHTTP(w, r)
 			})
 		},
+		authHandler,
+		versionHandler,
+		specValidationHandler,
 		web.FinalHandler(router),
 	)
 }
 
+// routesV1 registers the handlers of the v1 API version.
+func (s *server) routesV1(router *mux.Router) {
+	if s.Authenticator != nil {
+		router.Handle("/auth", jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.authHandler),
+		})
+		router.Handle("/auth/revoke", jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.authRevokeHandler),
+		})
+	}
+
+	// uploads are restricted in gateway mode
+	upload := func(h http.HandlerFunc) http.Handler {
+		if s.GatewayMode {
+			return s.gatewayUploadHandler(h)
+		}
+		return h
+	}
+
+	router.Handle("/files", jsonhttp.MethodHandler{
+		"POST": upload(s.fileUploadHandler),
+	})
+	router.Handle("/files/{addr}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.fileDownloadHandler),
+	})
+
+	router.Handle("/bytes", jsonhttp.MethodHandler{
+		"POST": upload(s.bytesUploadHandler),
+	})
+	router.Handle("/bytes/{address}", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.bytesGetHandler),
+	})
+
+	chunkHandlers := jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.chunkGetHandler),
+	}
+	// chunk uploads are disabled in gateway mode
+	if !s.GatewayMode {
+		chunkHandlers["POST"] = http.HandlerFunc(s.chunkUploadHandler)
+	}
+	router.Handle("/chunks/{addr}", chunkHandlers)
+}
+
+// recoveryHandler responds with the Internal Server Error status if the
+// handler panics, logging the panic and counting it in metrics.
+func (s *server) recoveryHandler(h http.Handler) http.Handler {
+	return jsonhttp.NewRecoveryHandler(func(r *http.Request, v interface{}, stack []byte) {
+		s.metrics.PanicCount.Inc()
+		tracing.NewLoggerWithTraceID(r.Context(), s.Logger).Errorf("api: panic serving %s %s: %v\n%s", r.Method, r.URL.Path, v, stack)
+	})(h)
+}
+
 func containsOrigin(s string, l []string) (ok bool) {
 	for _, e := range l {
 		if e == s || e == "*" {
@@ -0,0 +1,151 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/apispec"
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	mockstate "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/gorilla/mux"
+)
+
+// TestSpecRoutes checks that all registered routes are defined in the
+// OpenAPI documents of their versions and that all operations of the
+// documents are registered.
+func TestSpecRoutes(t *testing.T) {
+	s := api.New(api.Options{
+		Tags:          tags.NewTags(),
+		Storer:        mock.NewStorer(),
+		Logger:        logging.New(ioutil.Discard, 0),
+		Authenticator: auth.New(mockstate.NewStateStore(), "secret"),
+	})
+	specs, err := api.VersionSpecs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registered := make(map[string]bool)
+	err = api.Router(s).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			// subrouters that match by the Accept header have no path
+			return nil
+		}
+		switch template {
+		case "/", "/robots.txt":
+			// not a part of the api
+			return nil
+		}
+		handler, ok := route.GetHandler().(jsonhttp.MethodHandler)
+		if !ok {
+			return nil
+		}
+		prefixed := hasVersionPrefix(template, specs)
+		for method := range handler {
+			registered[method+" "+normalizeTemplate(template)] = true
+
+			// routes with a version prefix have to be defined in
+			// that version and the ones without it in any version
+			found := false
+			for name, spec := range specs {
+				if prefixed && !strings.HasPrefix(template, "/"+name+"/") {
+					continue
+				}
+				if spec.HasOperation(template, method) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("route %s %s is not defined in the api spec", method, template)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, spec := range specs {
+		for _, op := range spec.Operations() {
+			if !registered[op.Method+" "+normalizeTemplate("/"+name+op.Path)] {
+				t.Errorf("api spec operation %s %s is not registered in version %s", op.Method, op.Path, name)
+			}
+		}
+	}
+}
+
+// TestSpecValidation checks that invalid requests are rejected
+// when the spec validation is enabled.
+func TestSpecValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		validate bool
+		message  string
+	}{
+		{name: "enabled", validate: true, message: "invalid request: request body: content type application/x-www-form-urlencoded: not declared"},
+		{name: "disabled", validate: false, message: "invalid request"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestServer(t, testServerOptions{
+				Storer:        mock.NewStorer(),
+				Tags:          tags.NewTags(),
+				Authenticator: auth.New(mockstate.NewStateStore(), "secret"),
+				ValidateSpec:  tc.validate,
+			})
+
+			// the content type is not declared for the request body
+			jsonhttptest.ResponseDirectSendHeadersAndReceiveHeaders(t, client, http.MethodPost, "/auth", strings.NewReader("scopes=admin"), http.StatusBadRequest, jsonhttp.StatusResponse{
+				Message: tc.message,
+				Code:    http.StatusBadRequest,
+			}, http.Header{
+				"Authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte(":secret"))},
+				"Content-Type":  {"application/x-www-form-urlencoded"},
+			})
+		})
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		client := newTestServer(t, testServerOptions{
+			Storer:       mock.NewStorer(),
+			Tags:         tags.NewTags(),
+			ValidateSpec: true,
+		})
+
+		jsonhttptest.ResponseDirect(t, client, http.MethodGet, "/bytes/aabbcc", nil, http.StatusNotFound, jsonhttp.StatusResponse{
+			Message: "not found",
+			Code:    http.StatusNotFound,
+		})
+	})
+}
+
+var templateParam = regexp.MustCompile(`{[^}]*}`)
+
+// normalizeTemplate replaces the path parameters in the template, as
+// their names differ between the routes and the api spec.
+func normalizeTemplate(template string) string {
+	return templateParam.ReplaceAllString(template, "{}")
+}
+
+func hasVersionPrefix(template string, specs map[string]*apispec.Spec) bool {
+	for name := range specs {
+		if strings.HasPrefix(template, "/"+name+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/openapi"
+	"github.com/ethersphere/bee/pkg/apispec"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// VersionHeader is the response header with the API version
+	// that served the request.
+	VersionHeader = "Swarm-Api-Version"
+
+	// versionMediaTypePrefix is the prefix of media types in the Accept
+	// header that request a specific API version for routes without the
+	// version path prefix, for example application/vnd.swarm.v1+json.
+	versionMediaTypePrefix = "application/vnd.swarm."
+)
+
+// apiVersion describes a version of the API and its routes.
+type apiVersion struct {
+	// name of the version used as the path prefix
+	// and in the Accept header media type
+	name string
+	// deprecation and sunset are set to announce that the version
+	// is deprecated and when it will be removed
+	deprecation time.Time
+	sunset      time.Time
+	// specs are the OpenAPI documents served under the version,
+	// by their file names, with the main document as openapi.yaml
+	specs map[string]string
+	// routes registers the handlers of the version
+	routes func(s *server, router *mux.Router)
+}
+
+func (v *apiVersion) deprecated() bool {
+	return !v.deprecation.IsZero()
+}
+
+// apiVersions are all supported API versions, from the oldest to the newest.
+var apiVersions = []*apiVersion{
+	{
+		name: "v1",
+		specs: map[string]string{
+			"openapi.yaml":     openapi.Swarm,
+			"SwarmCommon.yaml": openapi.SwarmCommon,
+		},
+		routes: (*server).routesV1,
+	},
+}
+
+// spec returns the parsed OpenAPI document of the version.
+func (v *apiVersion) spec() (*apispec.Spec, error) {
+	return apispec.Load("openapi.yaml", v.specs)
+}
+
+// latestVersion returns the newest version that is not deprecated,
+// which serves requests without a version path prefix by default.
+func latestVersion() *apiVersion {
+	for i := len(apiVersions) - 1; i >= 0; i-- {
+		if !apiVersions[i].deprecated() {
+			return apiVersions[i]
+		}
+	}
+	return apiVersions[len(apiVersions)-1]
+}
+
+// versionByName returns the version with the name or nil if it is not supported.
+func versionByName(name string) *apiVersion {
+	for _, v := range apiVersions {
+		if v.name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// pathVersion returns the version from the path prefix of the request and
+// the path without the prefix. It returns nil if there is no version prefix.
+func pathVersion(path string) (*apiVersion, string) {
+	p := strings.TrimPrefix(path, "/")
+	name := p
+	if i := strings.IndexByte(p, '/'); i >= 0 {
+		name = p[:i]
+	}
+	v := versionByName(name)
+	if v == nil {
+		return nil, path
+	}
+	return v, strings.TrimPrefix(p, name)
+}
+
+// negotiateVersion returns the API version requested by the path prefix or
+// by the Accept header of the request, or the latest version if no version
+// is requested. It returns false if only unsupported versions are requested.
+func negotiateVersion(r *http.Request) (*apiVersion, bool) {
+	if v, _ := pathVersion(r.URL.Path); v != nil {
+		return v, true
+	}
+
+	var requested bool
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(mediaRange)
+			if err != nil || !strings.HasPrefix(mediaType, versionMediaTypePrefix) {
+				continue
+			}
+			requested = true
+			name := strings.TrimPrefix(mediaType, versionMediaTypePrefix)
+			if i := strings.IndexByte(name, '+'); i >= 0 {
+				name = name[:i]
+			}
+			if v := versionByName(name); v != nil {
+				return v, true
+			}
+		}
+	}
+	if requested {
+		return nil, false
+	}
+	return latestVersion(), true
+}
+
+// versionRoutes registers routes of all API versions, both with the version
+// path prefix and without it for requests that negotiate the version with
+// the Accept header.
+func (s *server) versionRoutes(router *mux.Router) {
+	for _, v := range apiVersions {
+		v := v
+
+		s.versionRouter(router.PathPrefix("/"+v.name).Subrouter(), v)
+
+		s.versionRouter(router.MatcherFunc(func(r *http.Request, _ *mux.RouteMatch) bool {
+			nv, ok := negotiateVersion(r)
+			return ok && nv == v
+		}).Subrouter(), v)
+	}
+}
+
+func (s *server) versionRouter(router *mux.Router, v *apiVersion) {
+	for name, spec := range v.specs {
+		spec := spec
+		router.Handle("/"+name, jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/yaml")
+				fmt.Fprint(w, spec)
+			}),
+		})
+	}
+	v.routes(s, router)
+}
+
+// versionHandler responds with the Not Acceptable status if the requested
+// API version is not supported. Otherwise, it sets the version header and,
+// for deprecated versions, the Deprecation and Sunset headers.
+func versionHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, ok := negotiateVersion(r)
+		if !ok {
+			jsonhttp.NotAcceptable(w, "unsupported api version")
+			return
+		}
+		w.Header().Set(VersionHeader, v.name)
+		if v.deprecated() {
+			w.Header().Set("Deprecation", v.deprecation.UTC().Format(http.TimeFormat))
+			if !v.sunset.IsZero() {
+				w.Header().Set("Sunset", v.sunset.UTC().Format(http.TimeFormat))
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// specValidationHandler validates requests and responses against the
+// OpenAPI document of the negotiated API version.
+func (s *server) specValidationHandler(h http.Handler) http.Handler {
+	return apispec.NewValidationHandler(s.Logger, func(r *http.Request) *apispec.Spec {
+		v, ok := negotiateVersion(r)
+		if !ok {
+			return nil
+		}
+		return s.specs[v]
+	})(h)
+}
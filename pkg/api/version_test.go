@@ -0,0 +1,126 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/openapi"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// TestVersions tests negotiation of the api version with the path prefix
+// and the Accept header, and the headers of deprecated versions.
+func TestVersions(t *testing.T) {
+	var (
+		deprecation = time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+		sunset      = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+	api.AddDeprecatedVersion(t, "v0", deprecation, sunset)
+
+	client := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+		Tags:   tags.NewTags(),
+	})
+
+	get := func(t *testing.T, resource, accept string, responseCode int) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, resource, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		if resp.StatusCode != responseCode {
+			t.Fatalf("got response status %s, want %v %s", resp.Status, responseCode, http.StatusText(responseCode))
+		}
+		return resp
+	}
+
+	for _, tc := range []struct {
+		name        string
+		resource    string
+		accept      string
+		version     string
+		deprecation string
+		sunset      string
+	}{
+		{name: "default", resource: "/bytes/aabbcc", version: "v1"},
+		{name: "path prefix", resource: "/v1/bytes/aabbcc", version: "v1"},
+		{name: "accept", resource: "/bytes/aabbcc", accept: "application/vnd.swarm.v1+json", version: "v1"},
+		{name: "accept with other types", resource: "/bytes/aabbcc", accept: "text/html, application/vnd.swarm.v9, application/vnd.swarm.v1", version: "v1"},
+		{
+			name:        "deprecated path prefix",
+			resource:    "/v0/bytes/aabbcc",
+			version:     "v0",
+			deprecation: "Wed, 01 Jul 2020 00:00:00 GMT",
+			sunset:      "Fri, 01 Jan 2021 00:00:00 GMT",
+		},
+		{
+			name:        "deprecated accept",
+			resource:    "/bytes/aabbcc",
+			accept:      "application/vnd.swarm.v0+json",
+			version:     "v0",
+			deprecation: "Wed, 01 Jul 2020 00:00:00 GMT",
+			sunset:      "Fri, 01 Jan 2021 00:00:00 GMT",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := get(t, tc.resource, tc.accept, http.StatusNotFound)
+			if got := resp.Header.Get(api.VersionHeader); got != tc.version {
+				t.Errorf("got version %q, want %q", got, tc.version)
+			}
+			if got := resp.Header.Get("Deprecation"); got != tc.deprecation {
+				t.Errorf("got deprecation %q, want %q", got, tc.deprecation)
+			}
+			if got := resp.Header.Get("Sunset"); got != tc.sunset {
+				t.Errorf("got sunset %q, want %q", got, tc.sunset)
+			}
+		})
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		jsonhttptest.ResponseDirectSendHeadersAndReceiveHeaders(t, client, http.MethodGet, "/bytes/aabbcc", nil, http.StatusNotAcceptable, jsonhttp.StatusResponse{
+			Message: "unsupported api version",
+			Code:    http.StatusNotAcceptable,
+		}, http.Header{"Accept": {"application/vnd.swarm.v9+json"}})
+	})
+
+	t.Run("unknown path prefix", func(t *testing.T) {
+		_ = get(t, "/v9/bytes/aabbcc", "", http.StatusNotFound)
+	})
+
+	t.Run("openapi", func(t *testing.T) {
+		for resource, want := range map[string]string{
+			"/openapi.yaml":        openapi.Swarm,
+			"/v1/openapi.yaml":     openapi.Swarm,
+			"/v1/SwarmCommon.yaml": openapi.SwarmCommon,
+		} {
+			resp := get(t, resource, "", http.StatusOK)
+			got, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, []byte(want)) {
+				t.Errorf("%s: got unexpected document", resource)
+			}
+		}
+	})
+}
@@ -0,0 +1,335 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package apispec validates HTTP requests and responses against OpenAPI 3
+// documents.
+//
+// It supports the subset of the specification that is used by the bundled
+// API documents: paths with path parameters, operations with parameters,
+// request bodies and responses, and schemas with types, properties, additional
+// properties, items, patterns, enumerations and the oneOf, anyOf and allOf
+// compositions.
+// References to other documents are resolved by their file names.
+package apispec
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrOperationNotFound is returned when the request does not
+	// match any operation in the document.
+	ErrOperationNotFound = errors.New("operation not found")
+)
+
+// Spec is a parsed OpenAPI document with resolved references.
+type Spec struct {
+	basePaths []string
+	paths     []*pathItem
+	strict    bool // reject undeclared properties of response bodies
+}
+
+type pathItem struct {
+	template   string
+	segments   []string
+	operations map[string]*operation
+}
+
+type operation struct {
+	parameters  []*parameter
+	requestBody *requestBody
+	responses   map[string]*response
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	schema   *Schema
+}
+
+type requestBody struct {
+	required bool
+	content  map[string]*Schema
+}
+
+type response struct {
+	content map[string]*Schema
+}
+
+// Operation identifies an operation of the document by
+// its path template and HTTP method.
+type Operation struct {
+	Path   string
+	Method string
+}
+
+// Load parses the main OpenAPI document from the documents, which are
+// provided by their file names so that references between them can be
+// resolved.
+func Load(main string, documents map[string]string) (*Spec, error) {
+	l := &loader{
+		docs:    make(map[string]map[string]interface{}),
+		schemas: make(map[string]*Schema),
+	}
+	for name, content := range documents {
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(content), &v); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		m, ok := normalize(v).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("parse %s: not an object", name)
+		}
+		l.docs[name] = m
+	}
+	doc, ok := l.docs[main]
+	if !ok {
+		return nil, fmt.Errorf("document %s not found", main)
+	}
+
+	s := new(Spec)
+	for _, server := range list(doc["servers"]) {
+		s.basePaths = append(s.basePaths, serverPath(str(obj(server)["url"])))
+	}
+	if len(s.basePaths) == 0 {
+		s.basePaths = []string{""}
+	}
+	// longer base paths are tried first
+	sort.Slice(s.basePaths, func(i, j int) bool {
+		return len(s.basePaths[i]) > len(s.basePaths[j])
+	})
+
+	paths := obj(doc["paths"])
+	templates := make([]string, 0, len(paths))
+	for template := range paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	for _, template := range templates {
+		item, itemDoc, err := l.resolve(main, obj(paths[template]))
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", template, err)
+		}
+		p := &pathItem{
+			template:   template,
+			segments:   splitPath(template),
+			operations: make(map[string]*operation),
+		}
+		common, err := l.parameters(itemDoc, list(item["parameters"]))
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", template, err)
+		}
+		for method, v := range item {
+			method = strings.ToUpper(method)
+			switch method {
+			case http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+				http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace:
+			default:
+				continue
+			}
+			op, err := l.operation(itemDoc, obj(v), common)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, template, err)
+			}
+			p.operations[method] = op
+		}
+		s.paths = append(s.paths, p)
+	}
+	return s, nil
+}
+
+// Strict returns the spec which also rejects properties of JSON response
+// bodies that are not declared by object schemas, so that renamed fields
+// are detected. Objects with no declared properties, or which allow
+// additional properties by a schema, are not checked.
+func (s *Spec) Strict() *Spec {
+	strict := *s
+	strict.strict = true
+	return &strict
+}
+
+// Operations returns all operations defined in the document.
+func (s *Spec) Operations() (ops []Operation) {
+	for _, p := range s.paths {
+		for method := range p.operations {
+			ops = append(ops, Operation{Path: p.template, Method: method})
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path == ops[j].Path {
+			return ops[i].Method < ops[j].Method
+		}
+		return ops[i].Path < ops[j].Path
+	})
+	return ops
+}
+
+// HasOperation returns true if the document defines the method for the
+// path template, regardless of the names of path parameters. The template
+// may contain any of the server base paths.
+func (s *Spec) HasOperation(template, method string) bool {
+	for _, base := range s.basePaths {
+		rest, ok := trimBasePath(template, base)
+		if !ok {
+			continue
+		}
+		segments := splitPath(rest)
+		for _, p := range s.paths {
+			if _, ok := p.operations[strings.ToUpper(method)]; ok && sameTemplate(p.segments, segments) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// find returns the operation and the path parameters for the request.
+func (s *Spec) find(method, path string) (*operation, map[string]string, error) {
+	for _, base := range s.basePaths {
+		rest, ok := trimBasePath(path, base)
+		if !ok {
+			continue
+		}
+		p, params := s.match(splitPath(rest))
+		if p == nil {
+			continue
+		}
+		op, ok := p.operations[method]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: method %s not defined for %s", ErrOperationNotFound, method, p.template)
+		}
+		return op, params, nil
+	}
+	return nil, nil, fmt.Errorf("%w: %s %s", ErrOperationNotFound, method, path)
+}
+
+// match returns the path item that matches the path segments, preferring
+// the items with more literal segments. If no path item matches exactly,
+// a parameter in the last segment of a template may match all remaining
+// path segments, as with parameters that contain slashes.
+func (s *Spec) match(segments []string) (*pathItem, map[string]string) {
+	var (
+		best       *pathItem
+		bestParams map[string]string
+		bestScore  = -1
+	)
+	for _, greedy := range []bool{false, true} {
+		for _, p := range s.paths {
+			params, score, ok := matchSegments(p.segments, segments, greedy)
+			if ok && score > bestScore {
+				best, bestParams, bestScore = p, params, score
+			}
+		}
+		if best != nil {
+			return best, bestParams
+		}
+	}
+	return nil, nil
+}
+
+func matchSegments(template, segments []string, greedy bool) (map[string]string, int, bool) {
+	n := len(template)
+	switch {
+	case n == len(segments):
+	case greedy && n > 0 && n < len(segments):
+		if _, ok := paramName(template[n-1]); !ok {
+			return nil, 0, false
+		}
+	default:
+		return nil, 0, false
+	}
+
+	params := make(map[string]string)
+	literals := 0
+	for i, t := range template {
+		if name, ok := paramName(t); ok {
+			v := segments[i]
+			if i == n-1 {
+				v = strings.Join(segments[i:], "/")
+			}
+			if v == "" {
+				return nil, 0, false
+			}
+			if u, err := url.PathUnescape(v); err == nil {
+				v = u
+			}
+			params[name] = v
+			continue
+		}
+		if t != segments[i] {
+			return nil, 0, false
+		}
+		literals++
+	}
+	return params, literals, true
+}
+
+func sameTemplate(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		_, ap := paramName(a[i])
+		_, bp := paramName(b[i])
+		if ap != bp || (!ap && a[i] != b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// paramName returns the name of the path parameter in the template segment,
+// without the regular expression of gorilla/mux templates.
+func paramName(segment string) (string, bool) {
+	if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+		return "", false
+	}
+	name := segment[1 : len(segment)-1]
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		name = name[:i]
+	}
+	return name, true
+}
+
+// splitPath splits the path into segments, ignoring the trailing slash.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func trimBasePath(path, base string) (string, bool) {
+	if base == "" {
+		return path, true
+	}
+	if path != base && !strings.HasPrefix(path, base+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(path, base), true
+}
+
+// serverPath returns the path of the server URL which may contain variables
+// in its scheme and host parts.
+func serverPath(u string) string {
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+		if i := strings.IndexByte(u, '/'); i >= 0 {
+			u = u[i:]
+		} else {
+			u = ""
+		}
+	}
+	return strings.TrimSuffix(u, "/")
+}
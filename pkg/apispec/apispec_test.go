@@ -0,0 +1,320 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apispec_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/apispec"
+	"github.com/ethersphere/bee/pkg/logging"
+)
+
+const testMain = `
+openapi: 3.0.0
+servers:
+  - url: 'http://{host}/v1'
+  - url: 'http://{host}'
+paths:
+  '/items':
+    post:
+      parameters:
+        - in: query
+          name: count
+          schema:
+            type: integer
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: 'common.yaml#/components/schemas/Item'
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                allOf:
+                  - $ref: 'common.yaml#/components/schemas/Item'
+                  - type: object
+                    properties:
+                      id:
+                        type: string
+        '4XX':
+          $ref: 'common.yaml#/components/responses/Problem'
+  '/items/{id}':
+    parameters:
+      - in: path
+        name: id
+        required: true
+        schema:
+          $ref: 'common.yaml#/components/schemas/Id'
+    get:
+      responses:
+        '200':
+          description: Ok
+          content:
+            '*/*':
+              schema:
+                type: string
+        default:
+          $ref: 'common.yaml#/components/responses/Problem'
+    delete:
+      responses:
+        '204':
+          description: Deleted
+  '/items/latest':
+    get:
+      responses:
+        '200':
+          description: Ok
+  '/connect/{address}':
+    post:
+      responses:
+        '200':
+          description: Ok
+`
+
+const testCommon = `
+openapi: 3.0.0
+paths: {}
+components:
+  schemas:
+    Id:
+      oneOf:
+        - type: string
+          pattern: '^[0-9a-f]{4}$'
+        - type: string
+          pattern: '^[0-9a-f]{8}$'
+    Item:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        kind:
+          type: string
+          enum: [a, b]
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Item'
+  responses:
+    Problem:
+      description: Problem
+      content:
+        application/json:
+          schema:
+            type: object
+            additionalProperties: false
+            properties:
+              message:
+                type: string
+              code:
+                type: integer
+`
+
+func loadTestSpec(t *testing.T) *apispec.Spec {
+	t.Helper()
+
+	spec, err := apispec.Load("main.yaml", map[string]string{
+		"main.yaml":   testMain,
+		"common.yaml": testCommon,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestLoad(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	got := spec.Operations()
+	want := []apispec.Operation{
+		{Path: "/connect/{address}", Method: "POST"},
+		{Path: "/items", Method: "POST"},
+		{Path: "/items/latest", Method: "GET"},
+		{Path: "/items/{id}", Method: "DELETE"},
+		{Path: "/items/{id}", Method: "GET"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got operations %v, want %v", got, want)
+	}
+
+	for _, tc := range []struct {
+		template string
+		method   string
+		want     bool
+	}{
+		{template: "/items/{addr}", method: "GET", want: true},
+		{template: "/v1/items/{addr}", method: "GET", want: true},
+		{template: "/connect/{multi-address:.+}", method: "POST", want: true},
+		{template: "/items/{addr}", method: "POST", want: false},
+		{template: "/items/{addr}/children", method: "GET", want: false},
+		{template: "/v2/items", method: "POST", want: false},
+	} {
+		if got := spec.HasOperation(tc.template, tc.method); got != tc.want {
+			t.Errorf("has operation %s %s: got %v, want %v", tc.method, tc.template, got, tc.want)
+		}
+	}
+
+	_, err := apispec.Load("main.yaml", map[string]string{"main.yaml": testMain})
+	if err == nil {
+		t.Error("expected error for a missing referenced document")
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	for _, tc := range []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		err         string
+	}{
+		{name: "valid", method: "POST", path: "/items?count=2", contentType: "application/json", body: `{"name":"x","children":[{"name":"y","kind":"a"}]}`},
+		{name: "version prefix", method: "POST", path: "/v1/items", contentType: "application/json", body: `{"name":"x"}`},
+		{name: "query parameter", method: "POST", path: "/items?count=two", contentType: "application/json", body: `{"name":"x"}`, err: "query parameter count"},
+		{name: "missing body", method: "POST", path: "/items", err: "request body: required"},
+		{name: "content type", method: "POST", path: "/items", contentType: "text/plain", body: "x", err: "content type text/plain: not declared"},
+		{name: "invalid json", method: "POST", path: "/items", contentType: "application/json", body: "{", err: "invalid json"},
+		{name: "required property", method: "POST", path: "/items", contentType: "application/json", body: `{}`, err: "name: required property missing"},
+		{name: "nested enum", method: "POST", path: "/items", contentType: "application/json", body: `{"name":"x","children":[{"name":"y","kind":"c"}]}`, err: "children[0].kind"},
+		{name: "path parameter", method: "GET", path: "/items/abcd"},
+		{name: "long path parameter", method: "GET", path: "/items/abcdef01"},
+		{name: "invalid path parameter", method: "GET", path: "/items/xyz", err: "path parameter id"},
+		{name: "literal segment", method: "GET", path: "/items/latest"},
+		{name: "greedy parameter", method: "POST", path: "/connect/ip4/127.0.0.1/tcp/1634"},
+		{name: "unknown method", method: "PUT", path: "/items/abcd", err: apispec.ErrOperationNotFound.Error()},
+		{name: "unknown path", method: "GET", path: "/things", err: apispec.ErrOperationNotFound.Error()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			err := spec.ValidateRequest(r)
+			checkError(t, err, tc.err)
+			if tc.err == "" && tc.body != "" {
+				// the body has to be available to the handler
+				b, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(b) != tc.body {
+					t.Errorf("got body %q, want %q", b, tc.body)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	for _, tc := range []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+		body        string
+		err         string
+		strictErr   string // error of the strict spec if it differs
+	}{
+		{name: "valid", method: "POST", path: "/items", status: 201, contentType: "application/json", body: `{"name":"x","id":"1"}`},
+		{name: "invalid schema", method: "POST", path: "/items", status: 201, contentType: "application/json", body: `{"name":1}`, err: "status 201: name: expected string"},
+		{name: "undeclared property", method: "POST", path: "/items", status: 201, contentType: "application/json", body: `{"name":"x","nmae":"y"}`, strictErr: "status 201: nmae: property not declared"},
+		{name: "undeclared nested property", method: "POST", path: "/items", status: 201, contentType: "application/json", body: `{"name":"x","children":[{"name":"y","kid":"a"}]}`, strictErr: "status 201: children[0].kid: property not declared"},
+		{name: "status range", method: "POST", path: "/items", status: 404, contentType: "application/json; charset=utf-8", body: `{"message":"not found","code":404}`},
+		{name: "undeclared status", method: "POST", path: "/items", status: 500, contentType: "application/json", body: `{}`, err: "status 500: not declared"},
+		{name: "default", method: "GET", path: "/items/abcd", status: 500, contentType: "application/json", body: `{"message":"error","code":500}`},
+		{name: "default schema", method: "GET", path: "/items/abcd", status: 500, contentType: "application/json", body: `{"code":"500"}`, err: "code: expected integer"},
+		{name: "additional property", method: "GET", path: "/items/abcd", status: 500, contentType: "application/json", body: `{"message":"error","reason":"x"}`, err: "reason: property not allowed"},
+		{name: "media range", method: "GET", path: "/items/abcd", status: 200, contentType: "image/png", body: "png"},
+		{name: "empty body", method: "DELETE", path: "/items/abcd", status: 204},
+		{name: "unexpected body", method: "DELETE", path: "/items/abcd", status: 204, contentType: "text/plain", body: "deleted", err: "unexpected response body"},
+		{name: "content type", method: "POST", path: "/items", status: 201, contentType: "text/plain", body: "x", err: "content type text/plain: not declared"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			header := make(http.Header)
+			if tc.contentType != "" {
+				header.Set("Content-Type", tc.contentType)
+			}
+			err := spec.ValidateResponse(r, tc.status, header, []byte(tc.body))
+			checkError(t, err, tc.err)
+
+			strictErr := tc.err
+			if tc.strictErr != "" {
+				strictErr = tc.strictErr
+			}
+			err = spec.Strict().ValidateResponse(r, tc.status, header, []byte(tc.body))
+			checkError(t, err, strictErr)
+		})
+	}
+}
+
+func TestValidationHandler(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	var called bool
+	h := apispec.NewValidationHandler(logging.New(ioutil.Discard, 0), func(*http.Request) *apispec.Spec {
+		return spec
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range []struct {
+		path   string
+		code   int
+		called bool
+	}{
+		{path: "/items/abcd", code: http.StatusOK, called: true},
+		{path: "/items/xyz", code: http.StatusBadRequest, called: false},
+		{path: "/things", code: http.StatusOK, called: true},
+	} {
+		called = false
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.code {
+			t.Errorf("%s: got status %v, want %v", tc.path, w.Code, tc.code)
+		}
+		if called != tc.called {
+			t.Errorf("%s: got handler called %v, want %v", tc.path, called, tc.called)
+		}
+	}
+}
+
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+
+	if want == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil {
+		t.Fatalf("expected error %q", want)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("got error %q, want %q", err, want)
+	}
+	if want == apispec.ErrOperationNotFound.Error() && !errors.Is(err, apispec.ErrOperationNotFound) {
+		t.Fatalf("got error %v, want %v", err, apispec.ErrOperationNotFound)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apispec
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/logging"
+)
+
+// maxResponseBodySize is the maximal size of a response body that is
+// recorded for validation. Larger bodies are not validated against schemas.
+const maxResponseBodySize = 1 << 20
+
+// NewValidationHandler returns a handler middleware which validates requests
+// and responses against the document returned by the spec function for the
+// request. Requests that do not conform to the document are rejected with
+// the Bad Request status, while invalid responses, which are already sent,
+// are only logged. Requests without an operation in the document, or for
+// which the spec function returns nil, are not validated.
+func NewValidationHandler(logger logging.Logger, spec func(r *http.Request) *Spec) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := spec(r)
+			if s == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+			if err := s.ValidateRequest(r); err != nil {
+				if errors.Is(err, ErrOperationNotFound) {
+					h.ServeHTTP(w, r)
+					return
+				}
+				logger.Debugf("api spec: invalid request %s %s: %v", r.Method, r.URL.Path, err)
+				jsonhttp.BadRequest(w, "invalid request: "+err.Error())
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w}
+			h.ServeHTTP(rec, r)
+
+			if err := s.validateResponse(r, rec.status(), w.Header(), rec.body, !rec.truncated); err != nil {
+				logger.Debugf("api spec: invalid response %s %s: %v", r.Method, r.URL.Path, err)
+				logger.Error("api spec: invalid response")
+			}
+		})
+	}
+}
+
+// responseRecorder records the status code and the beginning
+// of the body written to the response.
+type responseRecorder struct {
+	http.ResponseWriter
+	code      int
+	body      []byte
+	truncated bool
+}
+
+func (rr *responseRecorder) WriteHeader(code int) {
+	if rr.code == 0 {
+		rr.code = code
+	}
+	rr.ResponseWriter.WriteHeader(code)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.code == 0 {
+		rr.code = http.StatusOK
+	}
+	if n := maxResponseBodySize - len(rr.body); n < len(b) {
+		rr.body = append(rr.body, b[:n]...)
+		rr.truncated = true
+	} else {
+		rr.body = append(rr.body, b...)
+	}
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) Flush() {
+	if f, ok := rr.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rr *responseRecorder) status() int {
+	if rr.code == 0 {
+		return http.StatusOK
+	}
+	return rr.code
+}
@@ -0,0 +1,295 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apispec
+
+import (
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+)
+
+// loader resolves references in the parsed documents.
+type loader struct {
+	docs    map[string]map[string]interface{}
+	schemas map[string]*Schema // schemas by their references, to support recursion
+}
+
+// resolve follows the reference of the node in the document, if the node
+// has one, and returns the referenced node and the name of its document.
+func (l *loader) resolve(doc string, node map[string]interface{}) (map[string]interface{}, string, error) {
+	for i := 0; ; i++ {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node, doc, nil
+		}
+		if i > 32 {
+			return nil, "", fmt.Errorf("too many references: %s", ref)
+		}
+		target, refDoc, err := l.lookup(doc, ref)
+		if err != nil {
+			return nil, "", err
+		}
+		node, doc = target, refDoc
+	}
+}
+
+// lookup returns the node referenced by the reference from the document.
+func (l *loader) lookup(doc, ref string) (map[string]interface{}, string, error) {
+	file, pointer := ref, ""
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		file, pointer = ref[:i], ref[i+1:]
+	}
+	if file == "" {
+		file = doc
+	}
+	var v interface{}
+	d, ok := l.docs[file]
+	if !ok {
+		return nil, "", fmt.Errorf("reference %s: document %s not found", ref, file)
+	}
+	v = d
+	for _, token := range strings.Split(strings.Trim(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("reference %s: invalid pointer", ref)
+		}
+		if v, ok = m[token]; !ok {
+			return nil, "", fmt.Errorf("reference %s: not found", ref)
+		}
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("reference %s: not an object", ref)
+	}
+	return m, file, nil
+}
+
+func (l *loader) operation(doc string, node map[string]interface{}, common []*parameter) (*operation, error) {
+	params, err := l.parameters(doc, list(node["parameters"]))
+	if err != nil {
+		return nil, err
+	}
+	op := &operation{
+		responses: make(map[string]*response),
+	}
+	// operation parameters override the path item parameters
+	for _, c := range common {
+		overridden := false
+		for _, p := range params {
+			if p.name == c.name && p.in == c.in {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			op.parameters = append(op.parameters, c)
+		}
+	}
+	op.parameters = append(op.parameters, params...)
+
+	if rb, ok := node["requestBody"].(map[string]interface{}); ok {
+		rb, rbDoc, err := l.resolve(doc, rb)
+		if err != nil {
+			return nil, fmt.Errorf("request body: %w", err)
+		}
+		content, err := l.content(rbDoc, obj(rb["content"]))
+		if err != nil {
+			return nil, fmt.Errorf("request body: %w", err)
+		}
+		op.requestBody = &requestBody{
+			required: rb["required"] == true,
+			content:  content,
+		}
+	}
+
+	for code, v := range obj(node["responses"]) {
+		r, rDoc, err := l.resolve(doc, obj(v))
+		if err != nil {
+			return nil, fmt.Errorf("response %s: %w", code, err)
+		}
+		content, err := l.content(rDoc, obj(r["content"]))
+		if err != nil {
+			return nil, fmt.Errorf("response %s: %w", code, err)
+		}
+		op.responses[strings.ToUpper(code)] = &response{
+			content: content,
+		}
+	}
+	return op, nil
+}
+
+func (l *loader) parameters(doc string, nodes []interface{}) ([]*parameter, error) {
+	params := make([]*parameter, 0, len(nodes))
+	for _, v := range nodes {
+		p, pDoc, err := l.resolve(doc, obj(v))
+		if err != nil {
+			return nil, fmt.Errorf("parameter: %w", err)
+		}
+		param := &parameter{
+			name:     str(p["name"]),
+			in:       str(p["in"]),
+			required: p["required"] == true,
+		}
+		if sv, ok := p["schema"].(map[string]interface{}); ok {
+			if param.schema, err = l.schema(pDoc, sv); err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", param.name, err)
+			}
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// content returns the schemas of the media types. Media types without
+// schemas have nil values.
+func (l *loader) content(doc string, node map[string]interface{}) (map[string]*Schema, error) {
+	if len(node) == 0 {
+		return nil, nil
+	}
+	content := make(map[string]*Schema, len(node))
+	for mediaType, v := range node {
+		var schema *Schema
+		if sv, ok := obj(v)["schema"].(map[string]interface{}); ok {
+			var err error
+			if schema, err = l.schema(doc, sv); err != nil {
+				return nil, fmt.Errorf("media type %s: %w", mediaType, err)
+			}
+		}
+		if mt, _, err := mime.ParseMediaType(mediaType); err == nil {
+			mediaType = mt
+		}
+		content[mediaType] = schema
+	}
+	return content, nil
+}
+
+func (l *loader) schema(doc string, node map[string]interface{}) (*Schema, error) {
+	var key string
+	if ref, ok := node["$ref"].(string); ok {
+		target, targetDoc, err := l.resolve(doc, node)
+		if err != nil {
+			return nil, err
+		}
+		file, pointer := ref, ""
+		if i := strings.IndexByte(ref, '#'); i >= 0 {
+			file, pointer = ref[:i], ref[i+1:]
+		}
+		if file == "" {
+			file = doc
+		}
+		key = file + "#" + pointer
+		if s, ok := l.schemas[key]; ok {
+			return s, nil
+		}
+		node, doc = target, targetDoc
+	}
+
+	s := &Schema{
+		Type:     str(node["type"]),
+		Format:   str(node["format"]),
+		Nullable: node["nullable"] == true,
+	}
+	if key != "" {
+		l.schemas[key] = s
+	}
+
+	if pattern := str(node["pattern"]); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+		s.Pattern = re
+	}
+	s.Enum = list(node["enum"])
+	for _, r := range list(node["required"]) {
+		s.Required = append(s.Required, str(r))
+	}
+	if props := obj(node["properties"]); len(props) > 0 {
+		s.Properties = make(map[string]*Schema, len(props))
+		for name, v := range props {
+			p, err := l.schema(doc, obj(v))
+			if err != nil {
+				return nil, fmt.Errorf("property %s: %w", name, err)
+			}
+			s.Properties[name] = p
+		}
+	}
+	if items, ok := node["items"].(map[string]interface{}); ok {
+		var err error
+		if s.Items, err = l.schema(doc, items); err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+	}
+	if node["additionalProperties"] == false {
+		s.NoAdditionalProperties = true
+	}
+	if ap, ok := node["additionalProperties"].(map[string]interface{}); ok {
+		var err error
+		if s.AdditionalProperties, err = l.schema(doc, ap); err != nil {
+			return nil, fmt.Errorf("additional properties: %w", err)
+		}
+	}
+	for _, c := range []struct {
+		name   string
+		target *[]*Schema
+	}{
+		{name: "oneOf", target: &s.OneOf},
+		{name: "anyOf", target: &s.AnyOf},
+		{name: "allOf", target: &s.AllOf},
+	} {
+		for _, v := range list(node[c.name]) {
+			sub, err := l.schema(doc, obj(v))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", c.name, err)
+			}
+			*c.target = append(*c.target, sub)
+		}
+	}
+	return s, nil
+}
+
+// normalize converts YAML maps to maps with string keys, as
+// the keys like response status codes are decoded as integers.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normalize(e)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalize(e)
+		}
+		return v
+	}
+	return v
+}
+
+func obj(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,269 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apispec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a schema object of the OpenAPI document.
+type Schema struct {
+	Type                 string
+	Format               string
+	Nullable             bool
+	Pattern              *regexp.Regexp
+	Enum                 []interface{}
+	Required             []string
+	Properties           map[string]*Schema
+	AdditionalProperties *Schema
+	// NoAdditionalProperties is set if additionalProperties is false
+	// and only the properties of the schema are allowed.
+	NoAdditionalProperties bool
+	Items                  *Schema
+	OneOf                  []*Schema
+	AnyOf                  []*Schema
+	AllOf                  []*Schema
+}
+
+// Validate returns an error if the value decoded from JSON
+// does not conform to the schema.
+func (s *Schema) Validate(v interface{}) error {
+	return s.validate("", v, validateOptions{})
+}
+
+// validateOptions configure the validation of a value.
+type validateOptions struct {
+	// strict rejects properties that are not declared by object schemas
+	// with properties, unless they allow additional properties by a schema
+	strict bool
+	// composed is set for schemas of compositions, which are validated
+	// against the same value as the schema that composes them
+	composed bool
+}
+
+func (s *Schema) validate(path string, v interface{}, o validateOptions) error {
+	if v == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return fieldError(path, "null value")
+	}
+
+	switch s.Type {
+	case "":
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fieldError(path, "expected object, got %T", v)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fieldError(join(path, name), "required property missing")
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p, ok := s.Properties[name]
+			if !ok {
+				if s.NoAdditionalProperties {
+					return fieldError(join(path, name), "property not allowed")
+				}
+				p = s.AdditionalProperties
+			}
+			if p == nil {
+				continue
+			}
+			if err := p.validate(join(path, name), obj[name], validateOptions{strict: o.strict}); err != nil {
+				return err
+			}
+		}
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok {
+			return fieldError(path, "expected array, got %T", v)
+		}
+		if s.Items != nil {
+			for i, e := range a {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), e, validateOptions{strict: o.strict}); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fieldError(path, "expected string, got %T", v)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(str) {
+			return fieldError(path, "value %q does not match pattern %s", str, s.Pattern)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				return fieldError(path, "value %q is not a date-time", str)
+			}
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fieldError(path, "expected integer, got %v", v)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fieldError(path, "expected number, got %T", v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fieldError(path, "expected boolean, got %T", v)
+		}
+	default:
+		return fieldError(path, "unsupported schema type %s", s.Type)
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fieldError(path, "value %v is not one of %v", v, s.Enum)
+		}
+	}
+
+	if obj, ok := v.(map[string]interface{}); ok && o.strict && !o.composed {
+		if err := s.validateDeclared(path, obj); err != nil {
+			return err
+		}
+	}
+
+	composed := validateOptions{strict: o.strict, composed: true}
+	for _, sub := range s.AllOf {
+		if err := sub.validate(path, v, composed); err != nil {
+			return err
+		}
+	}
+	if len(s.AnyOf) > 0 {
+		var err error
+		for _, sub := range s.AnyOf {
+			if err = sub.validate(path, v, composed); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return fieldError(path, "value does not match any schema: %v", err)
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matched := 0
+		var lastErr error
+		for _, sub := range s.OneOf {
+			if err := sub.validate(path, v, composed); err != nil {
+				lastErr = err
+			} else {
+				matched++
+			}
+		}
+		if matched != 1 {
+			if matched == 0 {
+				return fieldError(path, "value does not match any schema: %v", lastErr)
+			}
+			return fieldError(path, "value matches %d schemas instead of one", matched)
+		}
+	}
+	return nil
+}
+
+// validateString validates a parameter value which is
+// converted to the type of the schema.
+func (s *Schema) validateString(path, str string) error {
+	var v interface{} = str
+	switch s.Type {
+	case "integer", "number":
+		n, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return fieldError(path, "expected %s, got %q", s.Type, str)
+		}
+		v = n
+	case "boolean":
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return fieldError(path, "expected boolean, got %q", str)
+		}
+		v = b
+	case "":
+		// composed schemas of parameters are validated with string values
+	}
+	return s.validate(path, v, validateOptions{})
+}
+
+// validateDeclared returns an error if the object has a property that is not
+// declared by the schema or the schemas that it is composed of. Objects are
+// not checked if no properties are declared or if additional properties are
+// allowed by a schema.
+func (s *Schema) validateDeclared(path string, obj map[string]interface{}) error {
+	declared := make(map[string]struct{})
+	if !s.declaredProperties(declared) || len(declared) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := declared[name]; !ok {
+			return fieldError(join(path, name), "property not declared")
+		}
+	}
+	return nil
+}
+
+// declaredProperties adds the names of the properties of the schema and the
+// schemas that it is composed of to the declared set. It returns false if
+// any of the schemas allows additional properties by a schema.
+func (s *Schema) declaredProperties(declared map[string]struct{}) bool {
+	if s.AdditionalProperties != nil {
+		return false
+	}
+	for name := range s.Properties {
+		declared[name] = struct{}{}
+	}
+	for _, c := range [][]*Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, sub := range c {
+			if !sub.declaredProperties(declared) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func fieldError(path, format string, a ...interface{}) error {
+	msg := fmt.Sprintf(format, a...)
+	if path != "" {
+		msg = path + ": " + msg
+	}
+	return errors.New(strings.TrimSpace(msg))
+}
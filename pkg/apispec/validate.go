@@ -0,0 +1,192 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apispec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxRequestBodySize is the maximal size of a JSON request body
+// that is read for validation.
+const maxRequestBodySize = 1 << 20
+
+// ValidateRequest returns an error if the request does not conform to the
+// operation of the document that matches its method and path. The error
+// wraps ErrOperationNotFound if there is no such operation.
+//
+// Parameters are validated for all requests, but the body is validated
+// against the schema only for JSON media types. The body is restored so
+// that it can be read again by the handler.
+func (s *Spec) ValidateRequest(r *http.Request) error {
+	op, pathParams, err := s.find(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+
+	query := r.URL.Query()
+	for _, p := range op.parameters {
+		var (
+			value   string
+			present bool
+		)
+		switch p.in {
+		case "path":
+			value, present = pathParams[p.name]
+		case "query":
+			if v, ok := query[p.name]; ok && len(v) > 0 {
+				value, present = v[0], true
+			}
+		case "header":
+			if v := r.Header.Values(p.name); len(v) > 0 {
+				value, present = v[0], true
+			}
+		default:
+			continue
+		}
+		if !present {
+			if p.required {
+				return fmt.Errorf("%s parameter %s: required", p.in, p.name)
+			}
+			continue
+		}
+		if p.schema == nil {
+			continue
+		}
+		if err := p.schema.validateString("", value); err != nil {
+			return fmt.Errorf("%s parameter %s: %w", p.in, p.name, err)
+		}
+	}
+
+	rb := op.requestBody
+	if rb == nil {
+		return nil
+	}
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		if rb.required {
+			return errors.New("request body: required")
+		}
+		return nil
+	}
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" || len(rb.content) == 0 {
+		return nil
+	}
+	mediaType, schema, err := matchContent(rb.content, contentType)
+	if err != nil {
+		return fmt.Errorf("request body: %w", err)
+	}
+	if schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBodySize+1))
+	if err != nil {
+		return fmt.Errorf("request body: %w", err)
+	}
+	r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if len(body) > maxRequestBodySize {
+		return nil
+	}
+	if err := validateJSON(schema, body, validateOptions{}); err != nil {
+		return fmt.Errorf("request body: %w", err)
+	}
+	return nil
+}
+
+// ValidateResponse returns an error if the response with the status code,
+// header and body does not conform to the operation of the document that
+// matches the method and path of the request. The error wraps
+// ErrOperationNotFound if there is no such operation.
+//
+// The status code has to be declared in the operation, as a specific code,
+// a range like 4XX or with the default response. Response bodies are
+// accepted only for responses that declare their media types and the JSON
+// bodies are validated against the schema. Properties that are not declared
+// by the schema are rejected only by the strict spec.
+func (s *Spec) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	return s.validateResponse(r, status, header, body, true)
+}
+
+// validateResponse validates the response. The schema of the body is not
+// validated if the body is not complete.
+func (s *Spec) validateResponse(r *http.Request, status int, header http.Header, body []byte, complete bool) error {
+	op, _, err := s.find(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+
+	code := strconv.Itoa(status)
+	resp, ok := op.responses[code]
+	if !ok {
+		resp, ok = op.responses[code[:1]+"XX"]
+	}
+	if !ok {
+		resp, ok = op.responses["DEFAULT"]
+	}
+	if !ok {
+		return fmt.Errorf("status %d: not declared", status)
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+	if len(resp.content) == 0 {
+		return fmt.Errorf("status %d: unexpected response body", status)
+	}
+	mediaType, schema, err := matchContent(resp.content, header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("status %d: %w", status, err)
+	}
+	if !complete || schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+	if err := validateJSON(schema, body, validateOptions{strict: s.strict}); err != nil {
+		return fmt.Errorf("status %d: %w", status, err)
+	}
+	return nil
+}
+
+// matchContent returns the declared media type that matches the content
+// type with its schema. Exact media types are preferred over media ranges
+// like text/* and */*.
+func matchContent(content map[string]*Schema, contentType string) (string, *Schema, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", nil, fmt.Errorf("content type %q: %w", contentType, err)
+	}
+	if schema, ok := content[mediaType]; ok {
+		return mediaType, schema, nil
+	}
+	if i := strings.IndexByte(mediaType, '/'); i >= 0 {
+		if schema, ok := content[mediaType[:i]+"/*"]; ok {
+			return mediaType, schema, nil
+		}
+	}
+	if schema, ok := content["*/*"]; ok {
+		return mediaType, schema, nil
+	}
+	return "", nil, fmt.Errorf("content type %s: not declared", mediaType)
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func validateJSON(schema *Schema, data []byte, o validateOptions) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+	return schema.validate("", v, o)
+}
@@ -0,0 +1,254 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package auth provides issuing and validation of scoped bearer tokens
+// that are used to authenticate requests to the HTTP APIs.
+//
+// Tokens are issued in exchange for the node password and are persisted in
+// the state store by their hash, so that the tokens themselves can not be
+// recovered from the store.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/statestore"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// Scope defines a set of API operations that a token grants access to.
+type Scope string
+
+const (
+	// ScopeRead grants access to content retrieval.
+	ScopeRead Scope = "read"
+	// ScopeUpload grants access to content uploads.
+	ScopeUpload Scope = "upload"
+	// ScopePin grants access to pinning.
+	ScopePin Scope = "pin"
+	// ScopeAdmin grants access to all operations, including the debug API.
+	ScopeAdmin Scope = "admin"
+)
+
+// DefaultExpiry is the token lifetime used if none is requested.
+const DefaultExpiry = 24 * time.Hour
+
+var (
+	// ErrInvalidPassword is returned when a token is requested
+	// with a wrong password.
+	ErrInvalidPassword = errors.New("invalid password")
+	// ErrUnauthorized is returned when a token is not known
+	// or it is expired.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is returned when a token does not grant
+	// the required scope.
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidScope is returned when a token is requested
+	// with an unknown scope.
+	ErrInvalidScope = errors.New("invalid scope")
+)
+
+// Authenticator issues, validates and revokes tokens.
+type Authenticator struct {
+	store        storage.StateStorer
+	passwordHash [sha256.Size]byte
+}
+
+// New constructs a new Authenticator which issues tokens
+// to the clients that present the password.
+func New(store storage.StateStorer, password string) *Authenticator {
+	return &Authenticator{
+		store:        statestore.WithNamespace(store, statestore.AuthNamespace),
+		passwordHash: sha256.Sum256([]byte(password)),
+	}
+}
+
+// token is the persisted state of an issued token.
+type token struct {
+	Scopes  []Scope   `json:"scopes"`
+	Expires time.Time `json:"expires"`
+}
+
+// Issue returns a new token with the scopes which expires after the
+// expiry duration, or DefaultExpiry if it is not positive. Expired
+// tokens are removed, so that tokens that are not used again do not
+// accumulate in the store.
+func (a *Authenticator) Issue(password string, scopes []Scope, expiry time.Duration) (string, time.Time, error) {
+	if !a.validPassword(password) {
+		return "", time.Time{}, ErrInvalidPassword
+	}
+	if len(scopes) == 0 {
+		return "", time.Time{}, ErrInvalidScope
+	}
+	for _, s := range scopes {
+		if !validScope(s) {
+			return "", time.Time{}, fmt.Errorf("%w: %q", ErrInvalidScope, s)
+		}
+	}
+	if expiry <= 0 {
+		expiry = DefaultExpiry
+	}
+	if err := a.removeExpired(); err != nil {
+		return "", time.Time{}, fmt.Errorf("remove expired tokens: %w", err)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	key := hex.EncodeToString(b)
+
+	t := token{
+		Scopes:  scopes,
+		Expires: time.Now().Add(expiry).UTC(),
+	}
+	if err := a.store.Put(storeKey(key), t); err != nil {
+		return "", time.Time{}, err
+	}
+	return key, t.Expires, nil
+}
+
+// Authorize returns nil if the token is valid and grants the scope.
+// Tokens with the admin scope grant all scopes.
+func (a *Authenticator) Authorize(key string, scope Scope) error {
+	if key == "" {
+		return ErrUnauthorized
+	}
+	var t token
+	if err := a.store.Get(storeKey(key), &t); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrUnauthorized
+		}
+		return err
+	}
+	if time.Now().After(t.Expires) {
+		// expired tokens are removed when they are used
+		if err := a.store.Delete(storeKey(key)); err != nil {
+			return err
+		}
+		return ErrUnauthorized
+	}
+	for _, s := range t.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// Revoke removes the token, so that it can not be used anymore.
+func (a *Authenticator) Revoke(password, key string) error {
+	if !a.validPassword(password) {
+		return ErrInvalidPassword
+	}
+	var t token
+	if err := a.store.Get(storeKey(key), &t); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrUnauthorized
+		}
+		return err
+	}
+	return a.store.Delete(storeKey(key))
+}
+
+// Middleware returns a handler middleware which authorizes requests with
+// the bearer token from the Authorization header for the scope returned by
+// the scope function. Requests with an empty scope are not authorized.
+func (a *Authenticator) Middleware(logger logging.Logger, scope func(r *http.Request) Scope) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sc := scope(r)
+			if sc == "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+			err := a.Authorize(BearerToken(r), sc)
+			switch {
+			case err == nil:
+				h.ServeHTTP(w, r)
+			case errors.Is(err, ErrUnauthorized):
+				w.Header().Set("WWW-Authenticate", `Bearer realm="bee"`)
+				jsonhttp.Unauthorized(w, nil)
+			case errors.Is(err, ErrForbidden):
+				jsonhttp.Forbidden(w, nil)
+			default:
+				logger.Debugf("auth: authorize: %v", err)
+				logger.Error("auth: authorize")
+				jsonhttp.InternalServerError(w, nil)
+			}
+		})
+	}
+}
+
+// BearerToken returns the token from the Authorization header
+// of the request or an empty string if there is none.
+func BearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	v := r.Header.Get("Authorization")
+	if len(v) < len(prefix) || !strings.EqualFold(v[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(v[len(prefix):])
+}
+
+// TokenID returns an identifier of the token, which
+// can be stored and shown without revealing the token.
+func TokenID(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:8])
+}
+
+// removeExpired removes all expired tokens from the store.
+func (a *Authenticator) removeExpired() error {
+	now := time.Now()
+	var expired []string
+	err := a.store.Iterate("", func(key, value []byte) (stop bool, err error) {
+		var t token
+		if err := json.Unmarshal(value, &t); err != nil {
+			return true, fmt.Errorf("token %s: %w", key, err)
+		}
+		if now.After(t.Expires) {
+			expired = append(expired, string(key))
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range expired {
+		if err := a.store.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Authenticator) validPassword(password string) bool {
+	h := sha256.Sum256([]byte(password))
+	return subtle.ConstantTimeCompare(h[:], a.passwordHash[:]) == 1
+}
+
+func storeKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+func validScope(s Scope) bool {
+	switch s {
+	case ScopeRead, ScopeUpload, ScopePin, ScopeAdmin:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/statestore"
+	mockstore "github.com/ethersphere/bee/pkg/statestore/mock"
+)
+
+const password = "secret"
+
+func TestIssue(t *testing.T) {
+	a := auth.New(mockstore.NewStateStore(), password)
+
+	if _, _, err := a.Issue("wrong", []auth.Scope{auth.ScopeRead}, 0); !errors.Is(err, auth.ErrInvalidPassword) {
+		t.Fatalf("got error %v, want %v", err, auth.ErrInvalidPassword)
+	}
+	if _, _, err := a.Issue(password, nil, 0); !errors.Is(err, auth.ErrInvalidScope) {
+		t.Fatalf("got error %v, want %v", err, auth.ErrInvalidScope)
+	}
+	if _, _, err := a.Issue(password, []auth.Scope{"unknown"}, 0); !errors.Is(err, auth.ErrInvalidScope) {
+		t.Fatalf("got error %v, want %v", err, auth.ErrInvalidScope)
+	}
+
+	start := time.Now()
+	key, expires, err := a.Issue(password, []auth.Scope{auth.ScopeRead}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key == "" {
+		t.Fatal("empty token")
+	}
+	if expires.Before(start.Add(auth.DefaultExpiry)) {
+		t.Fatalf("got expiry %v, want at least %v", expires, start.Add(auth.DefaultExpiry))
+	}
+
+	other, _, err := a.Issue(password, []auth.Scope{auth.ScopeRead}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == key {
+		t.Fatal("issued the same token twice")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	a := auth.New(mockstore.NewStateStore(), password)
+
+	read, _, err := a.Issue(password, []auth.Scope{auth.ScopeRead, auth.ScopePin}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, _, err := a.Issue(password, []auth.Scope{auth.ScopeAdmin}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		key   string
+		scope auth.Scope
+		err   error
+	}{
+		{name: "granted scope", key: read, scope: auth.ScopeRead},
+		{name: "other granted scope", key: read, scope: auth.ScopePin},
+		{name: "not granted scope", key: read, scope: auth.ScopeUpload, err: auth.ErrForbidden},
+		{name: "admin", key: admin, scope: auth.ScopeUpload},
+		{name: "empty token", key: "", scope: auth.ScopeRead, err: auth.ErrUnauthorized},
+		{name: "unknown token", key: "unknown", scope: auth.ScopeRead, err: auth.ErrUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := a.Authorize(tc.key, tc.scope); !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	a := auth.New(mockstore.NewStateStore(), password)
+
+	key, _, err := a.Issue(password, []auth.Scope{auth.ScopeRead}, time.Nanosecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+
+	if err := a.Authorize(key, auth.ScopeRead); !errors.Is(err, auth.ErrUnauthorized) {
+		t.Fatalf("got error %v, want %v", err, auth.ErrUnauthorized)
+	}
+}
+
+func TestExpiry_issue(t *testing.T) {
+	store := mockstore.NewStateStore()
+	a := auth.New(store, password)
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := a.Issue(password, []auth.Scope{auth.ScopeRead}, time.Nanosecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(time.Millisecond)
+
+	// expired tokens are removed when a new one is issued
+	key, _, err := a.Issue(password, []auth.Scope{auth.ScopeRead}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := store.Iterate(string(statestore.AuthNamespace), func(_, _ []byte) (stop bool, err error) {
+		count++
+		return false, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("got %v stored tokens, want 1", count)
+	}
+	if err := a.Authorize(key, auth.ScopeRead); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	a := auth.New(mockstore.NewStateStore(), password)
+
+	key, _, err := a.Issue(password, []auth.Scope{auth.ScopeRead}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Revoke("wrong", key); !errors.Is(err, auth.ErrInvalidPassword) {
+		t.Fatalf("got error %v, want %v", err, auth.ErrInvalidPassword)
+	}
+	if err := a.Authorize(key, auth.ScopeRead); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Revoke(password, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Authorize(key, auth.ScopeRead); !errors.Is(err, auth.ErrUnauthorized) {
+		t.Fatalf("got error %v, want %v", err, auth.ErrUnauthorized)
+	}
+	if err := a.Revoke(password, key); !errors.Is(err, auth.ErrUnauthorized) {
+		t.Fatalf("got error %v, want %v", err, auth.ErrUnauthorized)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: ""},
+		{header: "Bearer abcd", want: "abcd"},
+		{header: "bearer abcd", want: "abcd"},
+		{header: "Basic abcd", want: ""},
+	} {
+		r, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.header != "" {
+			r.Header.Set("Authorization", tc.header)
+		}
+		if got := auth.BearerToken(r); got != tc.want {
+			t.Errorf("header %q: got token %q, want %q", tc.header, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,572 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package blobstore provides an append-only store of size limited blobs in
+// fixed size slots of a number of shard files. Slots of released blobs are
+// reused for new ones, so the files grow only when there are no free slots.
+//
+// The store does not keep track of which blobs it holds, the locations
+// returned by Write must be kept by the user. Free slots are persisted on
+// Close. If the store is not closed properly, it is in recovery until the
+// user marks the locations of all kept blobs and calls Recovered.
+//
+// Written blobs are durable only after Sync, which must be called before
+// their locations are persisted by the user.
+package blobstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	// ErrNotFound is returned by Read if the slot
+	// at the location holds a different blob.
+	ErrNotFound = errors.New("blobstore: not found")
+	// ErrTooLarge is returned by Write if the data
+	// is larger than the maximal data size.
+	ErrTooLarge = errors.New("blobstore: data too large")
+	// ErrInvalidLocation is returned if the location can not be decoded
+	// or it is outside of the store.
+	ErrInvalidLocation = errors.New("blobstore: invalid location")
+)
+
+const (
+	// LocationSize is the length of the encoded location.
+	LocationSize = 15
+	// slotHeaderSize is the length of the generation stored before
+	// the data in the slot to detect reads of reused slots.
+	slotHeaderSize = 8
+	// freeSlotsFilename is the name of the file with persisted free slots.
+	freeSlotsFilename = "free_slots"
+	// generationFilename is the name of the file with the persisted
+	// limit of generations that may have been used for writes.
+	generationFilename = "generation"
+	// generationsReserve is the number of generations that are reserved
+	// by persisting the limit, so that it is not written on every write.
+	generationsReserve = 1 << 16
+)
+
+// Location is the position of a blob in the store.
+type Location struct {
+	Shard uint8
+	Slot  uint32
+	// Generation is unique for every write, so that reads of
+	// locations of released blobs are detected.
+	Generation uint64
+	Length     uint16
+}
+
+// Bytes returns the binary encoding of the location.
+func (l Location) Bytes() []byte {
+	b := make([]byte, LocationSize)
+	b[0] = l.Shard
+	binary.BigEndian.PutUint32(b[1:5], l.Slot)
+	binary.BigEndian.PutUint64(b[5:13], l.Generation)
+	binary.BigEndian.PutUint16(b[13:15], l.Length)
+	return b
+}
+
+// ParseLocation decodes the location from its binary encoding.
+func ParseLocation(b []byte) (l Location, err error) {
+	if len(b) != LocationSize {
+		return l, ErrInvalidLocation
+	}
+	return Location{
+		Shard:      b[0],
+		Slot:       binary.BigEndian.Uint32(b[1:5]),
+		Generation: binary.BigEndian.Uint64(b[5:13]),
+		Length:     binary.BigEndian.Uint16(b[13:15]),
+	}, nil
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("%d/%d", l.Shard, l.Slot)
+}
+
+// Options holds optional parameters of the Store.
+type Options struct {
+	// Shards is the number of shard files, 32 by default.
+	Shards int
+	// MaxDataSize is the maximal length of a blob, 4104 by default,
+	// which is the size of a chunk with its span.
+	MaxDataSize int
+}
+
+// Store keeps blobs in shard files.
+type Store struct {
+	dir      string
+	slotSize int64
+	maxData  int
+	shards   []*shard
+
+	mu              sync.Mutex
+	next            int    // round robin shard for writes
+	generation      uint64 // last write generation
+	generationLimit uint64 // persisted limit of write generations
+	recovering      bool
+
+	syncMu sync.Mutex // serializes syncs of shard files
+}
+
+type shard struct {
+	file file
+	// slots is the number of slots in the file
+	slots uint32
+	free  []uint32
+	// used marks slots of kept blobs during recovery
+	// among the slots that were in the file when opened
+	used      map[uint32]struct{}
+	usedSlots uint32
+	// dirty is set if the file is written after the last sync
+	dirty bool
+}
+
+// file is the storage of a shard.
+type file interface {
+	io.ReaderAt
+	io.WriterAt
+	io.Closer
+	Size() (int64, error)
+	Sync() error
+}
+
+// New opens or creates a store in the directory. The store is kept
+// in memory if the directory is an empty string.
+func New(dir string, o Options) (s *Store, err error) {
+	if o.Shards <= 0 {
+		o.Shards = 32
+	}
+	if o.Shards > 256 {
+		return nil, fmt.Errorf("blobstore: too many shards %d", o.Shards)
+	}
+	if o.MaxDataSize <= 0 {
+		o.MaxDataSize = 4104
+	}
+	if o.MaxDataSize > 1<<16-1 {
+		return nil, fmt.Errorf("blobstore: too large data size %d", o.MaxDataSize)
+	}
+	s = &Store{
+		dir:      dir,
+		slotSize: int64(slotHeaderSize + o.MaxDataSize),
+		maxData:  o.MaxDataSize,
+		shards:   make([]*shard, o.Shards),
+	}
+	defer func() {
+		if err != nil {
+			s.closeFiles()
+		}
+	}()
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	var empty = true
+	for i := range s.shards {
+		var f file
+		if dir == "" {
+			f = new(memFile)
+		} else {
+			f, err = openOSFile(filepath.Join(dir, fmt.Sprintf("shard_%03d", i)))
+			if err != nil {
+				return nil, err
+			}
+		}
+		size, err := f.Size()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		slots := (size + s.slotSize - 1) / s.slotSize
+		if slots > 0 {
+			empty = false
+		}
+		s.shards[i] = &shard{
+			file:  f,
+			slots: uint32(slots),
+		}
+	}
+	// generations are increasing across restarts, also if
+	// the store was not closed, as they are reserved before use
+	if dir != "" {
+		s.generation, err = readGeneration(dir)
+		if os.IsNotExist(err) {
+			// stores without the persisted limit are
+			// scanned for the last used generation
+			s.generation, err = s.lastGeneration()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.reserveGenerations(); err != nil {
+		return nil, err
+	}
+	if dir == "" || empty {
+		return s, nil
+	}
+
+	// free slots are known only if the store was closed
+	freeSlotsPath := filepath.Join(dir, freeSlotsFilename)
+	b, err := ioutil.ReadFile(freeSlotsPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		s.recovering = true
+		for _, sh := range s.shards {
+			sh.used = make(map[uint32]struct{})
+			sh.usedSlots = sh.slots
+		}
+		return s, nil
+	}
+	if err := s.decodeFreeSlots(b); err != nil {
+		return nil, err
+	}
+	// free slots change from now on, so they
+	// must be recovered if the store is not closed
+	if err := os.Remove(freeSlotsPath); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write stores the data in a free slot, or in a new one if there are no
+// free slots, and returns its location.
+func (s *Store) Write(data []byte) (loc Location, err error) {
+	if len(data) > s.maxData {
+		return loc, ErrTooLarge
+	}
+
+	s.mu.Lock()
+	if s.generation == s.generationLimit {
+		if err := s.reserveGenerations(); err != nil {
+			s.mu.Unlock()
+			return loc, err
+		}
+	}
+	i := s.next
+	s.next = (s.next + 1) % len(s.shards)
+	sh := s.shards[i]
+	var slot uint32
+	if n := len(sh.free); n > 0 {
+		slot = sh.free[n-1]
+		sh.free = sh.free[:n-1]
+	} else {
+		slot = sh.slots
+		sh.slots++
+	}
+	s.generation++
+	loc = Location{
+		Shard:      uint8(i),
+		Slot:       slot,
+		Generation: s.generation,
+		Length:     uint16(len(data)),
+	}
+	s.mu.Unlock()
+
+	b := make([]byte, slotHeaderSize+len(data))
+	binary.BigEndian.PutUint64(b[:slotHeaderSize], loc.Generation)
+	copy(b[slotHeaderSize:], data)
+	if _, err := sh.file.WriteAt(b, int64(slot)*s.slotSize); err != nil {
+		s.Release(loc)
+		return Location{}, err
+	}
+	// the file is marked after it is written,
+	// so that the next sync includes the blob
+	s.mu.Lock()
+	sh.dirty = true
+	s.mu.Unlock()
+	return loc, nil
+}
+
+// Sync commits blobs written to the shard files since the last sync
+// to stable storage.
+func (s *Store) Sync() error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	s.mu.Lock()
+	var dirty []*shard
+	for _, sh := range s.shards {
+		if sh.dirty {
+			sh.dirty = false
+			dirty = append(dirty, sh)
+		}
+	}
+	s.mu.Unlock()
+
+	for i, sh := range dirty {
+		if err := sh.file.Sync(); err != nil {
+			s.mu.Lock()
+			for _, sh := range dirty[i:] {
+				sh.dirty = true
+			}
+			s.mu.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+// Read returns the data at the location. It returns ErrNotFound if the slot
+// is released and holds another blob.
+func (s *Store) Read(loc Location) (data []byte, err error) {
+	if int(loc.Shard) >= len(s.shards) || int(loc.Length) > s.maxData {
+		return nil, ErrInvalidLocation
+	}
+	b := make([]byte, slotHeaderSize+int(loc.Length))
+	n, err := s.shards[loc.Shard].file.ReadAt(b, int64(loc.Slot)*s.slotSize)
+	if n < len(b) {
+		if err == nil || errors.Is(err, io.EOF) {
+			// the slot is not written
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if binary.BigEndian.Uint64(b[:slotHeaderSize]) != loc.Generation {
+		return nil, ErrNotFound
+	}
+	return b[slotHeaderSize:], nil
+}
+
+// Release frees the slot at the location for new blobs. It must
+// be called only once for a location returned by Write.
+func (s *Store) Release(loc Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if int(loc.Shard) >= len(s.shards) {
+		return
+	}
+	sh := s.shards[loc.Shard]
+	if sh.used != nil {
+		// free slots are not known until recovered
+		return
+	}
+	sh.free = append(sh.free, loc.Slot)
+}
+
+// Recovering reports whether the store was not closed properly and
+// free slots are not known. New blobs are written to new slots
+// while the store is in recovery.
+func (s *Store) Recovering() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.recovering
+}
+
+// MarkUsed marks the slot at the location as used during recovery.
+func (s *Store) MarkUsed(loc Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.recovering || int(loc.Shard) >= len(s.shards) {
+		return
+	}
+	s.shards[loc.Shard].used[loc.Slot] = struct{}{}
+}
+
+// Recovered ends the recovery, all slots that are not marked
+// as used and not written during recovery are freed.
+func (s *Store) Recovered() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.recovering {
+		return
+	}
+	for _, sh := range s.shards {
+		// slots written during recovery are used
+		for slot := uint32(0); slot < sh.usedSlots; slot++ {
+			if _, ok := sh.used[slot]; !ok {
+				sh.free = append(sh.free, slot)
+			}
+		}
+		sh.used = nil
+	}
+	s.recovering = false
+}
+
+// Size returns the total size of the shard files in bytes.
+func (s *Store) Size() (size int64, err error) {
+	for _, sh := range s.shards {
+		n, err := sh.file.Size()
+		if err != nil {
+			return 0, err
+		}
+		size += n
+	}
+	return size, nil
+}
+
+// FreeSize returns the size of the free slots in bytes. Free slots are
+// not known and not counted while the store is in recovery.
+func (s *Store) FreeSize() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var free int
+	for _, sh := range s.shards {
+		free += len(sh.free)
+	}
+	return int64(free) * s.slotSize
+}
+
+// Close persists the free slots and closes the shard files.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.closeFiles(); err != nil {
+		return err
+	}
+	if s.dir == "" || s.recovering {
+		return nil
+	}
+	return ioutil.WriteFile(filepath.Join(s.dir, freeSlotsFilename), s.encodeFreeSlots(), 0600)
+}
+
+// reserveGenerations persists the limit of generations that may be used
+// for writes. It must be called with the mu lock held.
+func (s *Store) reserveGenerations() error {
+	limit := s.generation + generationsReserve
+	if s.dir != "" {
+		if err := writeGeneration(s.dir, limit); err != nil {
+			return fmt.Errorf("blobstore: reserve generations: %w", err)
+		}
+	}
+	s.generationLimit = limit
+	return nil
+}
+
+// lastGeneration returns the largest generation in the slot headers.
+func (s *Store) lastGeneration() (generation uint64, err error) {
+	b := make([]byte, slotHeaderSize)
+	for _, sh := range s.shards {
+		for slot := uint32(0); slot < sh.slots; slot++ {
+			n, err := sh.file.ReadAt(b, int64(slot)*s.slotSize)
+			if n < len(b) {
+				if err == nil || errors.Is(err, io.EOF) {
+					continue
+				}
+				return 0, err
+			}
+			if g := binary.BigEndian.Uint64(b); g > generation {
+				generation = g
+			}
+		}
+	}
+	return generation, nil
+}
+
+// readGeneration returns the persisted limit of generations.
+func readGeneration(dir string) (uint64, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, generationFilename))
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, errors.New("blobstore: invalid generation")
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// writeGeneration persists the limit of generations. The limit is written
+// to a temporary file which replaces the previous one, so that it is not
+// lost if writing is interrupted.
+func writeGeneration(dir string, generation uint64) error {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, generation)
+
+	path := filepath.Join(dir, generationFilename)
+	f, err := os.OpenFile(path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
+
+func (s *Store) closeFiles() (err error) {
+	for _, sh := range s.shards {
+		if sh == nil {
+			continue
+		}
+		if e := sh.file.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// encodeFreeSlots returns the number of free slots followed
+// by the free slots for every shard.
+func (s *Store) encodeFreeSlots() []byte {
+	var b []byte
+	for _, sh := range s.shards {
+		n := make([]byte, 4, 4+4*len(sh.free))
+		binary.BigEndian.PutUint32(n, uint32(len(sh.free)))
+		b = append(b, n...)
+		for _, slot := range sh.free {
+			var v [4]byte
+			binary.BigEndian.PutUint32(v[:], slot)
+			b = append(b, v[:]...)
+		}
+	}
+	return b
+}
+
+func (s *Store) decodeFreeSlots(b []byte) error {
+	for _, sh := range s.shards {
+		if len(b) < 4 {
+			return errors.New("blobstore: invalid free slots")
+		}
+		n := int(binary.BigEndian.Uint32(b[:4]))
+		b = b[4:]
+		if len(b) < 4*n {
+			return errors.New("blobstore: invalid free slots")
+		}
+		sh.free = make([]uint32, 0, n)
+		for i := 0; i < n; i++ {
+			slot := binary.BigEndian.Uint32(b[4*i:])
+			if slot < sh.slots {
+				sh.free = append(sh.free, slot)
+			}
+		}
+		b = b[4*n:]
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package blobstore_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/blobstore"
+)
+
+func TestStore(t *testing.T) {
+	s, err := blobstore.New("", blobstore.Options{Shards: 2, MaxDataSize: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	data := [][]byte{
+		[]byte("first"),
+		[]byte("second"),
+		[]byte("third blob data!"),
+	}
+	locs := make([]blobstore.Location, len(data))
+	for i, d := range data {
+		locs[i], err = s.Write(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, loc := range locs {
+		testRead(t, s, loc, data[i])
+	}
+
+	if _, err := s.Write(make([]byte, 17)); !errors.Is(err, blobstore.ErrTooLarge) {
+		t.Errorf("got error %v, want %v", err, blobstore.ErrTooLarge)
+	}
+
+	t.Run("reuse", func(t *testing.T) {
+		s.Release(locs[0])
+		// a slot holds the generation and the data
+		if got, want := s.FreeSize(), int64(8+16); got != want {
+			t.Errorf("got free size %v, want %v", got, want)
+		}
+		// the second write is to the shard of the released blob
+		if _, err := s.Write([]byte("other shard")); err != nil {
+			t.Fatal(err)
+		}
+		loc, err := s.Write([]byte("reused"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loc.Shard != locs[0].Shard || loc.Slot != locs[0].Slot {
+			t.Errorf("got location %v, want %v", loc, locs[0])
+		}
+		testRead(t, s, loc, []byte("reused"))
+		if got := s.FreeSize(); got != 0 {
+			t.Errorf("got free size %v, want 0", got)
+		}
+
+		if _, err := s.Read(locs[0]); !errors.Is(err, blobstore.ErrNotFound) {
+			t.Errorf("got error %v, want %v", err, blobstore.ErrNotFound)
+		}
+	})
+}
+
+func TestLocation(t *testing.T) {
+	loc := blobstore.Location{
+		Shard:      3,
+		Slot:       1234,
+		Generation: 1 << 40,
+		Length:     4104,
+	}
+	got, err := blobstore.ParseLocation(loc.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != loc {
+		t.Errorf("got location %+v, want %+v", got, loc)
+	}
+	if _, err := blobstore.ParseLocation([]byte{1, 2}); !errors.Is(err, blobstore.ErrInvalidLocation) {
+		t.Errorf("got error %v, want %v", err, blobstore.ErrInvalidLocation)
+	}
+}
+
+func TestStore_persistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	o := blobstore.Options{Shards: 1, MaxDataSize: 8}
+
+	s, err := blobstore.New(dir, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var locs []blobstore.Location
+	for _, d := range []string{"a", "b", "c"} {
+		loc, err := s.Write([]byte(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		locs = append(locs, loc)
+	}
+	s.Release(locs[1])
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = blobstore.New(dir, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Recovering() {
+		t.Fatal("store is recovering after close")
+	}
+	testRead(t, s, locs[0], []byte("a"))
+	testRead(t, s, locs[2], []byte("c"))
+
+	// the free slot is persisted
+	loc, err := s.Write([]byte("d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Slot != locs[1].Slot {
+		t.Errorf("got slot %v, want %v", loc.Slot, locs[1].Slot)
+	}
+	// the reused slot is written with a new generation
+	if loc.Generation <= locs[2].Generation {
+		t.Errorf("got generation %v, want more than %v", loc.Generation, locs[2].Generation)
+	}
+	size, err := s.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the file is not extended for the reused slot
+	if max := int64(3 * (8 + 8)); size > max {
+		t.Errorf("got size %v, want at most %v", size, max)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_recovery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	o := blobstore.Options{Shards: 1, MaxDataSize: 8}
+
+	s, err := blobstore.New(dir, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var locs []blobstore.Location
+	for _, d := range []string{"a", "b", "c"} {
+		loc, err := s.Write([]byte(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		locs = append(locs, loc)
+	}
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	// the store is not closed, so free slots are not persisted
+
+	r, err := blobstore.New(dir, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if !r.Recovering() {
+		t.Fatal("store is not recovering")
+	}
+
+	// new slots are used during recovery
+	loc, err := r.Write([]byte("d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Slot != 3 {
+		t.Errorf("got slot %v, want 3", loc.Slot)
+	}
+
+	r.MarkUsed(locs[0])
+	r.MarkUsed(locs[2])
+	r.Recovered()
+	if r.Recovering() {
+		t.Fatal("store is recovering after recovered")
+	}
+
+	testRead(t, r, locs[0], []byte("a"))
+	testRead(t, r, loc, []byte("d"))
+
+	// only the slot that is not marked is free
+	loc, err = r.Write([]byte("e"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Slot != locs[1].Slot {
+		t.Errorf("got slot %v, want %v", loc.Slot, locs[1].Slot)
+	}
+	// generations are reserved, so they increase
+	// even if the store is not closed
+	if loc.Generation <= locs[2].Generation {
+		t.Errorf("got generation %v, want more than %v", loc.Generation, locs[2].Generation)
+	}
+	loc, err = r.Write([]byte("f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Slot != 4 {
+		t.Errorf("got slot %v, want 4", loc.Slot)
+	}
+}
+
+// TestStore_lastGeneration validates that generations of stores without the
+// persisted generation limit continue after the last written generation.
+func TestStore_lastGeneration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	o := blobstore.Options{Shards: 2, MaxDataSize: 8}
+
+	s, err := blobstore.New(dir, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last blobstore.Location
+	for _, d := range []string{"a", "b", "c"} {
+		last, err = s.Write([]byte(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "generation")); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = blobstore.New(dir, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	loc, err := s.Write([]byte("d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Generation <= last.Generation {
+		t.Errorf("got generation %v, want more than %v", loc.Generation, last.Generation)
+	}
+	testRead(t, s, last, []byte("c"))
+}
+
+func testRead(t *testing.T, s *blobstore.Store, loc blobstore.Location, want []byte) {
+	t.Helper()
+
+	got, err := s.Read(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got data %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package blobstore
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// osFile is a shard file on disk.
+type osFile struct {
+	*os.File
+}
+
+func openOSFile(path string) (*osFile, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &osFile{File: f}, nil
+}
+
+func (f *osFile) Size() (int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return fi.Size(), nil
+}
+
+// memFile is a shard file in memory.
+type memFile struct {
+	b  []byte
+	mu sync.RWMutex
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (n int, err error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if off >= int64(len(f.b)) {
+		return 0, io.EOF
+	}
+	n = copy(p, f.b[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memFile) WriteAt(p []byte, off int64) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if end := off + int64(len(p)); end > int64(len(f.b)) {
+		if end > int64(cap(f.b)) {
+			b := make([]byte, end, 2*end)
+			copy(b, f.b)
+			f.b = b
+		} else {
+			f.b = f.b[:end]
+		}
+	}
+	return copy(f.b[off:], p), nil
+}
+
+func (f *memFile) Size() (int64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return int64(len(f.b)), nil
+}
+
+func (f *memFile) Sync() error {
+	return nil
+}
+
+func (f *memFile) Close() error {
+	return nil
+}
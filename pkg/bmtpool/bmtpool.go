@@ -0,0 +1,52 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bmtpool provides easy access to binary
+// merkle tree hashers managed in as a resource pool.
+package bmtpool
+
+import (
+	"hash"
+	"runtime"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	bmtlegacy "github.com/ethersphere/bmt/legacy"
+	"golang.org/x/crypto/sha3"
+)
+
+// Capacity is the maximum number of bmt trees that can be used
+// concurrently by all hashers from the pool.
+var Capacity = runtime.GOMAXPROCS(0)
+
+var (
+	instance *bmtlegacy.TreePool
+	hashers  sync.Pool
+)
+
+func init() {
+	instance = bmtlegacy.NewTreePool(hashFunc, swarm.Branches, Capacity)
+	hashers = sync.Pool{
+		New: func() interface{} {
+			return bmtlegacy.New(instance)
+		},
+	}
+}
+
+// hashFunc is a hasher factory used by the bmt hasher
+func hashFunc() hash.Hash {
+	return sha3.NewLegacyKeccak256()
+}
+
+// Get returns a bmt Hasher instance.
+// Instances must be returned using the Put function.
+func Get() *bmtlegacy.Hasher {
+	return hashers.Get().(*bmtlegacy.Hasher)
+}
+
+// Put returns a bmt Hasher instance back to the pool.
+func Put(h *bmtlegacy.Hasher) {
+	h.Reset()
+	hashers.Put(h)
+}
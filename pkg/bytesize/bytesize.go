@@ -0,0 +1,69 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bytesize parses data sizes with human readable units.
+package bytesize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSize is returned by Parse if the size is malformed.
+var ErrInvalidSize = errors.New("invalid size")
+
+// units maps lower case unit names to the number of bytes. Decimal units
+// are multiples of 1000 and binary units are multiples of 1024 bytes.
+var units = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// Parse returns the number of bytes of a size with an optional unit, like
+// "4096", "512MiB" or "1.5 GB". Unit names are case insensitive.
+func Parse(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	multiplier, ok := units[unit]
+	if !ok {
+		return 0, fmt.Errorf("%w %q: unknown unit %q", ErrInvalidSize, s, unit)
+	}
+	if !strings.Contains(number, ".") {
+		n, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w %q", ErrInvalidSize, s)
+		}
+		if n > math.MaxUint64/uint64(multiplier) {
+			return 0, fmt.Errorf("%w %q: too large", ErrInvalidSize, s)
+		}
+		return n * uint64(multiplier), nil
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrInvalidSize, s)
+	}
+	f *= multiplier
+	if f >= math.MaxUint64 {
+		return 0, fmt.Errorf("%w %q: too large", ErrInvalidSize, s)
+	}
+	return uint64(f), nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytesize_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/bytesize"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		size string
+		want uint64
+	}{
+		{size: "0", want: 0},
+		{size: "4096", want: 4096},
+		{size: "10B", want: 10},
+		{size: "2kB", want: 2000},
+		{size: "20GB", want: 20000000000},
+		{size: "20gb", want: 20000000000},
+		{size: "1.5 GB", want: 1500000000},
+		{size: "3TB", want: 3000000000000},
+		{size: "1KiB", want: 1024},
+		{size: "512MiB", want: 512 << 20},
+		{size: " 2 GiB ", want: 2 << 30},
+		{size: "0.5TiB", want: 1 << 39},
+	} {
+		got, err := bytesize.Parse(tc.size)
+		if err != nil {
+			t.Errorf("parse %q: %v", tc.size, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parse %q: got %v, want %v", tc.size, got, tc.want)
+		}
+	}
+
+	for _, size := range []string{
+		"",
+		"GB",
+		"-1GB",
+		"1..5GB",
+		"12 parsecs",
+		"20000000TiB",
+		"18446744073709551616",
+	} {
+		if _, err := bytesize.Parse(size); !errors.Is(err, bytesize.ErrInvalidSize) {
+			t.Errorf("parse %q: got error %v, want %v", size, err, bytesize.ErrInvalidSize)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type referenceResponse struct {
+	Reference swarm.Address `json:"reference"`
+}
+
+// UploadBytes uploads the data from the reader and returns its reference.
+// The size is the exact length of the data, as the node requires it to
+// split the data while it is streamed.
+func (c *Client) UploadBytes(ctx context.Context, data io.Reader, size int64) (swarm.Address, error) {
+	resp, err := c.api(ctx, request{
+		method: http.MethodPost,
+		path:   "/bytes",
+		header: http.Header{"Content-Type": {"application/octet-stream"}},
+		body:   data,
+		size:   size,
+	})
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	var r referenceResponse
+	if err := decodeJSON(resp, &r); err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return r.Reference, nil
+}
+
+// DownloadBytes returns the reader of the data with the reference. The
+// reader streams the data from the node and it must be closed.
+func (c *Client) DownloadBytes(ctx context.Context, reference swarm.Address) (io.ReadCloser, error) {
+	resp, err := c.api(ctx, request{
+		method: http.MethodGet,
+		path:   "/bytes/" + reference.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// FileInfo contains the metadata of a downloaded file.
+type FileInfo struct {
+	Name        string
+	ContentType string
+	// Size is the size of the file in bytes, or -1 if it is not known.
+	Size int64
+}
+
+// UploadFile uploads the file with the name and the content type from the
+// reader and returns the reference of its entry. The size of the file is
+// sent as the content length if it is positive.
+func (c *Client) UploadFile(ctx context.Context, name, contentType string, data io.Reader, size int64) (swarm.Address, error) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	query := make(url.Values)
+	if name != "" {
+		query.Set("name", name)
+	}
+	resp, err := c.api(ctx, request{
+		method: http.MethodPost,
+		path:   "/files",
+		query:  query,
+		header: http.Header{"Content-Type": {contentType}},
+		body:   data,
+		size:   size,
+	})
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	var r referenceResponse
+	if err := decodeJSON(resp, &r); err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return r.Reference, nil
+}
+
+// DownloadFile returns the reader of the file with the entry reference and
+// its metadata. The reader streams the file from the node and it must be
+// closed.
+func (c *Client) DownloadFile(ctx context.Context, reference swarm.Address) (io.ReadCloser, FileInfo, error) {
+	resp, err := c.api(ctx, request{
+		method: http.MethodGet,
+		path:   "/files/" + reference.String(),
+	})
+	if err != nil {
+		return nil, FileInfo{}, err
+	}
+	info := FileInfo{
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+	}
+	// the content length is not known if the response is compressed
+	if l, err := strconv.ParseInt(resp.Header.Get("Decompressed-Content-Length"), 10, 64); err == nil {
+		info.Size = l
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		info.Name = params["filename"]
+	}
+	return resp.Body, info, nil
+}
+
+// ChunkUploadOptions are the optional parameters of a chunk upload.
+type ChunkUploadOptions struct {
+	// Tag is the uid of the tag that the chunk is counted in.
+	// A new tag is created if it is zero.
+	Tag uint32
+	// Pin pins the uploaded chunk.
+	Pin bool
+}
+
+// UploadChunk uploads the chunk and returns the uid of the tag
+// that it is counted in.
+func (c *Client) UploadChunk(ctx context.Context, ch swarm.Chunk, o ChunkUploadOptions) (uint32, error) {
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	if o.Tag != 0 {
+		header.Set(api.TagHeaderUid, strconv.FormatUint(uint64(o.Tag), 10))
+	}
+	if o.Pin {
+		header.Set(api.PinHeaderName, "true")
+	}
+	resp, err := c.api(ctx, request{
+		method: http.MethodPost,
+		path:   "/chunks/" + ch.Address().String(),
+		header: header,
+		body:   bytes.NewReader(ch.Data()),
+		size:   int64(len(ch.Data())),
+	})
+	if err != nil {
+		return 0, err
+	}
+	drain(resp.Body)
+
+	uid, err := strconv.ParseUint(resp.Header.Get(api.TagHeaderUid), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parse tag uid: %w", err)
+	}
+	return uint32(uid), nil
+}
+
+// DownloadChunk returns the chunk with the address.
+func (c *Client) DownloadChunk(ctx context.Context, address swarm.Address) (swarm.Chunk, error) {
+	resp, err := c.api(ctx, request{
+		method: http.MethodGet,
+		path:   "/chunks/" + address.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp.Body)
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read chunk: %w", err)
+	}
+	return swarm.NewChunk(address, data), nil
+}
+
+type authRequest struct {
+	Scopes []auth.Scope `json:"scopes"`
+	Expiry int64        `json:"expiry,omitempty"`
+}
+
+type authResponse struct {
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires"`
+}
+
+// IssueToken requests a bearer token with the scopes from a node in the
+// restricted mode, in exchange for the node password. The token expires
+// after the expiry duration, or the node default if it is zero.
+func (c *Client) IssueToken(ctx context.Context, password string, scopes []auth.Scope, expiry time.Duration) (string, time.Time, error) {
+	body, err := json.Marshal(authRequest{
+		Scopes: scopes,
+		Expiry: int64(expiry / time.Second),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	resp, err := c.api(ctx, request{
+		method: http.MethodPost,
+		path:   "/auth",
+		header: basicAuthHeader(password),
+		body:   bytes.NewReader(body),
+		size:   int64(len(body)),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var r authResponse
+	if err := decodeJSON(resp, &r); err != nil {
+		return "", time.Time{}, err
+	}
+	return r.Key, r.Expires, nil
+}
+
+type revokeRequest struct {
+	Key string `json:"key"`
+}
+
+// RevokeToken revokes the bearer token, in exchange for the node password.
+func (c *Client) RevokeToken(ctx context.Context, password, token string) error {
+	body, err := json.Marshal(revokeRequest{
+		Key: token,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := c.api(ctx, request{
+		method: http.MethodPost,
+		path:   "/auth/revoke",
+		header: basicAuthHeader(password),
+		body:   bytes.NewReader(body),
+		size:   int64(len(body)),
+	})
+	if err != nil {
+		return err
+	}
+	drain(resp.Body)
+	return nil
+}
+
+// basicAuthHeader returns the header of JSON requests that are
+// authenticated with the node password.
+func basicAuthHeader(password string) http.Header {
+	return http.Header{
+		"Authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte(":"+password))},
+		"Content-Type":  {"application/json"},
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/client"
+	"github.com/ethersphere/bee/pkg/logging"
+	mockstate "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func newAPIClient(t *testing.T, storer storage.Storer, authenticator *auth.Authenticator, o client.Options) *client.Client {
+	t.Helper()
+
+	return newTestClient(t, newAPIServer(storer, authenticator), nil, o)
+}
+
+func newAPIServer(storer storage.Storer, authenticator *auth.Authenticator) http.Handler {
+	return api.New(api.Options{
+		Tags:          tags.NewTags(),
+		Storer:        storer,
+		Logger:        logging.New(ioutil.Discard, 0),
+		Authenticator: authenticator,
+	})
+}
+
+func TestBytes(t *testing.T) {
+	ctx := context.Background()
+	c := newAPIClient(t, mock.NewStorer(), nil, client.Options{})
+	data := bytes.Repeat([]byte("swarm"), 2000)
+
+	ref, err := c.UploadBytes(ctx, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := c.DownloadBytes(ctx, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("downloaded data does not match the uploaded data")
+	}
+
+	_, err = c.DownloadBytes(ctx, swarm.MustParseHexAddress("aabbcc"))
+	if !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, client.ErrNotFound)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	ctx := context.Background()
+	c := newAPIClient(t, mock.NewStorer(), nil, client.Options{})
+	data := []byte("<h1>Swarm</h1>")
+
+	for _, tc := range []struct {
+		name string
+		size int64
+	}{
+		{name: "known size", size: int64(len(data))},
+		{name: "unknown size", size: -1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := c.UploadFile(ctx, "index.html", "text/html; charset=utf-8", bytes.NewReader(data), tc.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, info, err := c.DownloadFile(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			got, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("got file %q, want %q", got, data)
+			}
+			want := client.FileInfo{
+				Name:        "index.html",
+				ContentType: "text/html; charset=utf-8",
+				Size:        int64(len(data)),
+			}
+			if info != want {
+				t.Errorf("got file info %+v, want %+v", info, want)
+			}
+		})
+	}
+}
+
+func TestChunks(t *testing.T) {
+	ctx := context.Background()
+	storer := mock.NewStorer()
+	c := newAPIClient(t, storer, nil, client.Options{})
+	ch := swarm.NewChunk(swarm.MustParseHexAddress("aabbcc"), []byte("chunk data"))
+
+	uid, err := c.UploadChunk(ctx, ch, client.ChunkUploadOptions{Pin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid == 0 {
+		t.Error("got zero tag uid")
+	}
+	if mode := storer.GetModeSet(ch.Address()); mode != storage.ModeSetPin {
+		t.Errorf("got mode set %v, want %v", mode, storage.ModeSetPin)
+	}
+
+	// the chunk is counted in the same tag
+	ch2 := swarm.NewChunk(swarm.MustParseHexAddress("ddeeff"), []byte("other data"))
+	uid2, err := c.UploadChunk(ctx, ch2, client.ChunkUploadOptions{Tag: uid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid2 != uid {
+		t.Errorf("got tag uid %v, want %v", uid2, uid)
+	}
+
+	got, err := c.DownloadChunk(ctx, ch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(ch) {
+		t.Errorf("got chunk %v, want %v", got, ch)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	ctx := context.Background()
+	ts := httptest.NewServer(newAPIServer(mock.NewStorer(), auth.New(mockstate.NewStateStore(), "secret")))
+	defer ts.Close()
+
+	c, err := client.New(client.Options{APIURL: ts.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := c.IssueToken(ctx, "wrong", []auth.Scope{auth.ScopeRead}, 0); !errors.Is(err, client.ErrUnauthorized) {
+		t.Fatalf("got error %v, want %v", err, client.ErrUnauthorized)
+	}
+
+	key, expires, err := c.IssueToken(ctx, "secret", []auth.Scope{auth.ScopeRead}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(expires); d <= 0 || d > time.Hour {
+		t.Errorf("got expiry in %v, want within an hour", d)
+	}
+
+	tc, err := client.New(client.Options{
+		APIURL: ts.URL,
+		Token:  key,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.DownloadBytes(ctx, swarm.MustParseHexAddress("aabbcc")); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, client.ErrNotFound)
+	}
+	if _, err := tc.UploadBytes(ctx, bytes.NewReader([]byte("data")), 4); !errors.Is(err, client.ErrForbidden) {
+		t.Errorf("got error %v, want %v", err, client.ErrForbidden)
+	}
+
+	if err := c.RevokeToken(ctx, "secret", key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.DownloadBytes(ctx, swarm.MustParseHexAddress("aabbcc")); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("got error %v, want %v", err, client.ErrUnauthorized)
+	}
+}
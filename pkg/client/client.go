@@ -0,0 +1,284 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package client provides a Go client for the bee HTTP API and debug HTTP
+// API.
+//
+// All methods accept a context which cancels the request, including reading
+// of the streamed response bodies. Requests are retried on network errors and
+// on responses that indicate a temporary failure, if their bodies can be
+// sent again.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// DefaultRetryDelay is the delay before the first retry, if no other is
+// configured. Every subsequent retry waits for a multiple of it.
+const DefaultRetryDelay = 500 * time.Millisecond
+
+var (
+	// ErrNotFound is wrapped by the errors of responses
+	// with the Not Found status code.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is wrapped by the errors of responses
+	// with the Unauthorized status code.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is wrapped by the errors of responses
+	// with the Forbidden status code.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNoDebugAPI is returned by the debug API methods
+	// if the debug API URL is not configured.
+	ErrNoDebugAPI = errors.New("debug api url not configured")
+)
+
+// Error is returned for responses with unexpected status codes.
+type Error struct {
+	// Code is the HTTP status code of the response.
+	Code int
+	// Message is the error message from the response body.
+	Message string
+	// Reason is the machine readable error identifier
+	// from the response body, for example not_found.
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%d %s", e.Code, http.StatusText(e.Code))
+	}
+	return fmt.Sprintf("%d %s: %s", e.Code, http.StatusText(e.Code), e.Message)
+}
+
+// Unwrap returns the sentinel error for the status code, if there is one.
+func (e *Error) Unwrap() error {
+	switch e.Code {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	}
+	return nil
+}
+
+// Client is a client for the API and debug API of a bee node.
+type Client struct {
+	apiURL      *url.URL
+	debugAPIURL *url.URL
+	httpClient  *http.Client
+	token       string
+	maxRetries  int
+	retryDelay  time.Duration
+}
+
+// Options are the configuration of the Client.
+type Options struct {
+	// APIURL is the base URL of the API, for example http://localhost:8080.
+	APIURL string
+	// DebugAPIURL is the base URL of the debug API, for example
+	// http://localhost:6060. Debug API methods return ErrNoDebugAPI
+	// if it is not set.
+	DebugAPIURL string
+	// HTTPClient is used to send requests. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+	// Token is the bearer token that is sent with all requests
+	// to nodes that run in the restricted mode.
+	Token string
+	// MaxRetries is the number of times a failed request is retried.
+	// Zero disables retries.
+	MaxRetries int
+	// RetryDelay is the delay before the first retry,
+	// DefaultRetryDelay if it is not set.
+	RetryDelay time.Duration
+}
+
+// New constructs a new Client.
+func New(o Options) (*Client, error) {
+	c := &Client{
+		httpClient: o.HTTPClient,
+		token:      o.Token,
+		maxRetries: o.MaxRetries,
+		retryDelay: o.RetryDelay,
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.retryDelay <= 0 {
+		c.retryDelay = DefaultRetryDelay
+	}
+
+	var err error
+	if c.apiURL, err = parseBaseURL(o.APIURL); err != nil {
+		return nil, fmt.Errorf("api url: %w", err)
+	}
+	if o.DebugAPIURL != "" {
+		if c.debugAPIURL, err = parseBaseURL(o.DebugAPIURL); err != nil {
+			return nil, fmt.Errorf("debug api url: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func parseBaseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", s)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+// request describes a request to one of the APIs.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	// body is sent as the request body, it can be sent again when
+	// retrying only if it implements io.Seeker
+	body io.Reader
+	// size is the content length, if known
+	size int64
+}
+
+// api sends the request to the API.
+func (c *Client) api(ctx context.Context, r request) (*http.Response, error) {
+	return c.do(ctx, c.apiURL, r)
+}
+
+// debugAPI sends the request to the debug API.
+func (c *Client) debugAPI(ctx context.Context, r request) (*http.Response, error) {
+	if c.debugAPIURL == nil {
+		return nil, ErrNoDebugAPI
+	}
+	return c.do(ctx, c.debugAPIURL, r)
+}
+
+// do sends the request and returns the response if it has a successful
+// status code, retrying temporary failures. Otherwise, the response body is
+// closed and an *Error is returned. The response body must be closed by the
+// caller.
+func (c *Client) do(ctx context.Context, base *url.URL, r request) (*http.Response, error) {
+	u := *base
+	u.Path += r.path
+	u.RawQuery = r.query.Encode()
+
+	seeker, rewindable := r.body.(io.Seeker)
+	if r.body == nil {
+		rewindable = true
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && seeker != nil {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("rewind request body: %w", err)
+			}
+		}
+
+		resp, err := c.send(ctx, u.String(), r)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+		if err == nil {
+			err = responseError(resp)
+		}
+
+		if ctx.Err() != nil || !rewindable || attempt >= c.maxRetries || !temporary(err) {
+			return nil, err
+		}
+
+		select {
+		case <-time.After(c.retryDelay * time.Duration(attempt+1)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, url string, r request) (*http.Response, error) {
+	body := r.body
+	if body != nil {
+		// the body is closed by the transport, but it
+		// is still needed if the request is retried
+		body = ioutil.NopCloser(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if r.size > 0 {
+		req.ContentLength = r.size
+	}
+	if c.token != "" && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// responseError closes the response body and returns
+// the error with the message from the body.
+func responseError(resp *http.Response) error {
+	defer drain(resp.Body)
+
+	e := &Error{Code: resp.StatusCode}
+	var sr jsonhttp.StatusResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&sr); err == nil {
+		e.Message = sr.Message
+		e.Reason = sr.Reason
+	}
+	return e
+}
+
+// temporary returns true for errors that may not occur if the request is
+// sent again: network errors and responses that indicate that the node is
+// overloaded or unavailable.
+func temporary(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return true
+	}
+	switch e.Code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// decodeJSON decodes the response body into v and closes the body.
+func decodeJSON(resp *http.Response, v interface{}) error {
+	defer drain(resp.Body)
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// drain reads the remaining body so that the connection can be reused,
+// and closes it.
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(body, 1<<20))
+	_ = body.Close()
+}
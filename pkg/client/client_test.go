@@ -0,0 +1,240 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/client"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// newTestClient returns a client for the servers with the handlers.
+// The debug API is not configured if its handler is nil.
+func newTestClient(t *testing.T, api, debugAPI http.Handler, o client.Options) *client.Client {
+	t.Helper()
+
+	ts := httptest.NewServer(api)
+	t.Cleanup(ts.Close)
+	o.APIURL = ts.URL
+
+	if debugAPI != nil {
+		dts := httptest.NewServer(debugAPI)
+		t.Cleanup(dts.Close)
+		o.DebugAPIURL = dts.URL
+	}
+	if o.RetryDelay == 0 {
+		o.RetryDelay = time.Millisecond
+	}
+
+	c, err := client.New(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		o    client.Options
+		ok   bool
+	}{
+		{name: "api", o: client.Options{APIURL: "http://localhost:8080"}, ok: true},
+		{name: "debug api", o: client.Options{APIURL: "http://localhost:8080", DebugAPIURL: "http://localhost:6060/"}, ok: true},
+		{name: "no api", o: client.Options{}},
+		{name: "invalid api", o: client.Options{APIURL: "localhost:8080"}},
+		{name: "invalid debug api", o: client.Options{APIURL: "http://localhost:8080", DebugAPIURL: "localhost"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.New(tc.o)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jsonhttp.NotFound(w, "name not found")
+	}), nil, client.Options{MaxRetries: 3})
+
+	_, err := c.DownloadBytes(context.Background(), swarm.MustParseHexAddress("aabbcc"))
+	if !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, client.ErrNotFound)
+	}
+	var e *client.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("got error %T, want %T", err, e)
+	}
+	want := &client.Error{
+		Code:    http.StatusNotFound,
+		Message: "name not found",
+		Reason:  "not_found",
+	}
+	if *e != *want {
+		t.Errorf("got error %+v, want %+v", e, want)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	const failures = 2
+	data := []byte("retried data")
+
+	newServer := func(t *testing.T, maxRetries int) (*client.Client, *int) {
+		var attempts int
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			if !bytes.Equal(body, data) {
+				t.Errorf("attempt %v: got body %q, want %q", attempts, body, data)
+			}
+			if attempts <= failures {
+				jsonhttp.ServiceUnavailable(w, nil)
+				return
+			}
+			jsonhttp.OK(w, map[string]string{"reference": "aabbcc"})
+		}), nil, client.Options{MaxRetries: maxRetries})
+		return c, &attempts
+	}
+
+	t.Run("retried", func(t *testing.T) {
+		c, attempts := newServer(t, failures)
+
+		ref, err := c.UploadBytes(context.Background(), bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ref.Equal(swarm.MustParseHexAddress("aabbcc")) {
+			t.Errorf("got reference %s, want %s", ref, "aabbcc")
+		}
+		if *attempts != failures+1 {
+			t.Errorf("got %v attempts, want %v", *attempts, failures+1)
+		}
+	})
+
+	t.Run("too many failures", func(t *testing.T) {
+		c, attempts := newServer(t, failures-1)
+
+		_, err := c.UploadBytes(context.Background(), bytes.NewReader(data), int64(len(data)))
+		var e *client.Error
+		if !errors.As(err, &e) || e.Code != http.StatusServiceUnavailable {
+			t.Fatalf("got error %v, want %v status", err, http.StatusServiceUnavailable)
+		}
+		if *attempts != failures {
+			t.Errorf("got %v attempts, want %v", *attempts, failures)
+		}
+	})
+
+	t.Run("not rewindable body", func(t *testing.T) {
+		c, attempts := newServer(t, failures)
+
+		_, err := c.UploadBytes(context.Background(), io.MultiReader(bytes.NewReader(data)), int64(len(data)))
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if *attempts != 1 {
+			t.Errorf("got %v attempts, want %v", *attempts, 1)
+		}
+	})
+
+	t.Run("permanent failure", func(t *testing.T) {
+		var attempts int
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			jsonhttp.BadRequest(w, nil)
+		}), nil, client.Options{MaxRetries: failures})
+
+		if _, err := c.UploadBytes(context.Background(), bytes.NewReader(data), int64(len(data))); err == nil {
+			t.Fatal("expected error")
+		}
+		if attempts != 1 {
+			t.Errorf("got %v attempts, want %v", attempts, 1)
+		}
+	})
+}
+
+func TestContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}), nil, client.Options{MaxRetries: 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err := c.DownloadBytes(ctx, swarm.MustParseHexAddress("aabbcc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// the beginning of the body is streamed before the response is complete
+	b := make([]byte, len("partial"))
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "partial" {
+		t.Errorf("got %q, want %q", b, "partial")
+	}
+
+	cancel()
+	if _, err := ioutil.ReadAll(r); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := c.DownloadBytes(ctx, swarm.MustParseHexAddress("aabbcc")); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestToken(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret-token" {
+			t.Errorf("got authorization header %q", got)
+		}
+		jsonhttp.OK(w, nil)
+	}), nil, client.Options{Token: "secret-token"})
+
+	r, err := c.DownloadBytes(context.Background(), swarm.MustParseHexAddress("aabbcc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+}
+
+func TestNoDebugAPI(t *testing.T) {
+	c := newTestClient(t, http.NotFoundHandler(), nil, client.Options{})
+
+	if err := c.Health(context.Background()); !errors.Is(err, client.ErrNoDebugAPI) {
+		t.Errorf("got error %v, want %v", err, client.ErrNoDebugAPI)
+	}
+	if _, err := c.Peers(context.Background()); !errors.Is(err, client.ErrNoDebugAPI) {
+		t.Errorf("got error %v, want %v", err, client.ErrNoDebugAPI)
+	}
+}
@@ -0,0 +1,745 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Health returns nil if the node responds to the health check.
+func (c *Client) Health(ctx context.Context) error {
+	return c.status(ctx, "/health")
+}
+
+// Readiness returns nil if the node is ready to serve requests. If it is
+// not, the returned *Error has the Service Unavailable status code.
+func (c *Client) Readiness(ctx context.Context) error {
+	return c.status(ctx, "/readiness")
+}
+
+func (c *Client) status(ctx context.Context, path string) error {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   path,
+	})
+	if err != nil {
+		return err
+	}
+	drain(resp.Body)
+	return nil
+}
+
+// ComponentStatus is the readiness of a node component.
+type ComponentStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Error describes why the component is not ready.
+	Error string `json:"error"`
+}
+
+// NodeStatus is the state of the node and its components.
+type NodeStatus struct {
+	Addresses
+	Version   string
+	Uptime    time.Duration
+	NetworkID uint64
+	// DBCapacity and DBUsage are the numbers of bytes of the local store on the disk.
+	DBCapacity uint64
+	DBUsage    uint64
+	// Ready is true if all components are ready.
+	Ready      bool
+	Components []ComponentStatus
+}
+
+// Node returns the state of the node and its components.
+func (c *Client) Node(ctx context.Context) (NodeStatus, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/node",
+	})
+	if err != nil {
+		return NodeStatus{}, err
+	}
+	var r struct {
+		Overlay    swarm.Address     `json:"overlay"`
+		Underlay   []string          `json:"underlay"`
+		Version    string            `json:"version"`
+		Uptime     int64             `json:"uptime"`
+		NetworkID  uint64            `json:"networkID"`
+		DBCapacity uint64            `json:"dbCapacity"`
+		DBUsage    uint64            `json:"dbUsage"`
+		Status     string            `json:"status"`
+		Components []ComponentStatus `json:"components"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return NodeStatus{}, err
+	}
+	underlay, err := parseUnderlay(r.Underlay)
+	if err != nil {
+		return NodeStatus{}, err
+	}
+	return NodeStatus{
+		Addresses: Addresses{
+			Overlay:  r.Overlay,
+			Underlay: underlay,
+		},
+		Version:    r.Version,
+		Uptime:     time.Duration(r.Uptime) * time.Second,
+		NetworkID:  r.NetworkID,
+		DBCapacity: r.DBCapacity,
+		DBUsage:    r.DBUsage,
+		Ready:      r.Status == "ok",
+		Components: r.Components,
+	}, nil
+}
+
+// Addresses are the overlay and underlay addresses of the node.
+type Addresses struct {
+	Overlay  swarm.Address
+	Underlay []multiaddr.Multiaddr
+}
+
+// Addresses returns the overlay and underlay addresses of the node.
+func (c *Client) Addresses(ctx context.Context) (Addresses, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/addresses",
+	})
+	if err != nil {
+		return Addresses{}, err
+	}
+	var r struct {
+		Overlay  swarm.Address `json:"overlay"`
+		Underlay []string      `json:"underlay"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return Addresses{}, err
+	}
+	underlay, err := parseUnderlay(r.Underlay)
+	if err != nil {
+		return Addresses{}, err
+	}
+	return Addresses{
+		Overlay:  r.Overlay,
+		Underlay: underlay,
+	}, nil
+}
+
+func parseUnderlay(addrs []string) ([]multiaddr.Multiaddr, error) {
+	underlay := make([]multiaddr.Multiaddr, 0, len(addrs))
+	for _, a := range addrs {
+		addr, err := multiaddr.NewMultiaddr(a)
+		if err != nil {
+			return nil, fmt.Errorf("parse underlay %q: %w", a, err)
+		}
+		underlay = append(underlay, addr)
+	}
+	return underlay, nil
+}
+
+// Connect connects the node to the peer with the underlay address
+// and returns the overlay address of the peer.
+func (c *Client) Connect(ctx context.Context, underlay multiaddr.Multiaddr) (swarm.Address, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodPost,
+		path:   "/connect" + underlay.String(),
+	})
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	var r struct {
+		Address string `json:"address"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return swarm.ParseHexAddress(r.Address)
+}
+
+// Peers returns the overlay addresses of the connected peers.
+func (c *Client) Peers(ctx context.Context) ([]swarm.Address, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/peers",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		Peers []struct {
+			Address swarm.Address `json:"address"`
+		} `json:"peers"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return nil, err
+	}
+	peers := make([]swarm.Address, 0, len(r.Peers))
+	for _, p := range r.Peers {
+		peers = append(peers, p.Address)
+	}
+	return peers, nil
+}
+
+// PeerInfo is the state of the connection with a peer.
+type PeerInfo struct {
+	Address     swarm.Address
+	Underlays   []multiaddr.Multiaddr
+	Direction   string
+	ConnectedAt time.Time
+	Light       bool
+	// LastRTT is the round trip time of the last ping, zero if the peer
+	// has not been pinged.
+	LastRTT time.Duration
+	// Bin is the proximity order of the peer to the node.
+	Bin       uint8
+	Protocols map[string]ProtocolStats
+	// BreakerClosedUntil is the time until which the node does not make
+	// new connections, zero if it makes them.
+	BreakerClosedUntil time.Time
+}
+
+// ProtocolStats is the traffic with a peer over streams of a protocol.
+type ProtocolStats struct {
+	BytesSent     uint64 `json:"bytesSent"`
+	BytesReceived uint64 `json:"bytesReceived"`
+}
+
+// Peer returns the state of the connection with the peer. If the peer is
+// not connected, the returned error wraps ErrNotFound.
+func (c *Client) Peer(ctx context.Context, peer swarm.Address) (PeerInfo, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/peers/" + peer.String(),
+	})
+	if err != nil {
+		return PeerInfo{}, err
+	}
+	var r struct {
+		Address            swarm.Address            `json:"address"`
+		Underlays          []string                 `json:"underlays"`
+		Direction          string                   `json:"direction"`
+		ConnectedAt        time.Time                `json:"connectedAt"`
+		Light              bool                     `json:"light"`
+		LastRTT            string                   `json:"lastRtt"`
+		Bin                uint8                    `json:"bin"`
+		Protocols          map[string]ProtocolStats `json:"protocols"`
+		BreakerClosedUntil time.Time                `json:"breakerClosedUntil"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return PeerInfo{}, err
+	}
+	underlays, err := parseUnderlay(r.Underlays)
+	if err != nil {
+		return PeerInfo{}, err
+	}
+	var rtt time.Duration
+	if r.LastRTT != "" {
+		rtt, err = time.ParseDuration(r.LastRTT)
+		if err != nil {
+			return PeerInfo{}, fmt.Errorf("parse rtt: %w", err)
+		}
+	}
+	return PeerInfo{
+		Address:            r.Address,
+		Underlays:          underlays,
+		Direction:          r.Direction,
+		ConnectedAt:        r.ConnectedAt,
+		Light:              r.Light,
+		LastRTT:            rtt,
+		Bin:                r.Bin,
+		Protocols:          r.Protocols,
+		BreakerClosedUntil: r.BreakerClosedUntil,
+	}, nil
+}
+
+// Disconnect disconnects the node from the peer with the overlay address.
+func (c *Client) Disconnect(ctx context.Context, peer swarm.Address) error {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodDelete,
+		path:   "/peers/" + peer.String(),
+	})
+	if err != nil {
+		return err
+	}
+	drain(resp.Body)
+	return nil
+}
+
+// Ping sends a ping to the connected peer and returns the round trip time.
+func (c *Client) Ping(ctx context.Context, peer swarm.Address) (time.Duration, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodPost,
+		path:   "/pingpong/" + peer.String(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	var r struct {
+		RTT string `json:"rtt"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return 0, err
+	}
+	rtt, err := time.ParseDuration(r.RTT)
+	if err != nil {
+		return 0, fmt.Errorf("parse rtt: %w", err)
+	}
+	return rtt, nil
+}
+
+// HasChunk returns true if the chunk is stored in the local store of the node.
+func (c *Client) HasChunk(ctx context.Context, address swarm.Address) (bool, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/chunks/" + address.String(),
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	drain(resp.Body)
+	return true, nil
+}
+
+// ChunkInfo is the state of a chunk in the local store indexes.
+type ChunkInfo struct {
+	Address swarm.Address `json:"address"`
+	Indexes struct {
+		Retrieval bool `json:"retrieval"`
+		Push      bool `json:"push"`
+		Pull      bool `json:"pull"`
+		GC        bool `json:"gc"`
+		GCExclude bool `json:"gcExclude"`
+		Pin       bool `json:"pin"`
+	} `json:"indexes"`
+	BinID uint64 `json:"binID"`
+	// StoreTimestamp and AccessTimestamp are zero if the chunk is
+	// not in the retrieval indexes.
+	StoreTimestamp  time.Time `json:"storeTimestamp"`
+	AccessTimestamp time.Time `json:"accessTimestamp"`
+	PinCounter      uint64    `json:"pinCounter"`
+	Tag             uint32    `json:"tag"`
+	// Proximity is the proximity order of the chunk to the node.
+	Proximity   uint8 `json:"proximity"`
+	WithinDepth bool  `json:"withinDepth"`
+}
+
+// ChunkInfo returns the state of the chunk in the local store indexes of
+// the node. If the chunk is not in any index, the returned error wraps
+// ErrNotFound.
+func (c *Client) ChunkInfo(ctx context.Context, address swarm.Address) (ChunkInfo, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/chunks/" + address.String() + "/info",
+	})
+	if err != nil {
+		return ChunkInfo{}, err
+	}
+	var info ChunkInfo
+	if err := decodeJSON(resp, &info); err != nil {
+		return ChunkInfo{}, err
+	}
+	return info, nil
+}
+
+// LocalstoreStats are the statistics of the local store indexes and the
+// garbage collection.
+type LocalstoreStats struct {
+	// Indexes holds the number of items by index name.
+	Indexes map[string]int `json:"indexes"`
+	Bins    []struct {
+		Bin    uint8  `json:"bin"`
+		Chunks uint64 `json:"chunks"`
+		BinID  uint64 `json:"binID"`
+	} `json:"bins"`
+	GCSize       uint64 `json:"gcSize"`
+	Capacity     uint64 `json:"capacity"`
+	GCTarget     uint64 `json:"gcTarget"`
+	GCPolicy     string `json:"gcPolicy"`
+	PinnedChunks uint64 `json:"pinnedChunks"`
+	PinCounters  uint64 `json:"pinCounters"`
+	// ReserveRadius is the proximity order of chunks kept in the reserve.
+	ReserveRadius uint8  `json:"reserveRadius"`
+	ReserveSize   uint64 `json:"reserveSize"`
+	GC            struct {
+		Runs uint64 `json:"runs"`
+		// LastRun is zero if the garbage collection has not run.
+		LastRun        time.Time `json:"lastRun"`
+		LastCollected  uint64    `json:"lastCollected"`
+		TotalCollected uint64    `json:"totalCollected"`
+	} `json:"gc"`
+}
+
+// LocalstoreStats returns the statistics of the local store of the node.
+func (c *Client) LocalstoreStats(ctx context.Context) (LocalstoreStats, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/localstore",
+	})
+	if err != nil {
+		return LocalstoreStats{}, err
+	}
+	var stats LocalstoreStats
+	if err := decodeJSON(resp, &stats); err != nil {
+		return LocalstoreStats{}, err
+	}
+	return stats, nil
+}
+
+// CollectGarbage runs the garbage collection of the local store of the
+// node and returns the number of removed chunks.
+func (c *Client) CollectGarbage(ctx context.Context) (uint64, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodPost,
+		path:   "/localstore/gc",
+	})
+	if err != nil {
+		return 0, err
+	}
+	var r struct {
+		Collected uint64 `json:"collected"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return 0, err
+	}
+	return r.Collected, nil
+}
+
+// PinnedChunk is the pinning state of a chunk.
+type PinnedChunk struct {
+	Address    swarm.Address `json:"address"`
+	PinCounter uint64        `json:"pinCounter"`
+}
+
+// PinChunk pins the chunk, increasing its pin counter.
+func (c *Client) PinChunk(ctx context.Context, address swarm.Address) error {
+	return c.pin(ctx, http.MethodPost, address)
+}
+
+// UnpinChunk decreases the pin counter of the chunk.
+func (c *Client) UnpinChunk(ctx context.Context, address swarm.Address) error {
+	return c.pin(ctx, http.MethodDelete, address)
+}
+
+func (c *Client) pin(ctx context.Context, method string, address swarm.Address) error {
+	resp, err := c.debugAPI(ctx, request{
+		method: method,
+		path:   "/chunks-pin/" + address.String(),
+	})
+	if err != nil {
+		return err
+	}
+	drain(resp.Body)
+	return nil
+}
+
+// PinnedChunk returns the pinning state of the chunk.
+func (c *Client) PinnedChunk(ctx context.Context, address swarm.Address) (PinnedChunk, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/chunks-pin/" + address.String(),
+	})
+	if err != nil {
+		return PinnedChunk{}, err
+	}
+	var r PinnedChunk
+	if err := decodeJSON(resp, &r); err != nil {
+		return PinnedChunk{}, err
+	}
+	return r, nil
+}
+
+// PinnedChunks returns the pinning states of all pinned chunks.
+func (c *Client) PinnedChunks(ctx context.Context) ([]PinnedChunk, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/chunks-pin",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		Chunks []PinnedChunk `json:"chunks"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return nil, err
+	}
+	return r.Chunks, nil
+}
+
+// Pin is a named pin of all chunks in the tree of a root reference.
+type Pin struct {
+	Name    string        `json:"name"`
+	Root    swarm.Address `json:"root"`
+	Owner   string        `json:"owner"`
+	Created time.Time     `json:"created"`
+	// Expires is zero if the pin does not expire.
+	Expires time.Time `json:"expires"`
+}
+
+// PinOptions are optional parameters of a new pin.
+type PinOptions struct {
+	// Owner of the pin, the node sets the id of
+	// the client token as the owner if it is empty.
+	Owner string
+	// Expiry is the pin lifetime, the pin
+	// does not expire if it is zero.
+	Expiry time.Duration
+}
+
+type pinRequest struct {
+	Name   string        `json:"name"`
+	Root   swarm.Address `json:"root"`
+	Owner  string        `json:"owner,omitempty"`
+	Expiry int64         `json:"expiry,omitempty"`
+}
+
+// CreatePin pins all chunks in the tree of the root reference with a pin
+// of the name. All chunks of the tree have to be stored on the node.
+func (c *Client) CreatePin(ctx context.Context, name string, root swarm.Address, o PinOptions) (Pin, error) {
+	body, err := json.Marshal(pinRequest{
+		Name:   name,
+		Root:   root,
+		Owner:  o.Owner,
+		Expiry: int64(o.Expiry / time.Second),
+	})
+	if err != nil {
+		return Pin{}, err
+	}
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodPost,
+		path:   "/pins",
+		header: http.Header{"Content-Type": {"application/json"}},
+		body:   bytes.NewReader(body),
+		size:   int64(len(body)),
+	})
+	if err != nil {
+		return Pin{}, err
+	}
+	var p Pin
+	if err := decodeJSON(resp, &p); err != nil {
+		return Pin{}, err
+	}
+	return p, nil
+}
+
+// Pin returns the pin with the name.
+func (c *Client) Pin(ctx context.Context, name string) (Pin, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/pins/" + url.PathEscape(name),
+	})
+	if err != nil {
+		return Pin{}, err
+	}
+	var p Pin
+	if err := decodeJSON(resp, &p); err != nil {
+		return Pin{}, err
+	}
+	return p, nil
+}
+
+// DeletePin removes the pin with the name and
+// unpins all chunks in the tree of its root reference.
+func (c *Client) DeletePin(ctx context.Context, name string) error {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodDelete,
+		path:   "/pins/" + url.PathEscape(name),
+	})
+	if err != nil {
+		return err
+	}
+	drain(resp.Body)
+	return nil
+}
+
+// PinsOptions select and paginate the pins returned by Pins.
+type PinsOptions struct {
+	// After is the next name returned with the previous page.
+	After string
+	// Limit is the maximal number of pins, the node default if it is zero.
+	Limit  int
+	Prefix string
+	Owner  string
+	Root   swarm.Address
+}
+
+// Pins returns pins ordered by their names, selected by the options, and
+// the After option of the next page, which is empty if there are no more
+// pins.
+func (c *Client) Pins(ctx context.Context, o PinsOptions) (pins []Pin, next string, err error) {
+	query := make(url.Values)
+	for k, v := range map[string]string{
+		"after":  o.After,
+		"prefix": o.Prefix,
+		"owner":  o.Owner,
+	} {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if !o.Root.IsZero() {
+		query.Set("root", o.Root.String())
+	}
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/pins",
+		query:  query,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	var r struct {
+		Pins []Pin  `json:"pins"`
+		Next string `json:"next"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return nil, "", err
+	}
+	return r.Pins, r.Next, nil
+}
+
+// Tag is the upload progress of chunks.
+type Tag struct {
+	Total     int64         `json:"total"`
+	Split     int64         `json:"split"`
+	Seen      int64         `json:"seen"`
+	Stored    int64         `json:"stored"`
+	Sent      int64         `json:"sent"`
+	Synced    int64         `json:"synced"`
+	Uid       uint32        `json:"uid"`
+	Anonymous bool          `json:"anonymous"`
+	Name      string        `json:"name"`
+	Address   swarm.Address `json:"address"`
+	StartedAt time.Time     `json:"startedAt"`
+}
+
+// CreateTag creates a new tag with the name, or a generated
+// name if it is empty.
+func (c *Client) CreateTag(ctx context.Context, name string) (Tag, error) {
+	query := make(url.Values)
+	if name != "" {
+		query.Set("name", name)
+	}
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodPost,
+		path:   "/tags",
+		query:  query,
+	})
+	if err != nil {
+		return Tag{}, err
+	}
+	var t Tag
+	if err := decodeJSON(resp, &t); err != nil {
+		return Tag{}, err
+	}
+	return t, nil
+}
+
+// Tag returns the tag with the uid.
+func (c *Client) Tag(ctx context.Context, uid uint32) (Tag, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/tags/" + strconv.FormatUint(uint64(uid), 10),
+	})
+	if err != nil {
+		return Tag{}, err
+	}
+	var t Tag
+	if err := decodeJSON(resp, &t); err != nil {
+		return Tag{}, err
+	}
+	return t, nil
+}
+
+// Topology is the state of the node connectivity.
+type Topology struct {
+	BaseAddr       string                 `json:"baseAddr"`
+	Population     int                    `json:"population"`
+	Connected      int                    `json:"connected"`
+	Timestamp      time.Time              `json:"timestamp"`
+	NNLowWatermark int                    `json:"nnLowWatermark"`
+	Depth          uint8                  `json:"depth"`
+	Bins           map[string]TopologyBin `json:"bins"`
+}
+
+// TopologyBin is the state of the peers in one proximity order bin,
+// with the bin names like bin_0 as keys in Topology.
+type TopologyBin struct {
+	Population        uint     `json:"population"`
+	Connected         uint     `json:"connected"`
+	DisconnectedPeers []string `json:"disconnectedPeers"`
+	ConnectedPeers    []string `json:"connectedPeers"`
+}
+
+// Topology returns the state of the node connectivity.
+func (c *Client) Topology(ctx context.Context) (Topology, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/topology",
+	})
+	if err != nil {
+		return Topology{}, err
+	}
+	var t Topology
+	if err := decodeJSON(resp, &t); err != nil {
+		return Topology{}, err
+	}
+	return t, nil
+}
+
+// Logger is the level of a node logger.
+type Logger struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+// Loggers returns the levels of the node loggers.
+func (c *Client) Loggers(ctx context.Context) ([]Logger, error) {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodGet,
+		path:   "/loggers",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		Loggers []Logger `json:"loggers"`
+	}
+	if err := decodeJSON(resp, &r); err != nil {
+		return nil, err
+	}
+	return r.Loggers, nil
+}
+
+// SetLoggerLevel changes the level of the node logger with the name,
+// for example p2p, to a level like debug or trace.
+func (c *Client) SetLoggerLevel(ctx context.Context, name, level string) error {
+	resp, err := c.debugAPI(ctx, request{
+		method: http.MethodPut,
+		path:   "/loggers/" + url.PathEscape(name) + "/" + url.PathEscape(level),
+	})
+	if err != nil {
+		return err
+	}
+	drain(resp.Body)
+	return nil
+}
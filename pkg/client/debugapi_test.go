@@ -0,0 +1,498 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/addressbook"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/bzz"
+	"github.com/ethersphere/bee/pkg/client"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/p2p"
+	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
+	pingpongmock "github.com/ethersphere/bee/pkg/pingpong/mock"
+	mockstate "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	topologymock "github.com/ethersphere/bee/pkg/topology/mock"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/sirupsen/logrus"
+)
+
+func newDebugAPIClient(t *testing.T, o debugapi.Options) *client.Client {
+	t.Helper()
+
+	if o.Logger == nil {
+		o.Logger = logging.New(ioutil.Discard, 0)
+	}
+	if o.Addressbook == nil {
+		o.Addressbook = addressbook.New(mockstate.NewStateStore())
+	}
+	if o.TopologyDriver == nil {
+		o.TopologyDriver = topologymock.NewTopologyDriver()
+	}
+	if o.Storer == nil {
+		o.Storer = mock.NewStorer()
+	}
+	if o.Tags == nil {
+		o.Tags = tags.NewTags()
+	}
+	// the api shares the storage and tags with the debug api
+	s := api.New(api.Options{
+		Tags:   o.Tags,
+		Storer: o.Storer,
+		Logger: o.Logger,
+	})
+	return newTestClient(t, s, debugapi.New(o), client.Options{})
+}
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+	underlay := mustMultiaddr(t, "/ip4/127.0.0.1/tcp/7070/p2p/16Uiu2HAkx8ULY8cTXhdVAcMmLcH9AsTKz6uBQ7DPLKRjMLgBVYkS")
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	var ready bool
+
+	c := newDebugAPIClient(t, debugapi.Options{
+		Overlay: overlay,
+		P2P: p2pmock.New(p2pmock.WithAddressesFunc(func() ([]multiaddr.Multiaddr, error) {
+			return []multiaddr.Multiaddr{underlay}, nil
+		})),
+		NetworkID: 3,
+		ReadinessChecks: []debugapi.ReadinessCheck{
+			{Name: "peers", Check: func() error {
+				if !ready {
+					return errors.New("no peers")
+				}
+				return nil
+			}},
+		},
+	})
+
+	if err := c.Health(ctx); err != nil {
+		t.Error(err)
+	}
+
+	var e *client.Error
+	if err := c.Readiness(ctx); !errors.As(err, &e) || e.Code != http.StatusServiceUnavailable {
+		t.Errorf("got readiness error %v, want %v status", err, http.StatusServiceUnavailable)
+	}
+	node, err := c.Node(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Ready || len(node.Components) != 1 || node.Components[0].Error != "no peers" {
+		t.Errorf("got not ready node status %+v", node)
+	}
+
+	ready = true
+	if err := c.Readiness(ctx); err != nil {
+		t.Error(err)
+	}
+	node, err = c.Node(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !node.Ready || !node.Overlay.Equal(overlay) || len(node.Underlay) != 1 || node.NetworkID != 3 {
+		t.Errorf("got ready node status %+v", node)
+	}
+}
+
+func TestPeers(t *testing.T) {
+	ctx := context.Background()
+	underlay := mustMultiaddr(t, "/ip4/127.0.0.1/tcp/7070/p2p/16Uiu2HAkx8ULY8cTXhdVAcMmLcH9AsTKz6uBQ7DPLKRjMLgBVYkS")
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	peer := swarm.MustParseHexAddress("2f7e8e8a8a8d5dbb6cfc8c6d2e4d4a2c0f1e2d3c4b5a69788796a5b4c3d2e1f0")
+
+	privateKey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bzzAddress, err := bzz.NewAddress(crypto.NewDefaultSigner(privateKey), underlay, peer, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connectedAt := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	var disconnected swarm.Address
+	c := newDebugAPIClient(t, debugapi.Options{
+		Overlay: overlay,
+		P2P: p2pmock.New(
+			p2pmock.WithAddressesFunc(func() ([]multiaddr.Multiaddr, error) {
+				return []multiaddr.Multiaddr{underlay}, nil
+			}),
+			p2pmock.WithConnectFunc(func(ctx context.Context, addr multiaddr.Multiaddr) (*bzz.Address, error) {
+				return bzzAddress, nil
+			}),
+			p2pmock.WithPeersFunc(func() []p2p.Peer {
+				return []p2p.Peer{{Address: peer}}
+			}),
+			p2pmock.WithPeerFunc(func(addr swarm.Address) (p2p.PeerInfo, error) {
+				if !addr.Equal(peer) {
+					return p2p.PeerInfo{}, p2p.ErrPeerNotFound
+				}
+				return p2p.PeerInfo{
+					Peer:        p2p.Peer{Address: peer},
+					Underlays:   []multiaddr.Multiaddr{underlay},
+					Direction:   p2p.DirectionOutbound,
+					ConnectedAt: connectedAt,
+					Protocols: map[string]p2p.ProtocolStats{
+						"pingpong": {BytesSent: 42, BytesReceived: 24},
+					},
+				}, nil
+			}),
+			p2pmock.WithDisconnectFunc(func(addr swarm.Address) error {
+				disconnected = addr
+				return nil
+			}),
+		),
+		Pingpong: pingpongmock.New(func(ctx context.Context, address swarm.Address, msgs ...string) (time.Duration, error) {
+			return 5 * time.Millisecond, nil
+		}),
+	})
+
+	addresses, err := c.Addresses(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addresses.Overlay.Equal(overlay) {
+		t.Errorf("got overlay %s, want %s", addresses.Overlay, overlay)
+	}
+	if len(addresses.Underlay) != 1 || !addresses.Underlay[0].Equal(underlay) {
+		t.Errorf("got underlay %v, want %v", addresses.Underlay, underlay)
+	}
+
+	connected, err := c.Connect(ctx, underlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !connected.Equal(peer) {
+		t.Errorf("got connected peer %s, want %s", connected, peer)
+	}
+
+	peers, err := c.Peers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 1 || !peers[0].Equal(peer) {
+		t.Errorf("got peers %v, want %v", peers, []swarm.Address{peer})
+	}
+
+	rtt, err := c.Ping(ctx, peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rtt != 5*time.Millisecond {
+		t.Errorf("got rtt %v, want %v", rtt, 5*time.Millisecond)
+	}
+
+	info, err := c.Peer(ctx, peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (client.PeerInfo{
+		Address:     peer,
+		Underlays:   []multiaddr.Multiaddr{underlay},
+		Direction:   "outbound",
+		ConnectedAt: connectedAt,
+		LastRTT:     5 * time.Millisecond,
+		Protocols: map[string]client.ProtocolStats{
+			"pingpong": {BytesSent: 42, BytesReceived: 24},
+		},
+	}); !reflect.DeepEqual(info, want) {
+		t.Errorf("got peer %+v, want %+v", info, want)
+	}
+	if _, err := c.Peer(ctx, overlay); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, client.ErrNotFound)
+	}
+
+	if err := c.Disconnect(ctx, peer); err != nil {
+		t.Fatal(err)
+	}
+	if !disconnected.Equal(peer) {
+		t.Errorf("got disconnected peer %s, want %s", disconnected, peer)
+	}
+}
+
+func TestChunkInfo(t *testing.T) {
+	ctx := context.Background()
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	db, err := localstore.New("", overlay.Bytes(), nil, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	c := newDebugAPIClient(t, debugapi.Options{Overlay: overlay, Storer: db})
+	ch := swarm.NewChunk(swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf500"), []byte("chunk data"))
+
+	if _, err := c.ChunkInfo(ctx, ch.Address()); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, client.ErrNotFound)
+	}
+	if _, err := db.Put(ctx, storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := c.ChunkInfo(ctx, ch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Address.Equal(ch.Address()) {
+		t.Errorf("got address %s, want %s", info.Address, ch.Address())
+	}
+	if !info.Indexes.Retrieval || !info.Indexes.Push || !info.Indexes.Pull || info.Indexes.GC {
+		t.Errorf("got indexes %+v, want retrieval, push and pull", info.Indexes)
+	}
+	if info.StoreTimestamp.IsZero() || !info.AccessTimestamp.IsZero() {
+		t.Errorf("got store time %v and access time %v, want only store time", info.StoreTimestamp, info.AccessTimestamp)
+	}
+	if info.Proximity != 15 || !info.WithinDepth {
+		t.Errorf("got proximity %v within depth %v, want 15 within depth", info.Proximity, info.WithinDepth)
+	}
+}
+
+func TestLocalstore(t *testing.T) {
+	ctx := context.Background()
+	db, err := localstore.New("", swarm.ZeroAddress.Bytes(), &localstore.Options{Capacity: 10}, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	c := newDebugAPIClient(t, debugapi.Options{Storer: db})
+	ch := swarm.NewChunk(swarm.MustParseHexAddress("aabbcc"), []byte("chunk data"))
+	if _, err := db.Put(ctx, storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := c.LocalstoreStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Capacity != 10 || stats.GCTarget != 9 || stats.Indexes["pushIndex"] != 1 {
+		t.Errorf("got capacity %v, gc target %v and indexes %v", stats.Capacity, stats.GCTarget, stats.Indexes)
+	}
+
+	collected, err := c.CollectGarbage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collected != 0 {
+		t.Errorf("got %v collected chunks, want 0", collected)
+	}
+
+	stats, err = c.LocalstoreStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.GC.Runs != 1 || stats.GC.LastRun.IsZero() {
+		t.Errorf("got gc stats %+v, want one run", stats.GC)
+	}
+}
+
+func TestPinning(t *testing.T) {
+	ctx := context.Background()
+	storer := mock.NewStorer()
+	c := newDebugAPIClient(t, debugapi.Options{Storer: storer})
+	ch := swarm.NewChunk(swarm.MustParseHexAddress("aabbcc"), []byte("chunk data"))
+
+	if ok, err := c.HasChunk(ctx, ch.Address()); err != nil || ok {
+		t.Fatalf("got has chunk %v, %v, want false", ok, err)
+	}
+	if _, err := storer.Put(ctx, storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.HasChunk(ctx, ch.Address()); err != nil || !ok {
+		t.Fatalf("got has chunk %v, %v, want true", ok, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := c.PinChunk(ctx, ch.Address()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.UnpinChunk(ctx, ch.Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := client.PinnedChunk{Address: ch.Address(), PinCounter: 1}
+	pinned, err := c.PinnedChunk(ctx, ch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pinned.Address.Equal(want.Address) || pinned.PinCounter != want.PinCounter {
+		t.Errorf("got pinned chunk %+v, want %+v", pinned, want)
+	}
+
+	all, err := c.PinnedChunks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || !all[0].Address.Equal(want.Address) || all[0].PinCounter != want.PinCounter {
+		t.Errorf("got pinned chunks %+v, want %+v", all, []client.PinnedChunk{want})
+	}
+
+	if _, err := c.PinnedChunk(ctx, swarm.MustParseHexAddress("ddeeff")); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, client.ErrNotFound)
+	}
+}
+
+func TestPins(t *testing.T) {
+	ctx := context.Background()
+	db, err := localstore.New("", swarm.ZeroAddress.Bytes(), nil, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	c := newDebugAPIClient(t, debugapi.Options{Storer: db})
+	data := []byte("pinned data")
+	root, err := c.UploadBytes(ctx, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := c.CreatePin(ctx, "first", root, client.PinOptions{Owner: "owner", Expiry: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "first" || !created.Root.Equal(root) || created.Owner != "owner" || created.Expires.IsZero() {
+		t.Errorf("got pin %+v", created)
+	}
+	if _, err := c.CreatePin(ctx, "second", root, client.PinOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := c.Pin(ctx, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != created.Name || !p.Created.Equal(created.Created) || !p.Expires.Equal(created.Expires) {
+		t.Errorf("got pin %+v, want %+v", p, created)
+	}
+
+	pins, next, err := c.Pins(ctx, client.PinsOptions{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pins) != 1 || pins[0].Name != "first" || next != "first" {
+		t.Errorf("got pins %+v and next %q", pins, next)
+	}
+	pins, next, err = c.Pins(ctx, client.PinsOptions{After: next, Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pins) != 1 || pins[0].Name != "second" || !pins[0].Expires.IsZero() || next != "" {
+		t.Errorf("got pins %+v and next %q", pins, next)
+	}
+
+	if err := c.DeletePin(ctx, "first"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Pin(ctx, "first"); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, client.ErrNotFound)
+	}
+}
+
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+	c := newDebugAPIClient(t, debugapi.Options{})
+
+	created, err := c.CreateTag(ctx, "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "upload" || created.Uid == 0 {
+		t.Errorf("got tag %+v", created)
+	}
+
+	// the uploaded chunk is counted in the created tag
+	ch := swarm.NewChunk(swarm.MustParseHexAddress("aabbcc"), []byte("chunk data"))
+	if _, err := c.UploadChunk(ctx, ch, client.ChunkUploadOptions{Tag: created.Uid}); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := c.Tag(ctx, created.Uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Uid != created.Uid || tag.Stored != 1 {
+		t.Errorf("got tag %+v, want uid %v with one stored chunk", tag, created.Uid)
+	}
+
+	if _, err := c.Tag(ctx, created.Uid+1); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, client.ErrNotFound)
+	}
+}
+
+func TestTopology(t *testing.T) {
+	c := newDebugAPIClient(t, debugapi.Options{
+		TopologyDriver: topologymock.NewTopologyDriver(topologymock.WithMarshalJSONFunc(func() ([]byte, error) {
+			return []byte(`{"baseAddr":"aabbcc","population":3,"connected":1,"nnLowWatermark":2,"depth":4,"bins":{"bin_0":{"population":3,"connected":1,"connectedPeers":["ddeeff"]}}}`), nil
+		})),
+	})
+
+	topology, err := c.Topology(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topology.BaseAddr != "aabbcc" || topology.Population != 3 || topology.Connected != 1 || topology.Depth != 4 {
+		t.Errorf("got topology %+v", topology)
+	}
+	bin := topology.Bins["bin_0"]
+	if bin.Population != 3 || bin.Connected != 1 || len(bin.ConnectedPeers) != 1 || bin.ConnectedPeers[0] != "ddeeff" {
+		t.Errorf("got bin %+v", bin)
+	}
+}
+
+func TestLoggers(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(ioutil.Discard, logrus.InfoLevel)
+	logger.Named("p2p")
+	c := newDebugAPIClient(t, debugapi.Options{Logger: logger})
+
+	if err := c.SetLoggerLevel(ctx, "p2p", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetLoggerLevel(ctx, "pusher", "debug"); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, client.ErrNotFound)
+	}
+
+	loggers, err := c.Loggers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []client.Logger{
+		{Name: "p2p", Level: "debug"},
+		{Name: "root", Level: "info"},
+	}
+	if !reflect.DeepEqual(loggers, want) {
+		t.Errorf("got loggers %+v, want %+v", loggers, want)
+	}
+}
+
+func mustMultiaddr(t *testing.T, s string) multiaddr.Multiaddr {
+	t.Helper()
+
+	a, err := multiaddr.NewMultiaddr(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auth"
+	mockstatestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+// TestAuth tests that the debug api requires tokens with the admin
+// scope, or the pin scope for pinning, when the authenticator is configured.
+func TestAuth(t *testing.T) {
+	const password = "secret"
+
+	authenticator := auth.New(mockstatestore.NewStateStore(), password)
+	testServer := newTestServer(t, testServerOptions{
+		Storer:        mock.NewStorer(),
+		Tags:          tags.NewTags(),
+		Authenticator: authenticator,
+	})
+
+	issue := func(t *testing.T, scope auth.Scope) string {
+		t.Helper()
+
+		key, _, err := authenticator.Issue(password, []auth.Scope{scope}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+
+	request := func(t *testing.T, resource, token string, responseCode int) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, resource, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := testServer.Client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != responseCode {
+			t.Fatalf("%s: got response status %s, want %v %s", resource, resp.Status, responseCode, http.StatusText(responseCode))
+		}
+	}
+
+	var (
+		admin = issue(t, auth.ScopeAdmin)
+		pin   = issue(t, auth.ScopePin)
+		read  = issue(t, auth.ScopeRead)
+	)
+
+	// health and readiness are always available
+	request(t, "/health", "", http.StatusOK)
+	request(t, "/readiness", "", http.StatusOK)
+
+	request(t, "/chunks-pin/aabbcc", "", http.StatusUnauthorized)
+	request(t, "/chunks-pin/aabbcc", read, http.StatusForbidden)
+	request(t, "/chunks-pin/aabbcc", pin, http.StatusNotFound)
+	request(t, "/chunks-pin/aabbcc", admin, http.StatusNotFound)
+
+	request(t, "/pins", "", http.StatusUnauthorized)
+	request(t, "/pins/name", read, http.StatusForbidden)
+	request(t, "/pins/name", pin, http.StatusNotImplemented)
+
+	request(t, "/tags/1", "", http.StatusUnauthorized)
+	request(t, "/tags/1", pin, http.StatusForbidden)
+	request(t, "/tags/1", admin, http.StatusNotFound)
+
+	request(t, "/metrics", "", http.StatusUnauthorized)
+	request(t, "/metrics", pin, http.StatusForbidden)
+	request(t, "/metrics", admin, http.StatusOK)
+}
@@ -5,9 +5,13 @@
 package debugapi
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/gorilla/mux"
 )
@@ -16,7 +20,7 @@ func (s *server) hasChunkHandler(w http.ResponseWriter, r *http.Request) {
 	addr, err := swarm.ParseHexAddress(mux.Vars(r)["address"])
 	if err != nil {
 		s.Logger.Debugf("debug api: parse chunk address: %v", err)
-		jsonhttp.BadRequest(w, "bad address")
+		jsonhttp.BadRequest(w, jsonhttp.Error(jsonhttp.ReasonInvalidAddress, "bad address"))
 		return
 	}
 
@@ -32,5 +36,96 @@ func (s *server) hasChunkHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	jsonhttp.OK(w, nil)
+}
+
+// chunkInspector is implemented by storers that expose
+// the state of chunks in their indexes, like the localstore.
+type chunkInspector interface {
+	ChunkInfo(addr swarm.Address) (localstore.ChunkInfo, error)
+}
+
+// depther is implemented by topology drivers that
+// maintain the neighborhood depth, like kademlia.
+type depther interface {
+	NeighborhoodDepth() uint8
+}
+
+type chunkInfoResponse struct {
+	Address swarm.Address        `json:"address"`
+	Indexes chunkIndexesResponse `json:"indexes"`
+	BinID   uint64               `json:"binID"`
+	// StoreTimestamp and AccessTimestamp are omitted if the chunk
+	// is not in the retrieval indexes.
+	StoreTimestamp  *time.Time `json:"storeTimestamp,omitempty"`
+	AccessTimestamp *time.Time `json:"accessTimestamp,omitempty"`
+	PinCounter      uint64     `json:"pinCounter"`
+	Tag             uint32     `json:"tag"`
+	Proximity       uint8      `json:"proximity"`
+	WithinDepth     bool       `json:"withinDepth"`
+}
+
+type chunkIndexesResponse struct {
+	Retrieval bool `json:"retrieval"`
+	Push      bool `json:"push"`
+	Pull      bool `json:"pull"`
+	GC        bool `json:"gc"`
+	GCExclude bool `json:"gcExclude"`
+	Pin       bool `json:"pin"`
+}
+
+func (s *server) chunkInfoHandler(w http.ResponseWriter, r *http.Request) {
+	addr, err := swarm.ParseHexAddress(mux.Vars(r)["address"])
+	if err != nil {
+		s.Logger.Debugf("debug api: chunk info: parse chunk address: %v", err)
+		jsonhttp.BadRequest(w, jsonhttp.Error(jsonhttp.ReasonInvalidAddress, "bad address"))
+		return
+	}
+
+	inspector, ok := s.Storer.(chunkInspector)
+	if !ok {
+		s.Logger.Debug("debug api: chunk info: storer does not expose its indexes")
+		jsonhttp.NotImplemented(w, "chunk inspection not supported")
+		return
+	}
+
+	info, err := inspector.ChunkInfo(addr)
+	if err != nil {
+		s.Logger.Debugf("debug api: chunk info %s: %v", addr, err)
+		if errors.Is(err, storage.ErrNotFound) {
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		s.Logger.Errorf("debug api: chunk info %s", addr)
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+
+	resp := chunkInfoResponse{
+		Address: addr,
+		Indexes: chunkIndexesResponse{
+			Retrieval: info.Indexes.Retrieval,
+			Push:      info.Indexes.Push,
+			Pull:      info.Indexes.Pull,
+			GC:        info.Indexes.GC,
+			GCExclude: info.Indexes.GCExclude,
+			Pin:       info.Indexes.Pin,
+		},
+		BinID:      info.BinID,
+		PinCounter: info.PinCounter,
+		Tag:        info.Tag,
+		Proximity:  swarm.Proximity(s.Overlay.Bytes(), addr.Bytes()),
+	}
+	if info.StoreTimestamp != 0 {
+		t := time.Unix(0, info.StoreTimestamp).UTC()
+		resp.StoreTimestamp = &t
+	}
+	if info.AccessTimestamp != 0 {
+		t := time.Unix(0, info.AccessTimestamp).UTC()
+		resp.AccessTimestamp = &t
+	}
+	if d, ok := s.TopologyDriver.(depther); ok {
+		resp.WithinDepth = resp.Proximity >= d.NeighborhoodDepth()
+	}
 
+	jsonhttp.OK(w, resp)
 }
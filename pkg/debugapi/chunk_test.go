@@ -8,12 +8,16 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/ethersphere/bee/pkg/debugapi"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/localstore"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
+	topmock "github.com/ethersphere/bee/pkg/topology/mock"
 )
 
 func TestHasChunkHandler(t *testing.T) {
@@ -48,6 +52,110 @@ func TestHasChunkHandler(t *testing.T) {
 		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/chunks/abcd1100zz", nil, http.StatusBadRequest, jsonhttp.StatusResponse{
 			Message: "bad address",
 			Code:    http.StatusBadRequest,
+			Reason:  jsonhttp.ReasonInvalidAddress,
 		})
 	})
 }
+
+func TestChunkInfoHandler(t *testing.T) {
+	overlay := swarm.MustParseHexAddress("c000000000000000000000000000000000000000000000000000000000000000")
+	address := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	unknownAddress := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59e")
+	storeTime := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	accessTime := storeTime.Add(time.Minute)
+
+	storer := &inspectorStorer{
+		Storer: mock.NewStorer(),
+		infos: map[string]localstore.ChunkInfo{
+			address.String(): {
+				Indexes:         localstore.ChunkIndexes{Retrieval: true, Pull: true, GC: true},
+				BinID:           42,
+				StoreTimestamp:  storeTime.UnixNano(),
+				AccessTimestamp: accessTime.UnixNano(),
+				Tag:             7,
+			},
+		},
+	}
+
+	want := debugapi.ChunkInfoResponse{
+		Address:         address,
+		Indexes:         debugapi.ChunkIndexesResponse{Retrieval: true, Pull: true, GC: true},
+		BinID:           42,
+		StoreTimestamp:  &storeTime,
+		AccessTimestamp: &accessTime,
+		Tag:             7,
+		Proximity:       4,
+		WithinDepth:     true,
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Overlay:      overlay,
+			Storer:       storer,
+			TopologyOpts: []topmock.Option{topmock.WithNeighborhoodDepth(4)},
+		})
+
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/chunks/"+address.String()+"/info", nil, http.StatusOK, want)
+	})
+
+	t.Run("outside depth", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Overlay:      overlay,
+			Storer:       storer,
+			TopologyOpts: []topmock.Option{topmock.WithNeighborhoodDepth(5)},
+		})
+
+		want := want
+		want.WithinDepth = false
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/chunks/"+address.String()+"/info", nil, http.StatusOK, want)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Storer: storer,
+		})
+
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/chunks/"+unknownAddress.String()+"/info", nil, http.StatusNotFound, jsonhttp.StatusResponse{
+			Message: http.StatusText(http.StatusNotFound),
+			Code:    http.StatusNotFound,
+		})
+	})
+
+	t.Run("bad address", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Storer: storer,
+		})
+
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/chunks/abcd1100zz/info", nil, http.StatusBadRequest, jsonhttp.StatusResponse{
+			Message: "bad address",
+			Code:    http.StatusBadRequest,
+			Reason:  jsonhttp.ReasonInvalidAddress,
+		})
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Storer: mock.NewStorer(),
+		})
+
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/chunks/"+address.String()+"/info", nil, http.StatusNotImplemented, jsonhttp.StatusResponse{
+			Message: "chunk inspection not supported",
+			Code:    http.StatusNotImplemented,
+		})
+	})
+}
+
+// inspectorStorer is a storer that exposes the state
+// of chunks in its indexes, like the localstore.
+type inspectorStorer struct {
+	storage.Storer
+	infos map[string]localstore.ChunkInfo
+}
+
+func (s *inspectorStorer) ChunkInfo(addr swarm.Address) (localstore.ChunkInfo, error) {
+	info, ok := s.infos[addr.String()]
+	if !ok {
+		return localstore.ChunkInfo{}, storage.ErrNotFound
+	}
+	return info, nil
+}
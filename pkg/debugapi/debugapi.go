@@ -6,9 +6,15 @@ package debugapi
 
 import (
 	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/openapi"
 
 	"github.com/ethersphere/bee/pkg/addressbook"
+	"github.com/ethersphere/bee/pkg/apispec"
+	"github.com/ethersphere/bee/pkg/auth"
 	"github.com/ethersphere/bee/pkg/logging"
+	m "github.com/ethersphere/bee/pkg/metrics"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/pingpong"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -16,6 +22,7 @@ import (
 	"github.com/ethersphere/bee/pkg/tags"
 	"github.com/ethersphere/bee/pkg/topology"
 	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -28,7 +35,11 @@ type server struct {
 	Options
 	http.Handler
 
+	router          *mux.Router
+	spec            *apispec.Spec
 	metricsRegistry *prometheus.Registry
+	metrics         metrics
+	startTime       time.Time
 }
 
 type Options struct {
@@ -41,15 +52,46 @@ type Options struct {
 	Logger         logging.Logger
 	Tracer         *tracing.Tracer
 	Tags           *tags.Tags
+	NetworkID      uint64
+	// ReadinessChecks are the conditions that the node components
+	// have to satisfy for the node to be reported as ready.
+	ReadinessChecks []ReadinessCheck
+	// Authenticator authorizes requests with bearer tokens.
+	// If it is nil, the debug API is not protected.
+	Authenticator *auth.Authenticator
+	// ValidateSpec enables validation of requests and responses against
+	// the OpenAPI document. Invalid requests are rejected and invalid
+	// responses are logged.
+	ValidateSpec bool
 }
 
 func New(o Options) Service {
 	s := &server{
 		Options:         o,
 		metricsRegistry: newMetricsRegistry(),
+		metrics:         newMetrics(),
+		startTime:       time.Now(),
+	}
+	s.metricsRegistry.MustRegister(m.PrometheusCollectorsFromFields(s.metrics)...)
+
+	if o.ValidateSpec {
+		spec, err := loadSpec()
+		if err != nil {
+			s.Logger.Debugf("debug api: load openapi document: %v", err)
+			s.Logger.Error("debug api: spec validation disabled")
+		}
+		s.spec = spec
 	}
 
 	s.setupRouting()
 
 	return s
 }
+
+// loadSpec returns the parsed OpenAPI document of the debug API.
+func loadSpec() (*apispec.Spec, error) {
+	return apispec.Load("SwarmDebug.yaml", map[string]string{
+		"SwarmDebug.yaml":  openapi.SwarmDebug,
+		"SwarmCommon.yaml": openapi.SwarmCommon,
+	})
+}
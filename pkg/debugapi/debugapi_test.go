@@ -13,7 +13,9 @@ import (
 
 	"github.com/ethersphere/bee/pkg/addressbook"
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auth"
 	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/pingpong"
@@ -28,12 +30,17 @@ import (
 )
 
 type testServerOptions struct {
-	Overlay      swarm.Address
-	P2P          p2p.Service
-	Pingpong     pingpong.Interface
-	Storer       storage.Storer
-	TopologyOpts []mock.Option
-	Tags         *tags.Tags
+	Overlay       swarm.Address
+	P2P           p2p.Service
+	Pingpong      pingpong.Interface
+	Storer        storage.Storer
+	TopologyOpts  []mock.Option
+	Tags          *tags.Tags
+	Authenticator *auth.Authenticator
+	ValidateSpec  bool
+	NetworkID     uint64
+	Checks        []debugapi.ReadinessCheck
+	Logger        logging.Logger
 }
 
 type testServer struct {
@@ -46,16 +53,23 @@ func newTestServer(t *testing.T, o testServerOptions) *testServer {
 	statestore := mockstore.NewStateStore()
 	addrbook := addressbook.New(statestore)
 	topologyDriver := mock.NewTopologyDriver(o.TopologyOpts...)
+	if o.Logger == nil {
+		o.Logger = logging.New(ioutil.Discard, 0)
+	}
 
 	s := debugapi.New(debugapi.Options{
-		Overlay:        o.Overlay,
-		P2P:            o.P2P,
-		Pingpong:       o.Pingpong,
-		Tags:           o.Tags,
-		Logger:         logging.New(ioutil.Discard, 0),
-		Addressbook:    addrbook,
-		Storer:         o.Storer,
-		TopologyDriver: topologyDriver,
+		Overlay:         o.Overlay,
+		P2P:             o.P2P,
+		Pingpong:        o.Pingpong,
+		Tags:            o.Tags,
+		Logger:          o.Logger,
+		Addressbook:     addrbook,
+		Storer:          o.Storer,
+		TopologyDriver:  topologyDriver,
+		Authenticator:   o.Authenticator,
+		ValidateSpec:    o.ValidateSpec,
+		NetworkID:       o.NetworkID,
+		ReadinessChecks: o.Checks,
 	})
 	ts := httptest.NewServer(s)
 	t.Cleanup(ts.Close)
@@ -70,8 +84,14 @@ func newTestServer(t *testing.T, o testServerOptions) *testServer {
 			return ts.Client().Transport.RoundTrip(r)
 		}),
 	}
+
+	// all responses are validated against the api spec
+	spec, err := debugapi.LoadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
 	return &testServer{
-		Client:      client,
+		Client:      jsonhttptest.WithSpecValidation(t, client, spec),
 		Addressbook: addrbook,
 	}
 }
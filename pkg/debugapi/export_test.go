@@ -4,13 +4,37 @@
 
 package debugapi
 
+import "github.com/gorilla/mux"
+
 type (
 	StatusResponse           = statusResponse
+	ComponentStatus          = componentStatus
+	NodeResponse             = nodeResponse
 	PingpongResponse         = pingpongResponse
 	PeerConnectResponse      = peerConnectResponse
 	PeersResponse            = peersResponse
+	PeerResponse             = peerResponse
+	ProtocolStatsResponse    = protocolStatsResponse
 	AddressesResponse        = addressesResponse
+	ChunkInfoResponse        = chunkInfoResponse
+	ChunkIndexesResponse     = chunkIndexesResponse
+	LocalstoreResponse       = localstoreResponse
+	BinResponse              = binResponse
+	GCStatsResponse          = gcStatsResponse
+	CollectGarbageResponse   = collectGarbageResponse
 	PinnedChunk              = pinnedChunk
 	ListPinnedChunksResponse = listPinnedChunksResponse
+	PinRequest               = pinRequest
+	PinResponse              = pinResponse
+	ListPinsResponse         = listPinsResponse
 	TagResponse              = tagResponse
+	LoggerResponse           = loggerResponse
+	LoggersResponse          = loggersResponse
 )
+
+var LoadSpec = loadSpec
+
+// Router returns the router with all debug API routes of the service.
+func Router(s Service) *mux.Router {
+	return s.(*server).router
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/localstore"
+)
+
+// localstoreStatser is implemented by storers that
+// keep statistics of their indexes, like the localstore.
+type localstoreStatser interface {
+	Stats() (localstore.Stats, error)
+}
+
+// garbageCollector is implemented by storers
+// with garbage collection, like the localstore.
+type garbageCollector interface {
+	CollectGarbage(ctx context.Context) (collectedCount uint64, err error)
+}
+
+type localstoreResponse struct {
+	Indexes       map[string]int  `json:"indexes"`
+	Bins          []binResponse   `json:"bins"`
+	GCSize        uint64          `json:"gcSize"`
+	Capacity      uint64          `json:"capacity"`
+	GCTarget      uint64          `json:"gcTarget"`
+	GCPolicy      string          `json:"gcPolicy"`
+	PinnedChunks  uint64          `json:"pinnedChunks"`
+	PinCounters   uint64          `json:"pinCounters"`
+	ReserveRadius uint8           `json:"reserveRadius"`
+	ReserveSize   uint64          `json:"reserveSize"`
+	GC            gcStatsResponse `json:"gc"`
+}
+
+type binResponse struct {
+	Bin    uint8  `json:"bin"`
+	Chunks uint64 `json:"chunks"`
+	BinID  uint64 `json:"binID"`
+}
+
+type gcStatsResponse struct {
+	Runs uint64 `json:"runs"`
+	// LastRun is omitted if garbage collection has not run.
+	LastRun        *time.Time `json:"lastRun,omitempty"`
+	LastCollected  uint64     `json:"lastCollected"`
+	TotalCollected uint64     `json:"totalCollected"`
+}
+
+type collectGarbageResponse struct {
+	Collected uint64 `json:"collected"`
+}
+
+func (s *server) localstoreHandler(w http.ResponseWriter, r *http.Request) {
+	statser, ok := s.Storer.(localstoreStatser)
+	if !ok {
+		s.Logger.Debug("debug api: localstore: storer does not keep statistics")
+		jsonhttp.NotImplemented(w, "localstore statistics not supported")
+		return
+	}
+
+	stats, err := statser.Stats()
+	if err != nil {
+		s.Logger.Debugf("debug api: localstore: stats: %v", err)
+		s.Logger.Error("debug api: localstore: stats")
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+
+	resp := localstoreResponse{
+		Indexes:       stats.Indexes,
+		Bins:          make([]binResponse, 0, len(stats.Bins)),
+		GCSize:        stats.GCSize,
+		Capacity:      stats.Capacity,
+		GCTarget:      stats.GCTarget,
+		GCPolicy:      stats.GCPolicy,
+		PinnedChunks:  stats.PinnedChunks,
+		PinCounters:   stats.PinCounters,
+		ReserveRadius: stats.ReserveRadius,
+		ReserveSize:   stats.ReserveSize,
+		GC: gcStatsResponse{
+			Runs:           stats.GC.Runs,
+			LastCollected:  stats.GC.LastCollected,
+			TotalCollected: stats.GC.TotalCollected,
+		},
+	}
+	for bin, b := range stats.Bins {
+		resp.Bins = append(resp.Bins, binResponse{
+			Bin:    uint8(bin),
+			Chunks: b.Chunks,
+			BinID:  b.BinID,
+		})
+	}
+	if !stats.GC.LastRun.IsZero() {
+		resp.GC.LastRun = &stats.GC.LastRun
+	}
+
+	jsonhttp.OK(w, resp)
+}
+
+func (s *server) collectGarbageHandler(w http.ResponseWriter, r *http.Request) {
+	collector, ok := s.Storer.(garbageCollector)
+	if !ok {
+		s.Logger.Debug("debug api: collect garbage: storer does not collect garbage")
+		jsonhttp.NotImplemented(w, "garbage collection not supported")
+		return
+	}
+
+	collected, err := collector.CollectGarbage(r.Context())
+	if err != nil {
+		s.Logger.Debugf("debug api: collect garbage: %v", err)
+		s.Logger.Error("debug api: collect garbage")
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+
+	s.Logger.Infof("garbage collection removed %d chunks", collected)
+	jsonhttp.OK(w, collectGarbageResponse{
+		Collected: collected,
+	})
+}
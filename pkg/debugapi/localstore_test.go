@@ -0,0 +1,109 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestLocalstore(t *testing.T) {
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	db, err := localstore.New("", overlay.Bytes(), &localstore.Options{Capacity: 100}, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	// stay below the capacity, so that the garbage
+	// collection is not triggered, but above the target
+	chunks := testingc.GenerateTestRandomChunks(95)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	for _, ch := range chunks {
+		if err := db.Set(context.Background(), storage.ModeSetSyncPull, ch.Address()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testServer := newTestServer(t, testServerOptions{
+		Overlay: overlay,
+		Storer:  db,
+	})
+
+	var stats debugapi.LocalstoreResponse
+	jsonhttptest.ResponseUnmarshal(t, testServer.Client, http.MethodGet, "/localstore", nil, http.StatusOK, &stats)
+
+	if stats.GCSize != 95 || stats.Capacity != 100 || stats.GCTarget != 90 {
+		t.Errorf("got gc size %v, capacity %v and target %v, want 95, 100 and 90", stats.GCSize, stats.Capacity, stats.GCTarget)
+	}
+	if stats.GCPolicy != "lru" {
+		t.Errorf("got gc policy %q, want %q", stats.GCPolicy, "lru")
+	}
+	if got := stats.Indexes["pullIndex"]; got != 95 {
+		t.Errorf("got %v chunks in pull index, want 95", got)
+	}
+	if len(stats.Bins) != int(swarm.MaxPO)+1 {
+		t.Fatalf("got %v bins, want %v", len(stats.Bins), swarm.MaxPO+1)
+	}
+	var binChunks uint64
+	for i, b := range stats.Bins {
+		if int(b.Bin) != i {
+			t.Errorf("got bin %v at position %v", b.Bin, i)
+		}
+		binChunks += b.Chunks
+	}
+	if binChunks != 95 {
+		t.Errorf("got %v chunks in bins, want 95", binChunks)
+	}
+	if stats.ReserveRadius != swarm.MaxPO+1 || stats.ReserveSize != 0 {
+		t.Errorf("got reserve radius %v with %v chunks, want no reserve", stats.ReserveRadius, stats.ReserveSize)
+	}
+	if stats.GC.Runs != 0 || stats.GC.LastRun != nil {
+		t.Errorf("got gc stats %+v, want no runs", stats.GC)
+	}
+
+	jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPost, "/localstore/gc", nil, http.StatusOK, debugapi.CollectGarbageResponse{
+		Collected: 5,
+	})
+
+	jsonhttptest.ResponseUnmarshal(t, testServer.Client, http.MethodGet, "/localstore", nil, http.StatusOK, &stats)
+
+	if stats.GCSize != 90 {
+		t.Errorf("got gc size %v, want 90", stats.GCSize)
+	}
+	if stats.GC.Runs != 1 || stats.GC.LastRun == nil || stats.GC.LastCollected != 5 || stats.GC.TotalCollected != 5 {
+		t.Errorf("got gc stats %+v, want one run with 5 collected chunks", stats.GC)
+	}
+}
+
+func TestLocalstoreNotSupported(t *testing.T) {
+	testServer := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+	})
+
+	jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/localstore", nil, http.StatusNotImplemented, jsonhttp.StatusResponse{
+		Message: "localstore statistics not supported",
+		Code:    http.StatusNotImplemented,
+	})
+
+	jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPost, "/localstore/gc", nil, http.StatusNotImplemented, jsonhttp.StatusResponse{
+		Message: "garbage collection not supported",
+		Code:    http.StatusNotImplemented,
+	})
+}
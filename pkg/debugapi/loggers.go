@@ -0,0 +1,67 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type loggerResponse struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+type loggersResponse struct {
+	Loggers []loggerResponse `json:"loggers"`
+}
+
+func (s *server) loggersHandler(w http.ResponseWriter, r *http.Request) {
+	levels := s.Logger.Loggers()
+
+	loggers := make([]loggerResponse, 0, len(levels))
+	for _, l := range levels {
+		loggers = append(loggers, loggerResponse{
+			Name:  l.Name,
+			Level: l.Level.String(),
+		})
+	}
+
+	jsonhttp.OK(w, loggersResponse{
+		Loggers: loggers,
+	})
+}
+
+func (s *server) setLoggerLevelHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	level, err := logrus.ParseLevel(mux.Vars(r)["level"])
+	if err != nil {
+		s.Logger.Debugf("debug api: set logger level: parse level: %v", err)
+		jsonhttp.BadRequest(w, "invalid level")
+		return
+	}
+
+	if err := s.Logger.SetLoggerLevel(name, level); err != nil {
+		s.Logger.Debugf("debug api: set logger level %s: %v", name, err)
+		if errors.Is(err, logging.ErrLoggerNotFound) {
+			jsonhttp.NotFound(w, "logger not found")
+			return
+		}
+		s.Logger.Errorf("debug api: set logger level %s", name)
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+
+	s.Logger.Infof("logger %s level set to %s", name, level)
+	jsonhttp.OK(w, loggerResponse{
+		Name:  name,
+		Level: level.String(),
+	})
+}
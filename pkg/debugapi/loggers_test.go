@@ -0,0 +1,69 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLoggers(t *testing.T) {
+	logger := logging.New(ioutil.Discard, logrus.InfoLevel)
+	p2p := logger.Named("p2p")
+	logger.Named("kademlia")
+
+	testServer := newTestServer(t, testServerOptions{
+		Logger: logger,
+	})
+
+	t.Run("list", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/loggers", nil, http.StatusOK, debugapi.LoggersResponse{
+			Loggers: []debugapi.LoggerResponse{
+				{Name: "kademlia", Level: "info"},
+				{Name: "p2p", Level: "info"},
+				{Name: "root", Level: "info"},
+			},
+		})
+	})
+
+	t.Run("set level", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPut, "/loggers/p2p/trace", nil, http.StatusOK, debugapi.LoggerResponse{
+			Name:  "p2p",
+			Level: "trace",
+		})
+
+		if !p2p.WithField("test", true).Logger.IsLevelEnabled(logrus.TraceLevel) {
+			t.Error("trace level not enabled")
+		}
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/loggers", nil, http.StatusOK, debugapi.LoggersResponse{
+			Loggers: []debugapi.LoggerResponse{
+				{Name: "kademlia", Level: "info"},
+				{Name: "p2p", Level: "trace"},
+				{Name: "root", Level: "info"},
+			},
+		})
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPut, "/loggers/p2p/loud", nil, http.StatusBadRequest, jsonhttp.StatusResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid level",
+		})
+	})
+
+	t.Run("logger not found", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPut, "/loggers/pusher/debug", nil, http.StatusNotFound, jsonhttp.StatusResponse{
+			Code:    http.StatusNotFound,
+			Message: "logger not found",
+		})
+	})
+}
@@ -6,21 +6,41 @@ package debugapi
 
 import (
 	"github.com/ethersphere/bee"
-	"github.com/ethersphere/bee/pkg/metrics"
+	m "github.com/ethersphere/bee/pkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+type metrics struct {
+	// all metrics fields must be exported
+	// to be able to register them
+	// using reflection
+	PanicCount prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "debugapi"
+
+	return metrics{
+		PanicCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "panic_count",
+			Help:      "Number of panics recovered in debug API handlers.",
+		}),
+	}
+}
+
 func newMetricsRegistry() (r *prometheus.Registry) {
 	r = prometheus.NewRegistry()
 
 	// register standard metrics
 	r.MustRegister(
 		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{
-			Namespace: metrics.Namespace,
+			Namespace: m.Namespace,
 		}),
 		prometheus.NewGoCollector(),
 		prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: metrics.Namespace,
+			Namespace: m.Namespace,
 			Name:      "info",
 			Help:      "Bee information.",
 			ConstLabels: prometheus.Labels{
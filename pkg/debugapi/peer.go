@@ -7,6 +7,7 @@ package debugapi
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/p2p"
@@ -87,3 +88,81 @@ func (s *server) peersHandler(w http.ResponseWriter, r *http.Request) {
 		Peers: s.P2P.Peers(),
 	})
 }
+
+// rttRecorder is implemented by pingpong services that record
+// the round trip time of the last ping to a peer.
+type rttRecorder interface {
+	RTT(address swarm.Address) (rtt time.Duration, ok bool)
+}
+
+type peerResponse struct {
+	Address     swarm.Address         `json:"address"`
+	Underlays   []multiaddr.Multiaddr `json:"underlays"`
+	Direction   p2p.Direction         `json:"direction"`
+	ConnectedAt time.Time             `json:"connectedAt"`
+	Light       bool                  `json:"light"`
+	// LastRTT is the round trip time of the last ping to the peer,
+	// empty if the peer has not been pinged.
+	LastRTT   string                           `json:"lastRtt,omitempty"`
+	Bin       uint8                            `json:"bin"`
+	Protocols map[string]protocolStatsResponse `json:"protocols"`
+	// BreakerClosedUntil is set while the node does not make new
+	// connections after too many of them have failed.
+	BreakerClosedUntil *time.Time `json:"breakerClosedUntil,omitempty"`
+}
+
+type protocolStatsResponse struct {
+	BytesSent     uint64 `json:"bytesSent"`
+	BytesReceived uint64 `json:"bytesReceived"`
+}
+
+func (s *server) peerHandler(w http.ResponseWriter, r *http.Request) {
+	addr := mux.Vars(r)["address"]
+	swarmAddr, err := swarm.ParseHexAddress(addr)
+	if err != nil {
+		s.Logger.Debugf("debug api: parse peer address %s: %v", addr, err)
+		jsonhttp.BadRequest(w, "invalid peer address")
+		return
+	}
+
+	info, err := s.P2P.Peer(swarmAddr)
+	if err != nil {
+		s.Logger.Debugf("debug api: peer %s: %v", addr, err)
+		if errors.Is(err, p2p.ErrPeerNotFound) {
+			jsonhttp.NotFound(w, "peer not found")
+			return
+		}
+		s.Logger.Errorf("debug api: peer %s", addr)
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+
+	resp := peerResponse{
+		Address:     info.Address,
+		Underlays:   info.Underlays,
+		Direction:   info.Direction,
+		ConnectedAt: info.ConnectedAt,
+		Light:       info.Light,
+		Bin:         swarm.Proximity(s.Overlay.Bytes(), info.Address.Bytes()),
+		Protocols:   make(map[string]protocolStatsResponse, len(info.Protocols)),
+	}
+	if resp.Underlays == nil {
+		resp.Underlays = []multiaddr.Multiaddr{}
+	}
+	if rtts, ok := s.Pingpong.(rttRecorder); ok {
+		if rtt, ok := rtts.RTT(info.Address); ok {
+			resp.LastRTT = rtt.String()
+		}
+	}
+	for name, stats := range info.Protocols {
+		resp.Protocols[name] = protocolStatsResponse{
+			BytesSent:     stats.BytesSent,
+			BytesReceived: stats.BytesReceived,
+		}
+	}
+	if !info.BreakerClosedUntil.IsZero() {
+		resp.BreakerClosedUntil = &info.BreakerClosedUntil
+	}
+
+	jsonhttp.OK(w, resp)
+}
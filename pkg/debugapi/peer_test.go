@@ -9,6 +9,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/bzz"
 	"github.com/ethersphere/bee/pkg/crypto"
@@ -17,6 +18,7 @@ import (
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/p2p/mock"
+	pingpongmock "github.com/ethersphere/bee/pkg/pingpong/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	topmock "github.com/ethersphere/bee/pkg/topology/mock"
 	ma "github.com/multiformats/go-multiaddr"
@@ -188,3 +190,98 @@ func TestPeer(t *testing.T) {
 		})
 	})
 }
+
+func TestPeerInfo(t *testing.T) {
+	overlay := swarm.MustParseHexAddress("c000000000000000000000000000000000000000000000000000000000000000")
+	address := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	unknownAddress := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59e")
+	errorAddress := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59a")
+	underlay := mustMultiaddr(t, "/ip4/127.0.0.1/tcp/7070/p2p/16Uiu2HAkx8ULY8cTXhdVAcMmLcH9AsTKz6uBQ7DPLKRjMLgBVYkS")
+	connectedAt := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	closedUntil := connectedAt.Add(2 * time.Minute)
+	testErr := errors.New("test error")
+
+	info := p2p.PeerInfo{
+		Peer:        p2p.Peer{Address: address},
+		Underlays:   []ma.Multiaddr{underlay},
+		Direction:   p2p.DirectionInbound,
+		ConnectedAt: connectedAt,
+		Light:       true,
+		Protocols: map[string]p2p.ProtocolStats{
+			"pushsync": {BytesSent: 4200, BytesReceived: 120},
+		},
+	}
+
+	testServer := newTestServer(t, testServerOptions{
+		Overlay: overlay,
+		P2P: mock.New(mock.WithPeerFunc(func(addr swarm.Address) (p2p.PeerInfo, error) {
+			switch {
+			case addr.Equal(address):
+				return info, nil
+			case addr.Equal(errorAddress):
+				return p2p.PeerInfo{}, testErr
+			}
+			return p2p.PeerInfo{}, p2p.ErrPeerNotFound
+		})),
+		Pingpong: pingpongmock.New(func(ctx context.Context, address swarm.Address, msgs ...string) (time.Duration, error) {
+			return 5 * time.Millisecond, nil
+		}),
+	})
+
+	want := debugapi.PeerResponse{
+		Address:     address,
+		Underlays:   []ma.Multiaddr{underlay},
+		Direction:   p2p.DirectionInbound,
+		ConnectedAt: connectedAt,
+		Light:       true,
+		Bin:         4,
+		Protocols: map[string]debugapi.ProtocolStatsResponse{
+			"pushsync": {BytesSent: 4200, BytesReceived: 120},
+		},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/peers/"+address.String(), nil, http.StatusOK, want)
+	})
+
+	t.Run("pinged", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPost, "/pingpong/"+address.String(), nil, http.StatusOK, debugapi.PingpongResponse{
+			RTT: "5ms",
+		})
+
+		want := want
+		want.LastRTT = "5ms"
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/peers/"+address.String(), nil, http.StatusOK, want)
+	})
+
+	t.Run("breaker closed", func(t *testing.T) {
+		info.BreakerClosedUntil = closedUntil
+		defer func() { info.BreakerClosedUntil = time.Time{} }()
+
+		want := want
+		want.LastRTT = "5ms"
+		want.BreakerClosedUntil = &closedUntil
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/peers/"+address.String(), nil, http.StatusOK, want)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/peers/"+unknownAddress.String(), nil, http.StatusNotFound, jsonhttp.StatusResponse{
+			Code:    http.StatusNotFound,
+			Message: "peer not found",
+		})
+	})
+
+	t.Run("invalid peer address", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/peers/invalid-address", nil, http.StatusBadRequest, jsonhttp.StatusResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid peer address",
+		})
+	})
+
+	t.Run("error", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/peers/"+errorAddress.String(), nil, http.StatusInternalServerError, jsonhttp.StatusResponse{
+			Code:    http.StatusInternalServerError,
+			Message: testErr.Error(),
+		})
+	})
+}
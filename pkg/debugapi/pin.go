@@ -22,7 +22,7 @@ func (s *server) pinChunk(w http.ResponseWriter, r *http.Request) {
 	addr, err := swarm.ParseHexAddress(mux.Vars(r)["address"])
 	if err != nil {
 		s.Logger.Debugf("debug api: pin chunk: parse chunk address: %v", err)
-		jsonhttp.BadRequest(w, "bad address")
+		jsonhttp.BadRequest(w, jsonhttp.Error(jsonhttp.ReasonInvalidAddress, "bad address"))
 		return
 	}
 
@@ -53,7 +53,7 @@ func (s *server) unpinChunk(w http.ResponseWriter, r *http.Request) {
 	addr, err := swarm.ParseHexAddress(mux.Vars(r)["address"])
 	if err != nil {
 		s.Logger.Debugf("debug api: pin chunk: parse chunk ddress: %v", err)
-		jsonhttp.BadRequest(w, "bad address")
+		jsonhttp.BadRequest(w, jsonhttp.Error(jsonhttp.ReasonInvalidAddress, "bad address"))
 		return
 	}
 
@@ -115,7 +115,7 @@ func (s *server) getPinnedChunk(w http.ResponseWriter, r *http.Request) {
 	addr, err := swarm.ParseHexAddress(mux.Vars(r)["address"])
 	if err != nil {
 		s.Logger.Debugf("debug api: pin chunk: parse chunk ddress: %v", err)
-		jsonhttp.BadRequest(w, "bad address")
+		jsonhttp.BadRequest(w, jsonhttp.Error(jsonhttp.ReasonInvalidAddress, "bad address"))
 		return
 	}
 
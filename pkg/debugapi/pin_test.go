@@ -18,13 +18,16 @@ import (
 	"github.com/ethersphere/bee/pkg/tags"
 )
 
+// pinAddress is a full length address, as required by the api spec.
+const pinAddress = "aabbcc0000000000000000000000000000000000000000000000000000000000"
+
 // TestPinChunkHandler checks for pinning, unpinning and listing of chunks.
 // It also check other edgw cases like chunk not present and checking for pinning,
 // invalid chunk address case etc. This test case has to be run in sequence and
 // it assumes some state of the DB before another case is run.
 func TestPinChunkHandler(t *testing.T) {
 	resource := func(addr swarm.Address) string { return "/chunks/" + addr.String() }
-	hash := swarm.MustParseHexAddress("aabbcc")
+	hash := swarm.MustParseHexAddress(pinAddress)
 	data := []byte("bbaatt")
 	mockValidator := validator.NewMockValidator(hash, data)
 	tag := tags.NewTags()
@@ -44,6 +47,7 @@ func TestPinChunkHandler(t *testing.T) {
 		jsonhttptest.ResponseDirect(t, debugTestServer.Client, http.MethodPost, "/chunks-pin/abcd1100zz", nil, http.StatusBadRequest, jsonhttp.StatusResponse{
 			Message: "bad address",
 			Code:    http.StatusBadRequest,
+			Reason:  jsonhttp.ReasonInvalidAddress,
 		})
 	})
 
@@ -84,7 +88,7 @@ func TestPinChunkHandler(t *testing.T) {
 
 		// Check is the chunk is pinned once
 		jsonhttptest.ResponseDirect(t, debugTestServer.Client, http.MethodGet, "/chunks-pin/"+hash.String(), nil, http.StatusOK, debugapi.PinnedChunk{
-			Address:    swarm.MustParseHexAddress("aabbcc"),
+			Address:    hash,
 			PinCounter: 1,
 		})
 
@@ -99,7 +103,7 @@ func TestPinChunkHandler(t *testing.T) {
 
 		// Check is the chunk is pinned twice
 		jsonhttptest.ResponseDirect(t, debugTestServer.Client, http.MethodGet, "/chunks-pin/"+hash.String(), nil, http.StatusOK, debugapi.PinnedChunk{
-			Address:    swarm.MustParseHexAddress("aabbcc"),
+			Address:    hash,
 			PinCounter: 2,
 		})
 	})
@@ -113,7 +117,7 @@ func TestPinChunkHandler(t *testing.T) {
 
 		// Check is the chunk is pinned once
 		jsonhttptest.ResponseDirect(t, debugTestServer.Client, http.MethodGet, "/chunks-pin/"+hash.String(), nil, http.StatusOK, debugapi.PinnedChunk{
-			Address:    swarm.MustParseHexAddress("aabbcc"),
+			Address:    hash,
 			PinCounter: 1,
 		})
 	})
@@ -161,7 +165,7 @@ func TestPinChunkHandler(t *testing.T) {
 		jsonhttptest.ResponseDirect(t, debugTestServer.Client, http.MethodGet, "/chunks-pin", nil, http.StatusOK, debugapi.ListPinnedChunksResponse{
 			Chunks: []debugapi.PinnedChunk{
 				{
-					Address:    swarm.MustParseHexAddress("aabbcc"),
+					Address:    hash,
 					PinCounter: 1,
 				},
 				{
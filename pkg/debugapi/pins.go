@@ -0,0 +1,234 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+// maxPinsLimit is the maximal number of pins in a list response.
+const maxPinsLimit = 1000
+
+// pinStore is implemented by storers with
+// named pins of trees, like the localstore.
+type pinStore interface {
+	CreatePin(p localstore.Pin) (localstore.Pin, error)
+	GetPin(name string) (localstore.Pin, error)
+	DeletePin(name string) error
+	Pins(o localstore.PinsOptions) (pins []localstore.Pin, next string, err error)
+}
+
+type pinRequest struct {
+	Name string        `json:"name"`
+	Root swarm.Address `json:"root"`
+	// Owner is the id of the request token if the request has one,
+	// and it must not be set to a different owner.
+	Owner string `json:"owner"`
+	// Expiry is the pin lifetime in seconds, zero if the pin does not expire.
+	Expiry int64 `json:"expiry"`
+}
+
+type pinResponse struct {
+	Name    string        `json:"name"`
+	Root    swarm.Address `json:"root"`
+	Owner   string        `json:"owner,omitempty"`
+	Created time.Time     `json:"created"`
+	// Expires is omitted if the pin does not expire.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+type listPinsResponse struct {
+	Pins []pinResponse `json:"pins"`
+	// Next is the after query parameter of the next page,
+	// omitted if there are no more pins.
+	Next string `json:"next,omitempty"`
+}
+
+func newPinResponse(p localstore.Pin) pinResponse {
+	r := pinResponse{
+		Name:    p.Name,
+		Root:    p.Root,
+		Owner:   p.Owner,
+		Created: p.Created,
+	}
+	if !p.Expires.IsZero() {
+		r.Expires = &p.Expires
+	}
+	return r
+}
+
+// createPinHandler pins all chunks in the tree of the root reference
+// under the pin name. All chunks of the tree have to be stored.
+func (s *server) createPinHandler(w http.ResponseWriter, r *http.Request) {
+	pins, ok := s.Storer.(pinStore)
+	if !ok {
+		s.Logger.Debug("debug api: create pin: storer does not support pins")
+		jsonhttp.NotImplemented(w, "pins not supported")
+		return
+	}
+
+	var req pinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.Logger.Debugf("debug api: create pin: decode request: %v", err)
+		jsonhttp.BadRequest(w, "invalid request")
+		return
+	}
+	if strings.Contains(req.Name, "/") {
+		jsonhttp.BadRequest(w, "invalid pin name")
+		return
+	}
+	if req.Expiry < 0 {
+		jsonhttp.BadRequest(w, "invalid expiry")
+		return
+	}
+	if token := auth.BearerToken(r); token != "" {
+		// pins of token holders are always owned by their tokens,
+		// so that they cannot create pins on behalf of others
+		owner := auth.TokenID(token)
+		if req.Owner != "" && req.Owner != owner {
+			jsonhttp.Forbidden(w, "owner does not match token")
+			return
+		}
+		req.Owner = owner
+	}
+
+	p := localstore.Pin{
+		Name:  req.Name,
+		Root:  req.Root,
+		Owner: req.Owner,
+	}
+	if req.Expiry > 0 {
+		p.Expires = time.Now().Add(time.Duration(req.Expiry) * time.Second).UTC()
+	}
+
+	p, err := pins.CreatePin(p)
+	if err != nil {
+		s.Logger.Debugf("debug api: create pin %q: %v", req.Name, err)
+		switch {
+		case errors.Is(err, localstore.ErrInvalidPin):
+			jsonhttp.BadRequest(w, "invalid pin")
+		case errors.Is(err, localstore.ErrPinExists):
+			jsonhttp.Conflict(w, "pin exists")
+		case errors.Is(err, storage.ErrNotFound):
+			jsonhttp.NotFound(w, "chunks of the root are not stored")
+		default:
+			s.Logger.Error("debug api: create pin")
+			jsonhttp.InternalServerError(w, "cannot create pin")
+		}
+		return
+	}
+	jsonhttp.Created(w, newPinResponse(p))
+}
+
+func (s *server) getPinHandler(w http.ResponseWriter, r *http.Request) {
+	pins, ok := s.Storer.(pinStore)
+	if !ok {
+		s.Logger.Debug("debug api: get pin: storer does not support pins")
+		jsonhttp.NotImplemented(w, "pins not supported")
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	p, err := pins.GetPin(name)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		s.Logger.Debugf("debug api: get pin %q: %v", name, err)
+		s.Logger.Error("debug api: get pin")
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+	jsonhttp.OK(w, newPinResponse(p))
+}
+
+// deletePinHandler removes the pin and unpins all chunks in its tree.
+func (s *server) deletePinHandler(w http.ResponseWriter, r *http.Request) {
+	pins, ok := s.Storer.(pinStore)
+	if !ok {
+		s.Logger.Debug("debug api: delete pin: storer does not support pins")
+		jsonhttp.NotImplemented(w, "pins not supported")
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	if err := pins.DeletePin(name); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		s.Logger.Debugf("debug api: delete pin %q: %v", name, err)
+		s.Logger.Error("debug api: delete pin")
+		jsonhttp.InternalServerError(w, "cannot delete pin")
+		return
+	}
+	jsonhttp.OK(w, nil)
+}
+
+// listPinsHandler lists pins ordered by their names, in pages of the limit
+// query parameter size after the pin name of the after query parameter.
+// Pins are selected by the prefix, owner and root query parameters.
+func (s *server) listPinsHandler(w http.ResponseWriter, r *http.Request) {
+	pins, ok := s.Storer.(pinStore)
+	if !ok {
+		s.Logger.Debug("debug api: list pins: storer does not support pins")
+		jsonhttp.NotImplemented(w, "pins not supported")
+		return
+	}
+
+	query := r.URL.Query()
+	o := localstore.PinsOptions{
+		After:  query.Get("after"),
+		Prefix: query.Get("prefix"),
+		Owner:  query.Get("owner"),
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxPinsLimit {
+			s.Logger.Debugf("debug api: list pins: parse limit %q: %v", v, err)
+			jsonhttp.BadRequest(w, "invalid limit")
+			return
+		}
+		o.Limit = limit
+	}
+	if v := query.Get("root"); v != "" {
+		root, err := swarm.ParseHexAddress(v)
+		if err != nil {
+			s.Logger.Debugf("debug api: list pins: parse root %q: %v", v, err)
+			jsonhttp.BadRequest(w, jsonhttp.Error(jsonhttp.ReasonInvalidAddress, "invalid root"))
+			return
+		}
+		o.Root = root
+	}
+
+	list, next, err := pins.Pins(o)
+	if err != nil {
+		s.Logger.Debugf("debug api: list pins: %v", err)
+		s.Logger.Error("debug api: list pins")
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+	resp := listPinsResponse{
+		Pins: make([]pinResponse, 0, len(list)),
+		Next: next,
+	}
+	for _, p := range list {
+		resp.Pins = append(resp.Pins, newPinResponse(p))
+	}
+	jsonhttp.OK(w, resp)
+}
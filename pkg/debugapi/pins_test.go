@@ -0,0 +1,186 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/auth"
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/splitter"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestPins(t *testing.T) {
+	db, err := localstore.New("", make([]byte, 32), nil, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	data := []byte("pinned data")
+	root, err := splitter.NewSimpleSplitter(db).Split(context.Background(), file.NewSimpleReadCloser(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testServer := newTestServer(t, testServerOptions{
+		Storer: db,
+	})
+
+	pinRequest := func(name string, root swarm.Address, expiry int64) *bytes.Reader {
+		b, err := json.Marshal(debugapi.PinRequest{Name: name, Root: root, Expiry: expiry})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bytes.NewReader(b)
+	}
+
+	var first debugapi.PinResponse
+	jsonhttptest.ResponseUnmarshal(t, testServer.Client, http.MethodPost, "/pins", pinRequest("first", root, 0), http.StatusCreated, &first)
+	if first.Name != "first" || !first.Root.Equal(root) || first.Created.IsZero() || first.Expires != nil {
+		t.Errorf("got pin %+v", first)
+	}
+
+	var second debugapi.PinResponse
+	jsonhttptest.ResponseUnmarshal(t, testServer.Client, http.MethodPost, "/pins", pinRequest("second", root, 3600), http.StatusCreated, &second)
+	if second.Expires == nil || second.Expires.Before(time.Now().Add(59*time.Minute)) {
+		t.Errorf("got pin expiry %v, want in an hour", second.Expires)
+	}
+
+	pinCounter, err := db.PinInfo(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinCounter != 2 {
+		t.Errorf("got pin counter %v, want 2", pinCounter)
+	}
+
+	t.Run("create errors", func(t *testing.T) {
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPost, "/pins", pinRequest("first", root, 0), http.StatusConflict, jsonhttp.StatusResponse{
+			Message: "pin exists",
+			Code:    http.StatusConflict,
+		})
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPost, "/pins", pinRequest("missing", swarm.MustParseHexAddress(pinAddress), 0), http.StatusNotFound, jsonhttp.StatusResponse{
+			Message: "chunks of the root are not stored",
+			Code:    http.StatusNotFound,
+		})
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPost, "/pins", pinRequest("a/b", root, 0), http.StatusBadRequest, jsonhttp.StatusResponse{
+			Message: "invalid pin name",
+			Code:    http.StatusBadRequest,
+		})
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPost, "/pins", pinRequest("", root, 0), http.StatusBadRequest, jsonhttp.StatusResponse{
+			Message: "invalid pin",
+			Code:    http.StatusBadRequest,
+		})
+	})
+
+	t.Run("owner", func(t *testing.T) {
+		const token = "token"
+		req, err := http.NewRequest(http.MethodPost, "/pins", pinRequest("owned", root, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := testServer.Client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("got response status %s", resp.Status)
+		}
+
+		var pins debugapi.ListPinsResponse
+		jsonhttptest.ResponseUnmarshal(t, testServer.Client, http.MethodGet, "/pins?owner="+auth.TokenID(token), nil, http.StatusOK, &pins)
+		if len(pins.Pins) != 1 || pins.Pins[0].Name != "owned" {
+			t.Errorf("got pins %+v, want the owned pin", pins.Pins)
+		}
+
+		b, err := json.Marshal(debugapi.PinRequest{Name: "other", Root: root, Owner: "other"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		jsonhttptest.ResponseDirectSendHeadersAndReceiveHeaders(t, testServer.Client, http.MethodPost, "/pins", bytes.NewReader(b), http.StatusForbidden, jsonhttp.StatusResponse{
+			Message: "owner does not match token",
+			Code:    http.StatusForbidden,
+		}, http.Header{"Authorization": {"Bearer " + token}})
+	})
+
+	t.Run("get", func(t *testing.T) {
+		var p debugapi.PinResponse
+		jsonhttptest.ResponseUnmarshal(t, testServer.Client, http.MethodGet, "/pins/first", nil, http.StatusOK, &p)
+		if p.Name != first.Name || !p.Root.Equal(first.Root) || !p.Created.Equal(first.Created) {
+			t.Errorf("got pin %+v, want %+v", p, first)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var pins debugapi.ListPinsResponse
+		jsonhttptest.ResponseUnmarshal(t, testServer.Client, http.MethodGet, "/pins?limit=1", nil, http.StatusOK, &pins)
+		if len(pins.Pins) != 1 || pins.Pins[0].Name != "first" || pins.Next != "first" {
+			t.Errorf("got pins %+v and next %q, want first pin and next %q", pins.Pins, pins.Next, "first")
+		}
+		var last debugapi.ListPinsResponse
+		jsonhttptest.ResponseUnmarshal(t, testServer.Client, http.MethodGet, "/pins?root="+root.String()+"&after=owned", nil, http.StatusOK, &last)
+		if len(last.Pins) != 1 || last.Pins[0].Name != "second" || last.Next != "" {
+			t.Errorf("got pins %+v and next %q, want second pin", last.Pins, last.Next)
+		}
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/pins?limit=0", nil, http.StatusBadRequest, jsonhttp.StatusResponse{
+			Message: "invalid limit",
+			Code:    http.StatusBadRequest,
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		for _, name := range []string{"first", "second", "owned"} {
+			jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodDelete, "/pins/"+name, nil, http.StatusOK, jsonhttp.StatusResponse{
+				Message: http.StatusText(http.StatusOK),
+				Code:    http.StatusOK,
+			})
+		}
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/pins/first", nil, http.StatusNotFound, jsonhttp.StatusResponse{
+			Message: http.StatusText(http.StatusNotFound),
+			Code:    http.StatusNotFound,
+		})
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodDelete, "/pins/first", nil, http.StatusNotFound, jsonhttp.StatusResponse{
+			Message: http.StatusText(http.StatusNotFound),
+			Code:    http.StatusNotFound,
+		})
+		if _, err := db.PinInfo(root); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+		}
+	})
+}
+
+func TestPinsNotSupported(t *testing.T) {
+	testServer := newTestServer(t, testServerOptions{
+		Storer: mock.NewStorer(),
+	})
+
+	jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/pins", nil, http.StatusNotImplemented, jsonhttp.StatusResponse{
+		Message: "pins not supported",
+		Code:    http.StatusNotImplemented,
+	})
+	jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodPost, "/pins", strings.NewReader("{}"), http.StatusNotImplemented, jsonhttp.StatusResponse{
+		Message: "pins not supported",
+		Code:    http.StatusNotImplemented,
+	})
+}
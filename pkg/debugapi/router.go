@@ -8,9 +8,13 @@ import (
 	"expvar"
 	"net/http"
 	"net/http/pprof"
+	"strings"
 
+	"github.com/ethersphere/bee/pkg/apispec"
+	"github.com/ethersphere/bee/pkg/auth"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -21,8 +25,14 @@ import (
 func (s *server) setupRouting() {
 	baseRouter := http.NewServeMux()
 
+	authHandler := func(h http.Handler) http.Handler { return h }
+	if s.Authenticator != nil {
+		authHandler = s.Authenticator.Middleware(s.Logger, authScope)
+	}
+
 	baseRouter.Handle("/metrics", web.ChainHandlers(
 		logging.SetAccessLogLevelHandler(0), // suppress access log messages
+		authHandler,
 		web.FinalHandler(promhttp.InstrumentMetricHandler(
 			s.metricsRegistry,
 			promhttp.HandlerFor(s.metricsRegistry, promhttp.HandlerOpts{}),
@@ -47,9 +57,13 @@ func (s *server) setupRouting() {
 	))
 	router.Handle("/readiness", web.ChainHandlers(
 		logging.SetAccessLogLevelHandler(0), // suppress access log messages
-		web.FinalHandlerFunc(s.statusHandler),
+		web.FinalHandlerFunc(s.readinessHandler),
 	))
 
+	router.Handle("/node", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.nodeHandler),
+	})
+
 	router.Handle("/pingpong/{peer-id}", jsonhttp.MethodHandler{
 		"POST": http.HandlerFunc(s.pingpongHandler),
 	})
@@ -64,11 +78,21 @@ func (s *server) setupRouting() {
 		"GET": http.HandlerFunc(s.peersHandler),
 	})
 	router.Handle("/peers/{address}", jsonhttp.MethodHandler{
+		"GET":    http.HandlerFunc(s.peerHandler),
 		"DELETE": http.HandlerFunc(s.peerDisconnectHandler),
 	})
 	router.Handle("/chunks/{address}", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.hasChunkHandler),
 	})
+	router.Handle("/chunks/{address}/info", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.chunkInfoHandler),
+	})
+	router.Handle("/localstore", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.localstoreHandler),
+	})
+	router.Handle("/localstore/gc", jsonhttp.MethodHandler{
+		"POST": http.HandlerFunc(s.collectGarbageHandler),
+	})
 	router.Handle("/chunks-pin/{address}", jsonhttp.MethodHandler{
 		"GET":    http.HandlerFunc(s.getPinnedChunk),
 		"POST":   http.HandlerFunc(s.pinChunk),
@@ -77,6 +101,14 @@ func (s *server) setupRouting() {
 	router.Handle("/chunks-pin", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.listPinnedChunks),
 	})
+	router.Handle("/pins", jsonhttp.MethodHandler{
+		"GET":  http.HandlerFunc(s.listPinsHandler),
+		"POST": http.HandlerFunc(s.createPinHandler),
+	})
+	router.Handle("/pins/{name}", jsonhttp.MethodHandler{
+		"GET":    http.HandlerFunc(s.getPinHandler),
+		"DELETE": http.HandlerFunc(s.deletePinHandler),
+	})
 	router.Handle("/tags", jsonhttp.MethodHandler{
 		"POST": http.HandlerFunc(s.createTag),
 	})
@@ -86,14 +118,55 @@ func (s *server) setupRouting() {
 	router.Handle("/topology", jsonhttp.MethodHandler{
 		"GET": http.HandlerFunc(s.topologyHandler),
 	})
+	router.Handle("/loggers", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.loggersHandler),
+	})
+	router.Handle("/loggers/{name}/{level}", jsonhttp.MethodHandler{
+		"PUT": http.HandlerFunc(s.setLoggerLevelHandler),
+	})
+
+	s.router = router
+
+	specValidationHandler := func(h http.Handler) http.Handler { return h }
+	if s.spec != nil {
+		specValidationHandler = apispec.NewValidationHandler(s.Logger, func(*http.Request) *apispec.Spec {
+			return s.spec
+		})
+	}
 
 	baseRouter.Handle("/", web.ChainHandlers(
 		logging.NewHTTPAccessLogHandler(s.Logger, logrus.InfoLevel, "debug api access"),
 		handlers.CompressHandler,
-		// todo: add recovery handler
+		s.recoveryHandler,
 		web.NoCacheHeadersHandler,
+		authHandler,
+		specValidationHandler,
 		web.FinalHandler(router),
 	))
 
 	s.Handler = baseRouter
 }
+
+// recoveryHandler responds with the Internal Server Error status if the
+// handler panics, logging the panic and counting it in metrics.
+func (s *server) recoveryHandler(h http.Handler) http.Handler {
+	return jsonhttp.NewRecoveryHandler(func(r *http.Request, v interface{}, stack []byte) {
+		s.metrics.PanicCount.Inc()
+		tracing.NewLoggerWithTraceID(r.Context(), s.Logger).Errorf("debug api: panic serving %s %s: %v\n%s", r.Method, r.URL.Path, v, stack)
+	})(h)
+}
+
+// authScope returns the scope that is required to access the resource of
+// the request. Health and readiness probes are not protected, pinning
+// requires the pin scope and all other resources, including metrics,
+// require the admin scope.
+func authScope(r *http.Request) auth.Scope {
+	switch path := r.URL.Path; {
+	case path == "/health", path == "/readiness":
+		return ""
+	case path == "/chunks-pin", strings.HasPrefix(path, "/chunks-pin/"),
+		path == "/pins", strings.HasPrefix(path, "/pins/"):
+		return auth.ScopePin
+	}
+	return auth.ScopeAdmin
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/localstore"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/gorilla/mux"
+)
+
+// TestSpecRoutes checks that all registered routes are defined in the
+// OpenAPI document and that all operations of the document are registered.
+func TestSpecRoutes(t *testing.T) {
+	s := debugapi.New(debugapi.Options{
+		Logger: logging.New(ioutil.Discard, 0),
+	})
+	spec, err := debugapi.LoadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registered := make(map[string]bool)
+	err = debugapi.Router(s).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(template, "/debug/") {
+			// pprof and expvar are not a part of the api
+			return nil
+		}
+		methods := []string{http.MethodGet}
+		if handler, ok := route.GetHandler().(jsonhttp.MethodHandler); ok {
+			methods = methods[:0]
+			for method := range handler {
+				methods = append(methods, method)
+			}
+		}
+		for _, method := range methods {
+			registered[method+" "+normalizeTemplate(template)] = true
+			if !spec.HasOperation(template, method) {
+				t.Errorf("route %s %s is not defined in the api spec", method, template)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, op := range spec.Operations() {
+		if !registered[op.Method+" "+normalizeTemplate(op.Path)] {
+			t.Errorf("api spec operation %s %s is not registered", op.Method, op.Path)
+		}
+	}
+}
+
+// TestSpecValidation checks that invalid requests are rejected
+// when the spec validation is enabled.
+func TestSpecValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		validate bool
+		code     int
+		message  string
+	}{
+		{name: "enabled", validate: true, code: http.StatusBadRequest, message: "invalid request: request body: content type text/plain: not declared"},
+		{name: "disabled", validate: false, code: http.StatusBadRequest, message: "invalid request"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := localstore.New("", make([]byte, 32), nil, logging.New(ioutil.Discard, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = db.Close() })
+
+			testServer := newTestServer(t, testServerOptions{
+				Storer:       db,
+				ValidateSpec: tc.validate,
+			})
+
+			jsonhttptest.ResponseDirectSendHeadersAndReceiveHeaders(t, testServer.Client, http.MethodPost, "/pins", strings.NewReader("pin"), tc.code, jsonhttp.StatusResponse{
+				Message: tc.message,
+				Code:    tc.code,
+			}, http.Header{"Content-Type": {"text/plain"}})
+		})
+	}
+}
+
+var templateParam = regexp.MustCompile(`{[^}]*}`)
+
+// normalizeTemplate replaces the path parameters in the template, as
+// their names differ between the routes and the api spec.
+func normalizeTemplate(template string) string {
+	return templateParam.ReplaceAllString(template, "{}")
+}
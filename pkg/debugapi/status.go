@@ -6,16 +6,136 @@ package debugapi
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/ethersphere/bee"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/multiformats/go-multiaddr"
 )
 
+const (
+	statusOK       = "ok"
+	statusNotReady = "not ready"
+)
+
+// ReadinessCheck is a condition that a node component
+// has to satisfy for the node to be ready.
+type ReadinessCheck struct {
+	// Name identifies the component, for example localstore.
+	Name string
+	// Check returns an error that describes why the
+	// component is not ready, or nil if it is ready.
+	Check func() error
+}
+
 type statusResponse struct {
+	Status     string            `json:"status"`
+	Components []componentStatus `json:"components,omitempty"`
+}
+
+type componentStatus struct {
+	Name   string `json:"name"`
 	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
 func (s *server) statusHandler(w http.ResponseWriter, r *http.Request) {
 	jsonhttp.OK(w, statusResponse{
-		Status: "ok",
+		Status: statusOK,
 	})
 }
+
+func (s *server) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	components, ready := s.checkReadiness()
+	if !ready {
+		jsonhttp.ServiceUnavailable(w, statusResponse{
+			Status:     statusNotReady,
+			Components: components,
+		})
+		return
+	}
+	jsonhttp.OK(w, statusResponse{
+		Status:     statusOK,
+		Components: components,
+	})
+}
+
+// checkReadiness runs all readiness checks and returns the status of
+// every component and whether all of them are ready.
+func (s *server) checkReadiness() (components []componentStatus, ready bool) {
+	ready = true
+	for _, c := range s.ReadinessChecks {
+		status := componentStatus{
+			Name:   c.Name,
+			Status: statusOK,
+		}
+		if err := c.Check(); err != nil {
+			status.Status = statusNotReady
+			status.Error = err.Error()
+			ready = false
+		}
+		components = append(components, status)
+	}
+	return components, ready
+}
+
+// dbUsager is implemented by storers that have a limited disk capacity.
+type dbUsager interface {
+	DiskCapacity() uint64
+	DiskUsage() (uint64, error)
+}
+
+type nodeResponse struct {
+	Overlay   swarm.Address         `json:"overlay"`
+	Underlay  []multiaddr.Multiaddr `json:"underlay"`
+	Version   string                `json:"version"`
+	Uptime    int64                 `json:"uptime"`
+	NetworkID uint64                `json:"networkID"`
+	// DBCapacity and DBUsage are the numbers of bytes on the disk,
+	// zero if the storer does not have a limited disk capacity.
+	DBCapacity uint64            `json:"dbCapacity"`
+	DBUsage    uint64            `json:"dbUsage"`
+	Status     string            `json:"status"`
+	Components []componentStatus `json:"components"`
+}
+
+func (s *server) nodeHandler(w http.ResponseWriter, r *http.Request) {
+	underlay, err := s.P2P.Addresses()
+	if err != nil {
+		s.Logger.Debugf("debug api: node: p2p addresses: %v", err)
+		s.Logger.Error("debug api: node: p2p addresses")
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+
+	resp := nodeResponse{
+		Overlay:    s.Overlay,
+		Underlay:   underlay,
+		Version:    bee.Version,
+		Uptime:     int64(time.Since(s.startTime) / time.Second),
+		NetworkID:  s.NetworkID,
+		Status:     statusOK,
+		Components: []componentStatus{},
+	}
+
+	if db, ok := s.Storer.(dbUsager); ok {
+		usage, err := db.DiskUsage()
+		if err != nil {
+			s.Logger.Debugf("debug api: node: db usage: %v", err)
+			s.Logger.Error("debug api: node: db usage")
+			jsonhttp.InternalServerError(w, err)
+			return
+		}
+		resp.DBCapacity = db.DiskCapacity()
+		resp.DBUsage = usage
+	}
+
+	components, ready := s.checkReadiness()
+	if !ready {
+		resp.Status = statusNotReady
+	}
+	resp.Components = append(resp.Components, components...)
+
+	jsonhttp.OK(w, resp)
+}
@@ -5,25 +5,145 @@
 package debugapi_test
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 
+	"github.com/ethersphere/bee"
 	"github.com/ethersphere/bee/pkg/debugapi"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/p2p/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	mockstorage "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/multiformats/go-multiaddr"
 )
 
 func TestHealth(t *testing.T) {
-	testServer := newTestServer(t, testServerOptions{})
+	testServer := newTestServer(t, testServerOptions{
+		Checks: []debugapi.ReadinessCheck{
+			{Name: "peers", Check: func() error { return errors.New("no peers") }},
+		},
+	})
 
+	// health does not depend on readiness
 	jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/health", nil, http.StatusOK, debugapi.StatusResponse{
 		Status: "ok",
 	})
 }
 
 func TestReadiness(t *testing.T) {
-	testServer := newTestServer(t, testServerOptions{})
+	t.Run("no checks", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{})
 
-	jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/readiness", nil, http.StatusOK, debugapi.StatusResponse{
-		Status: "ok",
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/readiness", nil, http.StatusOK, debugapi.StatusResponse{
+			Status: "ok",
+		})
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Checks: []debugapi.ReadinessCheck{
+				{Name: "localstore", Check: func() error { return nil }},
+				{Name: "peers", Check: func() error { return nil }},
+			},
+		})
+
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/readiness", nil, http.StatusOK, debugapi.StatusResponse{
+			Status: "ok",
+			Components: []debugapi.ComponentStatus{
+				{Name: "localstore", Status: "ok"},
+				{Name: "peers", Status: "ok"},
+			},
+		})
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Checks: []debugapi.ReadinessCheck{
+				{Name: "localstore", Check: func() error { return nil }},
+				{Name: "peers", Check: func() error { return errors.New("0 of 2 peers connected") }},
+			},
+		})
+
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/readiness", nil, http.StatusServiceUnavailable, debugapi.StatusResponse{
+			Status: "not ready",
+			Components: []debugapi.ComponentStatus{
+				{Name: "localstore", Status: "ok"},
+				{Name: "peers", Status: "not ready", Error: "0 of 2 peers connected"},
+			},
+		})
+	})
+}
+
+func TestNode(t *testing.T) {
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	underlay := mustMultiaddr(t, "/ip4/127.0.0.1/tcp/7070/p2p/16Uiu2HAkx8ULY8cTXhdVAcMmLcH9AsTKz6uBQ7DPLKRjMLgBVYkS")
+	p2ps := mock.New(mock.WithAddressesFunc(func() ([]multiaddr.Multiaddr, error) {
+		return []multiaddr.Multiaddr{underlay}, nil
+	}))
+
+	t.Run("ready", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Overlay:   overlay,
+			P2P:       p2ps,
+			NetworkID: 5,
+			Storer:    &capacityStorer{Storer: mockstorage.NewStorer(), capacity: 1000, size: 42},
+			Checks: []debugapi.ReadinessCheck{
+				{Name: "localstore", Check: func() error { return nil }},
+			},
+		})
+
+		// uptime is zero as the node has just started
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/node", nil, http.StatusOK, debugapi.NodeResponse{
+			Overlay:    overlay,
+			Underlay:   []multiaddr.Multiaddr{underlay},
+			Version:    bee.Version,
+			NetworkID:  5,
+			DBCapacity: 1000,
+			DBUsage:    42,
+			Status:     "ok",
+			Components: []debugapi.ComponentStatus{
+				{Name: "localstore", Status: "ok"},
+			},
+		})
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{
+			Overlay: overlay,
+			P2P:     p2ps,
+			Storer:  mockstorage.NewStorer(),
+			Checks: []debugapi.ReadinessCheck{
+				{Name: "kademlia", Check: func() error { return errors.New("neighborhood depth not established") }},
+			},
+		})
+
+		// the node status is reported even if the node is not ready,
+		// without the db usage of storers without limited capacity
+		jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/node", nil, http.StatusOK, debugapi.NodeResponse{
+			Overlay:  overlay,
+			Underlay: []multiaddr.Multiaddr{underlay},
+			Version:  bee.Version,
+			Status:   "not ready",
+			Components: []debugapi.ComponentStatus{
+				{Name: "kademlia", Status: "not ready", Error: "neighborhood depth not established"},
+			},
+		})
 	})
 }
+
+// capacityStorer is a storer with a limited disk capacity, like the localstore.
+type capacityStorer struct {
+	storage.Storer
+	capacity uint64
+	size     uint64
+}
+
+func (s *capacityStorer) DiskCapacity() uint64 {
+	return s.capacity
+}
+
+func (s *capacityStorer) DiskUsage() (uint64, error) {
+	return s.size, nil
+}
@@ -15,20 +15,24 @@ import (
 	topmock "github.com/ethersphere/bee/pkg/topology/mock"
 )
 
+const baseAddr = "ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c"
+
 type topologyResponse struct {
-	Topology string `json:"topology"`
+	BaseAddr string `json:"baseAddr"`
+	Depth    int    `json:"depth"`
 }
 
 func TestTopologyOK(t *testing.T) {
 	marshalFunc := func() ([]byte, error) {
-		return json.Marshal(topologyResponse{Topology: "abcd"})
+		return json.Marshal(topologyResponse{BaseAddr: baseAddr, Depth: 3})
 	}
 	testServer := newTestServer(t, testServerOptions{
 		TopologyOpts: []topmock.Option{topmock.WithMarshalJSONFunc(marshalFunc)},
 	})
 
 	jsonhttptest.ResponseDirect(t, testServer.Client, http.MethodGet, "/topology", nil, http.StatusOK, topologyResponse{
-		Topology: "abcd",
+		BaseAddr: baseAddr,
+		Depth:    3,
 	})
 }
 
@@ -0,0 +1,255 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethersphere/bee/pkg/bmtpool"
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// PipelineSplitterJob encapsulates a single splitter operation which hashes
+// data chunks concurrently, accepting blockwise writes of data whose length
+// is defined in advance.
+//
+// Every complete data chunk is handed over to a worker which hashes it with
+// a hasher from the shared bmt pool and stores it. The reference is then
+// written to its position in the parent intermediate chunk, and the worker
+// that delivers the last reference of an intermediate chunk hashes it in turn,
+// so the levels of the tree are built as soon as their children complete.
+//
+// The usage is the same as with SimpleSplitterJob. The Write call that completes
+// the data length blocks until the whole tree is hashed and returns any error
+// encountered by the workers.
+type PipelineSplitterJob struct {
+	ctx        context.Context
+	putter     storage.Putter
+	spanLength int64         // target length of data
+	length     int64         // number of bytes written to the job
+	buffer     []byte        // data of the chunk that is currently written
+	root       *treeNode     // root of the tree, nil if data fits into a single chunk
+	sem        chan struct{} // limits the number of concurrent workers
+	wg         sync.WaitGroup
+	result     []byte // root hash, set by the worker that completes the tree
+	err        error  // first error encountered by the workers
+	errMu      sync.Mutex
+}
+
+// treeNode represents an intermediate chunk of the tree.
+type treeNode struct {
+	parent    *treeNode
+	index     int         // index of this node in the parent references
+	offset    int64       // data offset of the span covered by this node
+	span      int64       // length of data covered by this node
+	childSpan int64       // maximal length of data covered by a single child
+	children  []*treeNode // intermediate children, nil for data chunks
+	refs      []byte      // references of children, indexed by child index
+	pending   int32       // number of references not yet delivered
+}
+
+func newTreeNode(parent *treeNode, index int, offset, span int64) *treeNode {
+	childSpan := int64(swarm.ChunkSize)
+	for childSpan*swarm.Branches < span {
+		childSpan *= swarm.Branches
+	}
+	count := int((span + childSpan - 1) / childSpan)
+	return &treeNode{
+		parent:    parent,
+		index:     index,
+		offset:    offset,
+		span:      span,
+		childSpan: childSpan,
+		children:  make([]*treeNode, count),
+		refs:      make([]byte, count*swarm.SectionSize),
+		pending:   int32(count),
+	}
+}
+
+// NewPipelineSplitterJob creates a new PipelineSplitterJob.
+//
+// The spanLength is the length of the data that will be written and
+// workers is the maximum number of chunks that are hashed concurrently.
+func NewPipelineSplitterJob(ctx context.Context, putter storage.Putter, spanLength int64, workers int) *PipelineSplitterJob {
+	j := &PipelineSplitterJob{
+		ctx:        ctx,
+		putter:     putter,
+		spanLength: spanLength,
+		buffer:     make([]byte, 0, swarm.ChunkSize),
+		sem:        make(chan struct{}, workers),
+	}
+	if spanLength > swarm.ChunkSize {
+		j.root = newTreeNode(nil, 0, 0, spanLength)
+	}
+	return j
+}
+
+// Write adds data to the file splitter.
+func (j *PipelineSplitterJob) Write(b []byte) (int, error) {
+	if len(b) > swarm.ChunkSize {
+		return 0, fmt.Errorf("Write must be called with a maximum of %d bytes", swarm.ChunkSize)
+	}
+	if j.length+int64(len(b)) > j.spanLength {
+		return 0, errors.New("write past span length")
+	}
+	if err := j.error(); err != nil {
+		return 0, file.NewHashError(err)
+	}
+
+	for c := 0; c < len(b); {
+		n := copy(j.buffer[len(j.buffer):swarm.ChunkSize], b[c:])
+		j.buffer = j.buffer[:len(j.buffer)+n]
+		j.length += int64(n)
+		c += n
+		if len(j.buffer) == swarm.ChunkSize || j.length == j.spanLength {
+			if err := j.dispatch(); err != nil {
+				// workers that are already running must not
+				// outlive the job after its context is canceled
+				j.wg.Wait()
+				return 0, err
+			}
+		}
+	}
+
+	if j.length == j.spanLength {
+		j.wg.Wait()
+		if err := j.error(); err != nil {
+			return 0, file.NewHashError(err)
+		}
+	}
+	return len(b), nil
+}
+
+// Sum returns the Swarm hash of the data.
+func (j *PipelineSplitterJob) Sum(b []byte) []byte {
+	if j.result == nil {
+		return make([]byte, swarm.SectionSize)
+	}
+	return j.result
+}
+
+// dispatch hands over the buffered data chunk to a worker.
+func (j *PipelineSplitterJob) dispatch() error {
+	data := j.buffer
+	j.buffer = make([]byte, 0, swarm.ChunkSize)
+
+	offset := j.length - int64(len(data))
+	parent, index := j.leafParent(offset)
+
+	select {
+	case j.sem <- struct{}{}:
+	case <-j.ctx.Done():
+		return j.ctx.Err()
+	}
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		defer func() { <-j.sem }()
+
+		ref, err := j.sum(int64(len(data)), data)
+		if err != nil {
+			j.setError(err)
+			return
+		}
+		if err := j.deliver(parent, index, ref); err != nil {
+			j.setError(err)
+		}
+	}()
+	return nil
+}
+
+// leafParent returns the intermediate chunk which references the data chunk
+// at the given offset and the index of the reference in it, creating the
+// intermediate chunks on the path from the root if needed.
+//
+// It returns a nil node if the data chunk is the root of the tree.
+func (j *PipelineSplitterJob) leafParent(offset int64) (*treeNode, int) {
+	n := j.root
+	for n != nil {
+		i := int((offset - n.offset) / n.childSpan)
+		childOffset := n.offset + int64(i)*n.childSpan
+		childSpan := n.childSpan
+		if rest := n.offset + n.span - childOffset; rest < childSpan {
+			childSpan = rest
+		}
+		// a single chunk of data at the end of the span is referenced
+		// directly, regardless of the level of the parent
+		if childSpan <= swarm.ChunkSize {
+			return n, i
+		}
+		if n.children[i] == nil {
+			n.children[i] = newTreeNode(n, i, childOffset, childSpan)
+		}
+		n = n.children[i]
+	}
+	return nil, 0
+}
+
+// deliver writes the reference to the given index of the node and hashes all
+// intermediate chunks up the tree that have received all of their references.
+func (j *PipelineSplitterJob) deliver(n *treeNode, index int, ref []byte) (err error) {
+	for n != nil {
+		copy(n.refs[index*swarm.SectionSize:], ref)
+		if atomic.AddInt32(&n.pending, -1) > 0 {
+			return nil
+		}
+		ref, err = j.sum(n.span, n.refs)
+		if err != nil {
+			return err
+		}
+		index = n.index
+		n = n.parent
+	}
+	j.result = ref
+	return nil
+}
+
+// sum calculates the bmt hash of the chunk with the given span and payload
+// and puts the chunk to the store.
+func (j *PipelineSplitterJob) sum(span int64, data []byte) ([]byte, error) {
+	hasher := bmtpool.Get()
+	defer bmtpool.Put(hasher)
+
+	err := hasher.SetSpan(span)
+	if err != nil {
+		return nil, err
+	}
+	_, err = hasher.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	ref := hasher.Sum(nil)
+
+	chunkData := make([]byte, 8+len(data))
+	binary.LittleEndian.PutUint64(chunkData, uint64(span))
+	copy(chunkData[8:], data)
+	ch := swarm.NewChunk(swarm.NewAddress(ref), chunkData)
+	_, err = j.putter.Put(j.ctx, storage.ModePutUpload, ch)
+	if err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+func (j *PipelineSplitterJob) setError(err error) {
+	j.errMu.Lock()
+	defer j.errMu.Unlock()
+	if j.err == nil {
+		j.err = err
+	}
+}
+
+func (j *PipelineSplitterJob) error() error {
+	j.errMu.Lock()
+	defer j.errMu.Unlock()
+	return j.err
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/splitter/internal"
+	test "github.com/ethersphere/bee/pkg/file/testing"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// TestPipelineSplitterJobVector verifies the pipelined file hasher results
+// of legacy test vectors and that all chunks of the tree have been stored.
+func TestPipelineSplitterJobVector(t *testing.T) {
+	for i := start; i < end; i++ {
+		dataLengthStr := strconv.Itoa(i)
+		t.Run(dataLengthStr, testPipelineSplitterJobVector)
+	}
+}
+
+func testPipelineSplitterJobVector(t *testing.T) {
+	var (
+		paramstring = strings.Split(t.Name(), "/")
+		dataIdx, _  = strconv.ParseInt(paramstring[1], 10, 0)
+		store       = newRecordingPutter()
+	)
+
+	data, expect := test.GetVector(t, int(dataIdx))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	j := internal.NewPipelineSplitterJob(ctx, store, int64(len(data)), 4)
+
+	// write in unaligned bursts to exercise buffering of partial chunks
+	writeSize := swarm.ChunkSize - 7
+	for i := 0; i < len(data); i += writeSize {
+		l := writeSize
+		if len(data)-i < writeSize {
+			l = len(data) - i
+		}
+		c, err := j.Write(data[i : i+l])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c < l {
+			t.Fatalf("short write %d", c)
+		}
+	}
+
+	actual := swarm.NewAddress(j.Sum(nil))
+	if !expect.Equal(actual) {
+		t.Fatalf("expected %v, got %v", expect, actual)
+	}
+
+	// all chunks of the tree must also be stored by the simple splitter job,
+	// which may additionally store redundant chunks for single references
+	// at the top of a balanced tree
+	simpleStore := newRecordingPutter()
+	sj := internal.NewSimpleSplitterJob(ctx, simpleStore, int64(len(data)))
+	for i := 0; i < len(data); i += swarm.ChunkSize {
+		l := swarm.ChunkSize
+		if len(data)-i < swarm.ChunkSize {
+			l = len(data) - i
+		}
+		if _, err := sj.Write(data[i : i+l]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := store.chunks[string(actual.Bytes())]; !ok {
+		t.Fatal("root chunk not stored")
+	}
+	for addr, data := range store.chunks {
+		if simpleStore.chunks[addr] != data {
+			t.Fatalf("unexpected chunk %x", addr)
+		}
+	}
+}
+
+// TestPipelineSplitterJobPutError checks that a failing store write
+// is returned by the Write call that completes the data.
+func TestPipelineSplitterJobPutError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	data := make([]byte, swarm.ChunkSize*3)
+	j := internal.NewPipelineSplitterJob(ctx, failingPutter{}, int64(len(data)), 2)
+
+	var err error
+	for i := 0; i < len(data) && err == nil; i += swarm.ChunkSize {
+		_, err = j.Write(data[i : i+swarm.ChunkSize])
+	}
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// TestPipelineSplitterJobCancel checks that the Write call which fails on
+// a canceled context returns only after the running workers are done.
+func TestPipelineSplitterJobCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	putter := &blockingPutter{release: make(chan struct{})}
+	data := make([]byte, swarm.ChunkSize*3)
+	j := internal.NewPipelineSplitterJob(ctx, putter, int64(len(data)), 1)
+
+	// the only worker blocks in the store with the first chunk
+	if _, err := j.Write(data[:swarm.ChunkSize]); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	errC := make(chan error, 1)
+	go func() {
+		_, err := j.Write(data[swarm.ChunkSize : 2*swarm.ChunkSize])
+		errC <- err
+	}()
+
+	select {
+	case <-errC:
+		t.Fatal("write returned before the worker was done")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(putter.release)
+	if err := <-errC; err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if atomic.LoadInt32(&putter.done) != 1 {
+		t.Fatal("worker is not done")
+	}
+}
+
+// blockingPutter blocks all puts until it is released.
+type blockingPutter struct {
+	release chan struct{}
+	done    int32 // number of completed puts
+}
+
+func (p *blockingPutter) Put(context.Context, storage.ModePut, ...swarm.Chunk) ([]bool, error) {
+	<-p.release
+	atomic.AddInt32(&p.done, 1)
+	return []bool{false}, nil
+}
+
+type failingPutter struct{}
+
+func (failingPutter) Put(context.Context, storage.ModePut, ...swarm.Chunk) ([]bool, error) {
+	return nil, storage.ErrInvalidChunk
+}
+
+// recordingPutter keeps data of all chunks that are put to it.
+type recordingPutter struct {
+	chunks map[string]string
+	mu     sync.Mutex
+}
+
+func newRecordingPutter() *recordingPutter {
+	return &recordingPutter{
+		chunks: make(map[string]string),
+	}
+}
+
+func (p *recordingPutter) Put(_ context.Context, _ storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	exist := make([]bool, len(chs))
+	for i, ch := range chs {
+		key := string(ch.Address().Bytes())
+		_, exist[i] = p.chunks[key]
+		p.chunks[key] = string(ch.Data())
+	}
+	return exist, nil
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/ethersphere/bee/pkg/bmtpool"
 	"github.com/ethersphere/bee/pkg/file"
 	"github.com/ethersphere/bee/pkg/file/splitter/internal"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -36,7 +37,45 @@ func NewSimpleSplitter(putter storage.Putter) file.Splitter {
 // It returns the Swarmhash of the data.
 func (s *simpleSplitter) Split(ctx context.Context, r io.ReadCloser, dataLength int64) (addr swarm.Address, err error) {
 	j := internal.NewSimpleSplitterJob(ctx, s.putter, dataLength)
+	return split(j, r, dataLength)
+}
+
+// pipelineSplitter wraps an implementation of file.Splitter that hashes
+// chunks concurrently
+type pipelineSplitter struct {
+	putter  storage.Putter
+	workers int
+}
+
+// NewPipelineSplitter creates a new pipelined Splitter
+func NewPipelineSplitter(putter storage.Putter) file.Splitter {
+	return &pipelineSplitter{
+		putter:  putter,
+		workers: bmtpool.Capacity,
+	}
+}
+
+// Split implements the file.Splitter interface
+//
+// Data chunks are hashed and stored concurrently with hashers from the shared
+// bmt pool, and intermediate chunks are hashed as soon as all of their
+// children are available. The resulting tree is the same as the one created
+// by the simple splitter.
+//
+// It returns the Swarmhash of the data.
+func (s *pipelineSplitter) Split(ctx context.Context, r io.ReadCloser, dataLength int64) (addr swarm.Address, err error) {
+	j := internal.NewPipelineSplitterJob(ctx, s.putter, dataLength, s.workers)
+	return split(j, r, dataLength)
+}
+
+// splitterJob is the file hasher component used by splitters.
+type splitterJob interface {
+	Write(b []byte) (int, error)
+	Sum(b []byte) []byte
+}
 
+// split reads all data from the reader and writes it to the splitter job.
+func split(j splitterJob, r io.Reader, dataLength int64) (swarm.Address, error) {
 	var total int64
 	data := make([]byte, swarm.ChunkSize)
 	var eof bool
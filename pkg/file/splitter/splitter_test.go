@@ -7,6 +7,7 @@ package splitter_test
 import (
 	"bytes"
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -182,3 +183,76 @@ func TestUnalignedSplit(t *testing.T) {
 	}
 
 }
+
+// TestPipelineSplitter checks that the pipelined splitter produces the same
+// address as the simple splitter for data spanning multiple tree levels.
+func TestPipelineSplitter(t *testing.T) {
+	g := mockbytes.New(0, mockbytes.MockTypeStandard).WithModulus(255)
+	for _, dataLen := range []int{0, 42, swarm.ChunkSize, swarm.ChunkSize*swarm.Branches + 32, swarm.ChunkSize*swarm.Branches*2 + swarm.ChunkSize*3} {
+		testData, err := g.SequentialBytes(dataLen)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectAddr, err := splitter.NewSimpleSplitter(mock.NewStorer()).Split(context.Background(), file.NewSimpleReadCloser(testData), int64(dataLen))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		store := mock.NewStorer()
+		addr, err := splitter.NewPipelineSplitter(store).Split(context.Background(), file.NewSimpleReadCloser(testData), int64(dataLen))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !expectAddr.Equal(addr) {
+			t.Fatalf("data length %d: expected %v, got %v", dataLen, expectAddr, addr)
+		}
+		if dataLen == 0 {
+			continue
+		}
+		if _, err := store.Get(context.Background(), storage.ModeGetRequest, addr); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSplitter(b *testing.B) {
+	for _, count := range []int{
+		1,
+		swarm.Branches,
+		swarm.Branches * 16,
+		swarm.Branches * swarm.Branches,
+	} {
+		b.Run(strconv.Itoa(count*swarm.ChunkSize), func(b *testing.B) {
+			benchmarkSplitter(b, count*swarm.ChunkSize)
+		})
+	}
+}
+
+func benchmarkSplitter(b *testing.B, dataLen int) {
+	g := mockbytes.New(0, mockbytes.MockTypeStandard).WithModulus(255)
+	data, err := g.RandomBytes(dataLen)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name        string
+		newSplitter func(storage.Putter) file.Splitter
+	}{
+		{"simple", splitter.NewSimpleSplitter},
+		{"pipeline", splitter.NewPipelineSplitter},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.SetBytes(int64(dataLen))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s := tc.newSplitter(mock.NewStorer())
+				_, err := s.Split(context.Background(), file.NewSimpleReadCloser(data), int64(dataLen))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -5,7 +5,9 @@
 package jsonhttp
 
 import (
+	"encoding/json"
 	"net/http"
+	"runtime/debug"
 
 	"resenje.org/web"
 )
@@ -13,9 +15,47 @@ import (
 type MethodHandler map[string]http.Handler
 
 func (h MethodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	web.HandleMethods(h, `{"message":"Method Not Allowed","code":405}`, DefaultContentTypeHeader, w, r)
+	web.HandleMethods(h, methodNotAllowedBody, DefaultContentTypeHeader, w, r)
 }
 
+var methodNotAllowedBody = func() string {
+	b, err := json.Marshal(StatusResponse{
+		Message: http.StatusText(http.StatusMethodNotAllowed),
+		Code:    http.StatusMethodNotAllowed,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}()
+
 func NotFoundHandler(w http.ResponseWriter, _ *http.Request) {
 	NotFound(w, nil)
 }
+
+// NewRecoveryHandler returns a middleware which recovers from panics in the
+// handlers, calls the onPanic function with the recovered value and the stack
+// trace and responds with the Internal Server Error status.
+//
+// The http.ErrAbortHandler panic is not recovered, as it is used to abort the
+// response intentionally.
+func NewRecoveryHandler(onPanic func(r *http.Request, v interface{}, stack []byte)) func(h http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				if onPanic != nil {
+					onPanic(r, v, debug.Stack())
+				}
+				InternalServerError(w, nil)
+			}()
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -82,6 +82,11 @@ func TestMethodHandler(t *testing.T) {
 			t.Errorf("got message message %q, want %q", m.Message, wantMessage)
 		}
 
+		wantReason := "method_not_allowed"
+		if m.Reason != wantReason {
+			t.Errorf("got reason %q, want %q", m.Reason, wantReason)
+		}
+
 		testContentType(t, w)
 	})
 }
@@ -114,3 +119,46 @@ func TestNotFoundHandler(t *testing.T) {
 
 	testContentType(t, w)
 }
+
+func TestRecoveryHandler(t *testing.T) {
+	var (
+		recovered interface{}
+		stack     []byte
+	)
+	h := jsonhttp.NewRecoveryHandler(func(_ *http.Request, v interface{}, s []byte) {
+		recovered = v
+		stack = s
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failure")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if recovered != "handler failure" {
+		t.Errorf("got recovered value %v, want %v", recovered, "handler failure")
+	}
+	if len(stack) == 0 {
+		t.Error("got empty stack trace")
+	}
+
+	statusCode := w.Result().StatusCode
+	if statusCode != http.StatusInternalServerError {
+		t.Errorf("got status code %d, want %d", statusCode, http.StatusInternalServerError)
+	}
+
+	var m *jsonhttp.StatusResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	want := &jsonhttp.StatusResponse{
+		Message: http.StatusText(http.StatusInternalServerError),
+		Code:    http.StatusInternalServerError,
+		Reason:  "internal_server_error",
+	}
+	if *m != *want {
+		t.Errorf("got response %+v, want %+v", m, want)
+	}
+}
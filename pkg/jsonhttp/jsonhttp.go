@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 var (
@@ -25,13 +26,75 @@ var (
 // StatusResponse is a standardized error format for specific HTTP responses.
 // Code field corresponds with HTTP status code, and Message field is a short
 // description of that code or provides more context about the reason for such
-// response.
+// response. Reason field is a machine readable identifier of the error that
+// clients can rely on, as the Message may change. If it is not set on error
+// responses, it is derived from the status code, for example "not_found".
 //
 // If response is string, error or Stringer type the string will be set as
 // value to the Message field.
 type StatusResponse struct {
 	Message string `json:"message,omitempty"`
 	Code    int    `json:"code,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Reasons of error responses that clients need to tell apart from other
+// errors with the same status code.
+const (
+	ReasonInvalidAddress  = "invalid_address"
+	ReasonNameNotFound    = "name_not_found"
+	ReasonNameNotResolved = "name_not_resolved"
+	ReasonDeniedReference = "denied_reference"
+	ReasonChunkNotFound   = "chunk_not_found"
+)
+
+// Error returns the error response with the machine readable reason and
+// the message. Its Code is set to the status code of the response by
+// Respond.
+func Error(reason, message string) StatusResponse {
+	return StatusResponse{
+		Message: message,
+		Reason:  reason,
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface, setting the Reason
+// of error responses from the status code if it is not set.
+func (r StatusResponse) MarshalJSON() ([]byte, error) {
+	// statusResponse has the same fields without the MarshalJSON method
+	type statusResponse StatusResponse
+	if r.Reason == "" && r.Code >= http.StatusBadRequest {
+		r.Reason = statusReason(r.Code)
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(EscapeHTML)
+	if err := enc.Encode(statusResponse(r)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+// statusReason returns the lowercase status text of the status code with
+// words separated by underscores, for example "request_entity_too_large".
+func statusReason(code int) string {
+	text := http.StatusText(code)
+	if text == "" {
+		return "error"
+	}
+	var words []string
+	for _, f := range strings.Fields(strings.ToLower(text)) {
+		f = strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return -1
+		}, f)
+		if f != "" {
+			words = append(words, f)
+		}
+	}
+	return strings.Join(words, "_")
 }
 
 // Respond writes a JSON-encoded body to http.ResponseWriter.
@@ -46,6 +109,11 @@ func Respond(w http.ResponseWriter, statusCode int, response interface{}) {
 		}
 	} else {
 		switch message := response.(type) {
+		case StatusResponse:
+			if message.Code == 0 {
+				message.Code = statusCode
+			}
+			response = message
 		case string:
 			response = &StatusResponse{
 				Message: message,
@@ -307,3 +375,8 @@ func GatewayTimeout(w http.ResponseWriter, response interface{}) {
 func HTTPVersionNotSupported(w http.ResponseWriter, response interface{}) {
 	Respond(w, http.StatusHTTPVersionNotSupported, response)
 }
+
+// InsufficientStorage writes a response with status code 507.
+func InsufficientStorage(w http.ResponseWriter, response interface{}) {
+	Respond(w, http.StatusInsufficientStorage, response)
+}
@@ -95,6 +95,7 @@ func TestRespond_statusResponse(t *testing.T) {
 		{code: http.StatusServiceUnavailable},
 		{code: http.StatusGatewayTimeout},
 		{code: http.StatusHTTPVersionNotSupported},
+		{code: http.StatusInsufficientStorage},
 	} {
 		w := httptest.NewRecorder()
 
@@ -282,6 +283,7 @@ func TestStandardHTTPResponds(t *testing.T) {
 		{f: jsonhttp.ServiceUnavailable, code: http.StatusServiceUnavailable},
 		{f: jsonhttp.GatewayTimeout, code: http.StatusGatewayTimeout},
 		{f: jsonhttp.HTTPVersionNotSupported, code: http.StatusHTTPVersionNotSupported},
+		{f: jsonhttp.InsufficientStorage, code: http.StatusInsufficientStorage},
 	} {
 		w := httptest.NewRecorder()
 		tc.f(w, nil)
@@ -303,6 +305,38 @@ func TestStandardHTTPResponds(t *testing.T) {
 	}
 }
 
+func TestRespond_reason(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		code     int
+		response interface{}
+		want     string
+	}{
+		{name: "success", code: http.StatusOK, want: ""},
+		{name: "client error", code: http.StatusRequestEntityTooLarge, want: "request_entity_too_large"},
+		{name: "server error", code: http.StatusInternalServerError, response: "failure", want: "internal_server_error"},
+		{name: "teapot", code: http.StatusTeapot, want: "im_a_teapot"},
+		{name: "custom", code: http.StatusNotFound, response: jsonhttp.StatusResponse{Code: http.StatusNotFound, Reason: "name_not_found"}, want: "name_not_found"},
+		{name: "error", code: http.StatusNotFound, response: jsonhttp.Error(jsonhttp.ReasonNameNotFound, "name not found"), want: jsonhttp.ReasonNameNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			jsonhttp.Respond(w, tc.code, tc.response)
+
+			var m *jsonhttp.StatusResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &m); err != nil {
+				t.Fatal(err)
+			}
+			if m.Reason != tc.want {
+				t.Errorf("got reason %q, want %q", m.Reason, tc.want)
+			}
+			if m.Code != tc.code {
+				t.Errorf("got code %v, want %v", m.Code, tc.code)
+			}
+		})
+	}
+}
+
 func TestPanicRespond(t *testing.T) {
 	w := httptest.NewRecorder()
 
@@ -7,6 +7,7 @@ package jsonhttptest
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,6 +16,9 @@ import (
 	"net/textproto"
 	"strconv"
 	"testing"
+
+	"github.com/ethersphere/bee/pkg/apispec"
+	"resenje.org/web"
 )
 
 func ResponseDirect(t *testing.T, client *http.Client, method, url string, body io.Reader, responseCode int, response interface{}) {
@@ -165,3 +169,35 @@ func request(t *testing.T, client *http.Client, method, url string, body io.Read
 	}
 	return resp
 }
+
+// WithSpecValidation returns a client which validates responses to requests
+// that match operations of the OpenAPI document and reports responses that
+// do not conform to it as test errors. Properties of JSON responses that are
+// not declared in the document are reported too. Requests are not validated,
+// as tests may send invalid requests on purpose.
+func WithSpecValidation(t *testing.T, client *http.Client, spec *apispec.Spec) *http.Client {
+	spec = spec.Strict()
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := *client
+	c.Transport = web.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		resp, err := transport.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		if err := spec.ValidateResponse(r, resp.StatusCode, resp.Header, body); err != nil && !errors.Is(err, apispec.ErrOperationNotFound) {
+			t.Errorf("%s %s: response does not conform to the api spec: %v", r.Method, r.URL.Path, err)
+		}
+		return resp, nil
+	})
+	return &c
+}
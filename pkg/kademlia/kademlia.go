@@ -526,6 +526,12 @@ func (k *Kad) NeighborhoodDepth() uint8 {
 	return k.neighborhoodDepth()
 }
 
+// DepthEstablished reports whether enough peers are connected for the
+// neighborhood depth to be calculated. Until then the depth is zero.
+func (k *Kad) DepthEstablished() bool {
+	return k.connectedPeers.Length() > nnLowWatermark
+}
+
 func (k *Kad) neighborhoodDepth() uint8 {
 	return k.depth
 }
@@ -65,10 +65,16 @@ func TestNeighborhoodDepth(t *testing.T) {
 
 	// check empty kademlia depth is 0
 	kDepth(t, kad, 0)
+	if kad.DepthEstablished() {
+		t.Fatal("depth established without peers")
+	}
 
 	// add two bin 8 peers, verify depth still 0
 	add(t, signer, kad, ab, binEight, 0, 2)
 	kDepth(t, kad, 0)
+	if kad.DepthEstablished() {
+		t.Fatal("depth established with nnLowWatermark peers")
+	}
 
 	// add two first peers (po0,po1)
 	add(t, signer, kad, ab, peers, 0, 2)
@@ -78,6 +84,9 @@ func TestNeighborhoodDepth(t *testing.T) {
 
 	// depth 2 (shallowest empty bin)
 	kDepth(t, kad, 2)
+	if !kad.DepthEstablished() {
+		t.Fatal("depth not established")
+	}
 
 	for i := 2; i < len(peers)-1; i++ {
 		addOne(t, signer, kad, ab, peers[i])
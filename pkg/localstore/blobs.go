@@ -0,0 +1,200 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/blobstore"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	// blobsDir is the directory of the chunk data
+	// store in the database directory.
+	blobsDir = "blobs"
+	// maxChunkDataSize is the size of the chunk payload with its span.
+	maxChunkDataSize = swarm.ChunkSize + 8
+)
+
+// newRetrievalDataIndex returns the index of chunk store timestamps,
+// bin ids and locations of chunk data in the blob store.
+func (db *DB) newRetrievalDataIndex() (shed.Index, error) {
+	return db.shed.NewIndex("Address->StoreTimestamp|BinID|Location", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			return fields.Address, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.Address = key
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			b := make([]byte, 16)
+			binary.BigEndian.PutUint64(b[:8], fields.BinID)
+			binary.BigEndian.PutUint64(b[8:16], uint64(fields.StoreTimestamp))
+			value = append(b, fields.Location...)
+			return value, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			e.StoreTimestamp = int64(binary.BigEndian.Uint64(value[8:16]))
+			e.BinID = binary.BigEndian.Uint64(value[:8])
+			e.Location = value[16:]
+			return e, nil
+		},
+	})
+}
+
+// writeData stores the item data in the blob store and sets the item
+// location. The location is added to the written slice, so that it can be
+// released if the batch with the item is not written.
+func (db *DB) writeData(item shed.Item, written *[]blobstore.Location) (shed.Item, error) {
+	loc, err := db.blobs.Write(item.Data)
+	if err != nil {
+		return item, fmt.Errorf("write chunk data: %w", err)
+	}
+	*written = append(*written, loc)
+	item.Location = loc.Bytes()
+	return item, nil
+}
+
+// readData sets the item data from the blob store. It returns
+// leveldb.ErrNotFound if the chunk was removed after the item was read
+// from the retrieval data index, as it is for chunks not in the index.
+func (db *DB) readData(item shed.Item) (shed.Item, error) {
+	loc, err := blobstore.ParseLocation(item.Location)
+	if err != nil {
+		return item, err
+	}
+	item.Data, err = db.blobs.Read(loc)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return item, leveldb.ErrNotFound
+		}
+		return item, err
+	}
+	return item, nil
+}
+
+// chunksRemoved frees chunk data slots of removed items and calls the
+// remove hook. It must be called after the batch that removes items is
+// written, with the batchMu lock held.
+func (db *DB) chunksRemoved(items ...shed.Item) {
+	if len(items) == 0 {
+		return
+	}
+	db.releaseData(items...)
+	if db.removeHook != nil {
+		addrs := make([]swarm.Address, len(items))
+		for i, item := range items {
+			addrs[i] = swarm.NewAddress(item.Address)
+		}
+		db.removeHook(addrs...)
+	}
+}
+
+// releaseData frees chunk data slots of removed items for new chunks.
+func (db *DB) releaseData(items ...shed.Item) {
+	for _, item := range items {
+		loc, err := blobstore.ParseLocation(item.Location)
+		if err != nil {
+			db.logger.Debugf("localstore: release chunk %s data: %v", swarm.NewAddress(item.Address), err)
+			continue
+		}
+		db.blobs.Release(loc)
+	}
+}
+
+// releaseLocations frees slots of data written for a batch that failed.
+func (db *DB) releaseLocations(locs []blobstore.Location) {
+	for _, loc := range locs {
+		db.blobs.Release(loc)
+	}
+}
+
+// recoverBlobs marks the locations of all chunks as used if the blob store
+// does not know its free slots because the database was not closed.
+func (db *DB) recoverBlobs() error {
+	if !db.blobs.Recovering() {
+		return nil
+	}
+	db.logger.Info("localstore: database was not closed properly, recovering free chunk data slots")
+	var count int
+	err := db.retrievalDataIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		loc, err := blobstore.ParseLocation(item.Location)
+		if err != nil {
+			return true, fmt.Errorf("chunk %s: %w", swarm.NewAddress(item.Address), err)
+		}
+		db.blobs.MarkUsed(loc)
+		count++
+		return false, nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+	db.blobs.Recovered()
+	db.logger.Infof("localstore: recovered chunk data slots of %d chunks", count)
+	return nil
+}
+
+// migrateBlobstore moves chunk data from the leveldb retrieval
+// data index to the blob store.
+func migrateBlobstore(db *DB) error {
+	oldIndex, err := db.shed.NewIndex("Address->StoreTimestamp|BinID|Data", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			return fields.Address, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.Address = key
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			b := make([]byte, 16)
+			binary.BigEndian.PutUint64(b[:8], fields.BinID)
+			binary.BigEndian.PutUint64(b[8:16], uint64(fields.StoreTimestamp))
+			value = append(b, fields.Data...)
+			return value, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			e.StoreTimestamp = int64(binary.BigEndian.Uint64(value[8:16]))
+			e.BinID = binary.BigEndian.Uint64(value[:8])
+			e.Data = value[16:]
+			return e, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	newIndex, err := db.newRetrievalDataIndex()
+	if err != nil {
+		return err
+	}
+
+	db.logger.Info("localstore migration: moving chunk data to the blob store")
+	var count int
+	// the chunk is moved to the new index in the same batch as it is
+	// deleted from the old one, so the migration can be interrupted,
+	// and its data is synced before the batch, so that it is not lost
+	err = inBatches(db, oldIndex, func(batch *leveldb.Batch, item shed.Item) error {
+		var written []blobstore.Location
+		item, err := db.writeData(item, &written)
+		if err != nil {
+			return err
+		}
+		if err := oldIndex.DeleteInBatch(batch, item); err != nil {
+			return err
+		}
+		count++
+		return newIndex.PutInBatch(batch, item)
+	}, db.blobs.Sync)
+	if err != nil {
+		return err
+	}
+	db.logger.Infof("localstore migration: moved data of %d chunks to the blob store", count)
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// TestMigrateBlobstore validates that chunk data stored in leveldb
+// by the previous schema is moved to the blob store.
+func TestMigrateBlobstore(t *testing.T) {
+	defer func(s int) { migrationBatchSize = s }(migrationBatchSize)
+	migrationBatchSize = 3
+
+	dir, err := ioutil.TempDir("", "localstore-blobstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	chunks := generateTestRandomChunks(10)
+
+	// store chunks as the previous schema did
+	s, err := shed.NewDB(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemaName, err := s.NewStringField("schema-name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schemaName.Put(DbSchemaCode); err != nil {
+		t.Fatal(err)
+	}
+	oldIndex, err := s.NewIndex("Address->StoreTimestamp|BinID|Data", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			return fields.Address, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.Address = key
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			b := make([]byte, 16)
+			binary.BigEndian.PutUint64(b[:8], fields.BinID)
+			binary.BigEndian.PutUint64(b[8:16], uint64(fields.StoreTimestamp))
+			return append(b, fields.Data...), nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			return e, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ch := range chunks {
+		err := oldIndex.Put(shed.Item{
+			Address:        ch.Address().Bytes(),
+			Data:           ch.Data(),
+			BinID:          uint64(i + 1),
+			StoreTimestamp: int64(i + 1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := New(dir, make([]byte, 32), nil, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	name, err := db.schemaName.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != DbSchemaBlobstore {
+		t.Errorf("got schema %q, want %q", name, DbSchemaBlobstore)
+	}
+
+	t.Run("retrieval data index count", newItemsCountTest(db.retrievalDataIndex, len(chunks)))
+
+	for i, ch := range chunks {
+		newRetrieveIndexesTest(db, ch, int64(i+1), 0)(t)
+
+		got, err := db.Get(context.Background(), storage.ModeGetLookup, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(ch) {
+			t.Errorf("chunk %v: got %s, want %s", i, got.Address(), ch.Address())
+		}
+	}
+}
+
+// TestDB_blobsRecovery validates that slots of removed chunks are
+// reused without overwriting stored chunks when free slots of the
+// blob store are recovered.
+func TestDB_blobsRecovery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localstore-blobstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	baseKey := make([]byte, 32)
+	logger := logging.New(ioutil.Discard, 0)
+
+	db, err := New(dir, baseKey, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := generateTestRandomChunks(100)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Set(context.Background(), storage.ModeSetRemove, chunkAddresses(chunks[:50])...); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// free slots are not known if the database is not closed
+	if err := os.Remove(filepath.Join(dir, blobsDir, "free_slots")); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = New(dir, baseKey, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if db.blobs.Recovering() {
+		t.Fatal("blob store is recovering")
+	}
+
+	newChunks := generateTestRandomChunks(50)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, newChunks...); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ch := range append(chunks[50:], newChunks...) {
+		got, err := db.Get(context.Background(), storage.ModeGetLookup, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(ch) {
+			t.Errorf("got chunk %s, want %s", got.Address(), ch.Address())
+		}
+	}
+
+	// removed chunk slots are reused
+	size, err := db.blobs.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max := int64(len(chunks) * (8 + maxChunkDataSize)); size > max {
+		t.Errorf("got blob store size %v, want at most %v", size, max)
+	}
+}
+
+// TestDB_removedChunkData validates that chunks are not
+// returned with the data of other chunks in reused slots.
+func TestDB_removedChunkData(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	ch := generateTestRandomChunk()
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+	item, err := db.retrievalDataIndex.Get(addressToItem(ch.Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Set(context.Background(), storage.ModeSetRemove, ch.Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	// write to every shard, so that the released slot is reused
+	for i := 0; i < 32; i++ {
+		if _, err := db.Put(context.Background(), storage.ModePutUpload, generateTestRandomChunk()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the location read before the chunk is removed
+	_, err = db.readData(item)
+	if err == nil {
+		t.Fatalf("got data of chunk %s from reused slot", swarm.NewAddress(item.Address))
+	}
+}
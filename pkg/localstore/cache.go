@@ -0,0 +1,299 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	// cacheFlushInterval is the default interval
+	// of writing cached chunk accesses to the gc index.
+	cacheFlushInterval = time.Second
+	// cacheFlushSize is the number of accessed chunks
+	// that triggers writing accesses before the interval.
+	cacheFlushSize = 1000
+)
+
+// Cache keeps the most recently requested chunks in memory in front of the
+// database. Only ModeGetRequest and ModeGetLookup are served from memory,
+// all other modes and methods are passed to the database. Accesses of
+// cached chunks are coalesced and written to the gc index in batches.
+type Cache struct {
+	*DB
+
+	capacity int
+
+	mu    sync.Mutex
+	items map[string]*list.Element
+	// least recently used items are at the back
+	lru *list.List
+	// chunks requested from the cache since the last flush
+	accessed map[string]shed.Item
+	// incremented on every chunk removal to prevent caching
+	// chunks that were removed while they were read
+	removals uint64
+
+	flushInterval time.Duration
+	flushTrigger  chan struct{}
+	quit          chan struct{}
+	flushDone     chan struct{}
+}
+
+// CacheOptions holds optional parameters for configuring Cache.
+type CacheOptions struct {
+	// Capacity is the maximal number of chunks in the cache.
+	Capacity uint64
+	// FlushInterval is the maximal time before chunk accesses
+	// are written to the gc index.
+	FlushInterval time.Duration
+}
+
+// NewCache returns a cache in front of the database. Closing the
+// cache writes pending chunk accesses and closes the database.
+func NewCache(db *DB, o CacheOptions) *Cache {
+	c := &Cache{
+		DB:            db,
+		capacity:      int(o.Capacity),
+		items:         make(map[string]*list.Element),
+		lru:           list.New(),
+		accessed:      make(map[string]shed.Item),
+		flushInterval: o.FlushInterval,
+		flushTrigger:  make(chan struct{}, 1),
+		quit:          make(chan struct{}),
+		flushDone:     make(chan struct{}),
+	}
+	if c.flushInterval <= 0 {
+		c.flushInterval = cacheFlushInterval
+	}
+
+	db.batchMu.Lock()
+	db.removeHook = c.remove
+	db.batchMu.Unlock()
+
+	go c.flushWorker()
+	return c
+}
+
+// Get returns a chunk from the cache or the database. Chunks
+// returned by the database are added to the cache. Gets are counted
+// and timed in the same metrics as database gets, also on cache hits.
+func (c *Cache) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (ch swarm.Chunk, err error) {
+	if mode != storage.ModeGetRequest && mode != storage.ModeGetLookup {
+		return c.DB.Get(ctx, mode, addr)
+	}
+
+	c.metrics.ModeGet.Inc()
+	defer totalTimeMetric(c.metrics.TotalTimeGet, time.Now())
+
+	defer func() {
+		if err != nil {
+			c.metrics.ModeGetFailure.Inc()
+		}
+	}()
+
+	if item, ok := c.get(addr, mode == storage.ModeGetRequest); ok {
+		return itemToChunk(item), nil
+	}
+
+	removals := c.removalCount()
+	item, err := c.DB.get(mode, addr)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	c.add(removals, item)
+	return itemToChunk(item), nil
+}
+
+// GetMulti returns chunks from the cache or the database. Chunks
+// returned by the database are added to the cache. Gets are counted
+// and timed in the same metrics as database gets, also on cache hits.
+func (c *Cache) GetMulti(ctx context.Context, mode storage.ModeGet, addrs ...swarm.Address) (chunks []swarm.Chunk, err error) {
+	if mode != storage.ModeGetRequest && mode != storage.ModeGetLookup {
+		return c.DB.GetMulti(ctx, mode, addrs...)
+	}
+
+	c.metrics.ModeGetMulti.Inc()
+	defer totalTimeMetric(c.metrics.TotalTimeGetMulti, time.Now())
+
+	defer func() {
+		if err != nil {
+			c.metrics.ModeGetMultiFailure.Inc()
+		}
+	}()
+
+	chunks = make([]swarm.Chunk, len(addrs))
+	var missing []swarm.Address
+	var missingIndexes []int
+	for i, addr := range addrs {
+		if item, ok := c.get(addr, mode == storage.ModeGetRequest); ok {
+			chunks[i] = itemToChunk(item)
+			continue
+		}
+		missing = append(missing, addr)
+		missingIndexes = append(missingIndexes, i)
+	}
+	if len(missing) == 0 {
+		return chunks, nil
+	}
+
+	removals := c.removalCount()
+	items, err := c.DB.getMulti(mode, missing...)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	c.add(removals, items...)
+	for i, item := range items {
+		chunks[missingIndexes[i]] = itemToChunk(item)
+	}
+	return chunks, nil
+}
+
+// Close writes pending chunk accesses to the gc index
+// and closes the database.
+func (c *Cache) Close() error {
+	close(c.quit)
+	<-c.flushDone
+	return c.DB.Close()
+}
+
+// get returns a cached item and records its access if access is true.
+func (c *Cache) get(addr swarm.Address, access bool) (item shed.Item, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[string(addr.Bytes())]
+	if !ok {
+		c.metrics.CacheMiss.Inc()
+		return item, false
+	}
+	c.metrics.CacheHit.Inc()
+	c.lru.MoveToFront(e)
+	item = e.Value.(shed.Item)
+
+	if access {
+		c.accessed[string(item.Address)] = item
+		if len(c.accessed) >= cacheFlushSize {
+			select {
+			case c.flushTrigger <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return item, true
+}
+
+// add caches items read from the database, evicting the least recently
+// used ones. Items are not added if any chunk was removed since the
+// removals count was read before the items.
+func (c *Cache) add(removals uint64, items ...shed.Item) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.removals != removals {
+		return
+	}
+	for _, item := range items {
+		key := string(item.Address)
+		if e, ok := c.items[key]; ok {
+			c.lru.MoveToFront(e)
+			continue
+		}
+		c.items[key] = c.lru.PushFront(item)
+		if c.lru.Len() > c.capacity {
+			e := c.lru.Back()
+			c.lru.Remove(e)
+			delete(c.items, string(e.Value.(shed.Item).Address))
+		}
+	}
+	c.metrics.CacheSize.Set(float64(c.lru.Len()))
+}
+
+// remove is called by the database when chunks are removed.
+func (c *Cache) remove(addrs ...swarm.Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removals++
+	for _, addr := range addrs {
+		key := string(addr.Bytes())
+		if e, ok := c.items[key]; ok {
+			c.lru.Remove(e)
+			delete(c.items, key)
+		}
+		delete(c.accessed, key)
+	}
+	c.metrics.CacheSize.Set(float64(c.lru.Len()))
+}
+
+func (c *Cache) removalCount() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.removals
+}
+
+// flushWorker writes chunk accesses to the gc index periodically or when
+// there are enough of them, until the cache is closed.
+func (c *Cache) flushWorker() {
+	defer close(c.flushDone)
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.flushTrigger:
+		case <-c.quit:
+			c.flush()
+			return
+		}
+		c.flush()
+	}
+}
+
+// flush writes all recorded chunk accesses to the gc index in one batch.
+func (c *Cache) flush() {
+	c.mu.Lock()
+	accessed := c.accessed
+	c.accessed = make(map[string]shed.Item)
+	c.mu.Unlock()
+
+	if len(accessed) == 0 {
+		return
+	}
+	items := make([]shed.Item, 0, len(accessed))
+	for _, item := range accessed {
+		items = append(items, item)
+	}
+
+	c.metrics.CacheAccessFlush.Inc()
+	if err := c.DB.updateGC(items...); err != nil {
+		c.metrics.CacheAccessFlushError.Inc()
+		c.logger.Debugf("localstore cache: flush %d accesses: %v", len(items), err)
+		c.logger.Error("localstore cache: flush accesses")
+	}
+}
+
+// itemToChunk returns a chunk of the item read from the retrieval index.
+func itemToChunk(item shed.Item) swarm.Chunk {
+	return swarm.NewChunk(swarm.NewAddress(item.Address), item.Data).WithPinCounter(item.PinCounter)
+}
@@ -0,0 +1,247 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// TestCache_Get validates that requested chunks are cached with the
+// least recently used evicted and that their accesses are written
+// to the gc index on flush.
+func TestCache_Get(t *testing.T) {
+	var timestamp int64 = 1
+	defer setNow(func() int64 {
+		return timestamp
+	})()
+
+	c := newTestCache(t, 3)
+
+	chunks := putSyncedChunks(t, c.DB, 5)
+
+	for _, ch := range chunks {
+		got, err := c.Get(context.Background(), storage.ModeGetRequest, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(ch) {
+			t.Fatalf("got chunk %s, want %s", got.Address(), ch.Address())
+		}
+	}
+	// wait for the gc updates of cache misses
+	c.DB.updateGCWG.Wait()
+
+	if got := c.lru.Len(); got != 3 {
+		t.Fatalf("got %v cached chunks, want 3", got)
+	}
+	for i, ch := range chunks {
+		_, cached := c.items[string(ch.Address().Bytes())]
+		if want := i >= 2; cached != want {
+			t.Errorf("chunk %v: got cached %v, want %v", i, cached, want)
+		}
+	}
+
+	timestamp = 2
+	for _, ch := range chunks[2:] {
+		got, err := c.Get(context.Background(), storage.ModeGetRequest, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(ch) {
+			t.Fatalf("got chunk %s, want %s", got.Address(), ch.Address())
+		}
+	}
+
+	// accesses of cache hits are written only on flush
+	for _, ch := range chunks[2:] {
+		testAccessTimestamp(t, c.DB, ch.Address(), 1)
+	}
+	c.flush()
+	for _, ch := range chunks[2:] {
+		testAccessTimestamp(t, c.DB, ch.Address(), 2)
+	}
+
+	t.Run("gc index count", newItemsCountTest(c.gcIndex, 5))
+
+	t.Run("gc size", newIndexGCSizeTest(c.DB))
+}
+
+// TestCache_GetMulti validates that GetMulti returns
+// chunks both from the cache and the database.
+func TestCache_GetMulti(t *testing.T) {
+	c := newTestCache(t, 10)
+
+	chunks := putSyncedChunks(t, c.DB, 4)
+
+	if _, err := c.Get(context.Background(), storage.ModeGetLookup, chunks[1].Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.GetMulti(context.Background(), storage.ModeGetLookup, chunkAddresses(chunks)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ch := range chunks {
+		if !got[i].Equal(ch) {
+			t.Errorf("chunk %v: got %s, want %s", i, got[i].Address(), ch.Address())
+		}
+	}
+	if l := c.lru.Len(); l != 4 {
+		t.Errorf("got %v cached chunks, want 4", l)
+	}
+
+	_, err = c.GetMulti(context.Background(), storage.ModeGetLookup, chunks[0].Address(), generateTestRandomChunk().Address())
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+// TestCache_syncModes validates that chunks
+// read for syncing are not cached.
+func TestCache_syncModes(t *testing.T) {
+	c := newTestCache(t, 10)
+
+	ch := generateTestRandomChunk()
+	if _, err := c.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []storage.ModeGet{storage.ModeGetSync, storage.ModeGetPin} {
+		if mode == storage.ModeGetPin {
+			if err := c.Set(context.Background(), storage.ModeSetPin, ch.Address()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := c.Get(context.Background(), mode, ch.Address()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if l := c.lru.Len(); l != 0 {
+		t.Errorf("got %v cached chunks, want 0", l)
+	}
+}
+
+// TestCache_remove validates that chunks removed from the
+// database by garbage collection or ModeSetRemove are not
+// served from the cache.
+func TestCache_remove(t *testing.T) {
+	var timestamp int64
+	defer setNow(func() int64 {
+		timestamp++
+		return timestamp
+	})()
+
+	c := newTestCache(t, 200)
+
+	chunks := putSyncedChunks(t, c.DB, 95)
+	for _, ch := range chunks {
+		if _, err := c.Get(context.Background(), storage.ModeGetLookup, ch.Address()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.Set(context.Background(), storage.ModeSetRemove, chunks[0].Address()); err != nil {
+		t.Fatal(err)
+	}
+	testCacheNotFound(t, c, chunks[0].Address())
+
+	// leaves 90 chunks of the capacity 100
+	collected, err := c.CollectGarbage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collected != 4 {
+		t.Fatalf("got %v collected chunks, want 4", collected)
+	}
+	for _, ch := range chunks[1:5] {
+		testCacheNotFound(t, c, ch.Address())
+	}
+	if l := c.lru.Len(); l != 90 {
+		t.Errorf("got %v cached chunks, want 90", l)
+	}
+}
+
+// TestCache_Close validates that pending
+// accesses are written when the cache is closed.
+func TestCache_Close(t *testing.T) {
+	var timestamp int64 = 1
+	defer setNow(func() int64 {
+		return timestamp
+	})()
+
+	db := newTestDB(t, nil)
+	c := NewCache(db, CacheOptions{Capacity: 10, FlushInterval: time.Hour})
+
+	ch := putSyncedChunks(t, db, 1)[0]
+	if _, err := c.Get(context.Background(), storage.ModeGetRequest, ch.Address()); err != nil {
+		t.Fatal(err)
+	}
+	db.updateGCWG.Wait()
+
+	timestamp = 2
+	if _, err := c.Get(context.Background(), storage.ModeGetRequest, ch.Address()); err != nil {
+		t.Fatal(err)
+	}
+	testAccessTimestamp(t, db, ch.Address(), 1)
+
+	// stop the flush worker as Close does, without
+	// closing the database that is closed on cleanup
+	close(c.quit)
+	<-c.flushDone
+
+	testAccessTimestamp(t, db, ch.Address(), 2)
+}
+
+// newTestCache returns a cache in front of a new test database
+// that is closed at the end of the test.
+func newTestCache(t *testing.T, capacity uint64) *Cache {
+	t.Helper()
+
+	db, err := New("", make([]byte, 32), &Options{Capacity: 100}, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCache(db, CacheOptions{
+		Capacity:      capacity,
+		FlushInterval: time.Hour,
+	})
+	t.Cleanup(func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return c
+}
+
+// testAccessTimestamp validates the access timestamp of the chunk.
+func testAccessTimestamp(t *testing.T, db *DB, addr swarm.Address, want int64) {
+	t.Helper()
+
+	item, err := db.retrievalAccessIndex.Get(addressToItem(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.AccessTimestamp != want {
+		t.Errorf("chunk %s: got access timestamp %v, want %v", addr, item.AccessTimestamp, want)
+	}
+}
+
+// testCacheNotFound validates that the chunk is not returned by the cache.
+func testCacheNotFound(t *testing.T, c *Cache, addr swarm.Address) {
+	t.Helper()
+
+	_, err := c.Get(context.Background(), storage.ModeGetLookup, addr)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("chunk %s: got error %v, want %v", addr, err, storage.ErrNotFound)
+	}
+}
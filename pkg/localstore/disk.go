@@ -0,0 +1,174 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var (
+	// diskCheckInterval is the period of disk usage
+	// and free disk space checks.
+	diskCheckInterval = time.Minute
+	// freeDiskSpace returns the number of bytes available on the file
+	// system with the path, it is replaced in tests.
+	freeDiskSpace = diskFree
+	// errDiskFreeUnsupported is returned by diskFree on
+	// platforms where the free disk space is not measured.
+	errDiskFreeUnsupported = errors.New("free disk space not supported")
+)
+
+// diskWatchdog is a long running function that periodically checks
+// the disk usage and the free disk space until the database is closed.
+func (db *DB) diskWatchdog() {
+	defer close(db.diskWatchdogDone)
+
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		db.checkDisk()
+
+		select {
+		case <-ticker.C:
+		case <-db.close:
+			return
+		}
+	}
+}
+
+// checkDisk updates disk metrics and checks the disk usage against the
+// disk capacity and the free disk space against the minimum.
+func (db *DB) checkDisk() {
+	usage, err := db.DiskUsage()
+	if err != nil {
+		db.logger.Debugf("localstore: disk usage: %v", err)
+		db.logger.Error("localstore: disk usage")
+	} else {
+		db.metrics.DiskUsage.Set(float64(usage))
+		db.checkDiskCapacity(usage)
+	}
+
+	db.checkFreeDiskSpace()
+}
+
+// checkDiskCapacity lowers the garbage collection target under the current
+// gc size if the disk usage exceeds the disk capacity, so that the garbage
+// collection removes chunks that take at least the excess of the disk usage
+// over the gc target ratio of the disk capacity.
+func (db *DB) checkDiskCapacity(usage uint64) {
+	if db.diskCapacity == 0 {
+		return
+	}
+
+	db.batchMu.Lock()
+	defer db.batchMu.Unlock()
+
+	if usage <= db.diskCapacity {
+		if db.overCapacity {
+			db.overCapacity = false
+			db.logger.Infof("localstore: disk usage is %d bytes, within the capacity", usage)
+		}
+		return
+	}
+
+	gcSize, err := db.gcSize.Get()
+	if err != nil {
+		db.logger.Debugf("localstore: disk capacity: gc size: %v", err)
+		db.logger.Error("localstore: disk capacity: gc size")
+		return
+	}
+	if !db.overCapacity {
+		db.logger.Infof("localstore: disk usage is %d bytes, above the capacity of %d bytes", usage, db.diskCapacity)
+	}
+	// every chunk takes at least its size on the disk
+	excess := (usage - uint64(float64(db.diskCapacity)*gcTargetRatio)) / swarm.ChunkSize
+	var target uint64
+	if excess < gcSize {
+		target = gcSize - excess
+	}
+	db.overCapacity = true
+	db.overCapacityGCTarget = target
+	db.triggerGarbageCollection()
+}
+
+// checkFreeDiskSpace refuses uploads and lowers the garbage collection target
+// under the current gc size if the free disk space is below the minimum, so
+// that the garbage collection frees some space.
+func (db *DB) checkFreeDiskSpace() {
+	if db.minFreeDiskSpace == 0 {
+		return
+	}
+
+	free, err := freeDiskSpace(db.path)
+	if err != nil {
+		db.logger.Debugf("localstore: free disk space: %v", err)
+		if !errors.Is(err, errDiskFreeUnsupported) {
+			db.logger.Error("localstore: free disk space")
+		}
+		return
+	}
+	db.metrics.DiskFree.Set(float64(free))
+
+	db.batchMu.Lock()
+	defer db.batchMu.Unlock()
+
+	if free >= db.minFreeDiskSpace {
+		if db.lowDiskSpace {
+			db.lowDiskSpace = false
+			db.metrics.LowDiskSpace.Set(0)
+			db.logger.Infof("localstore: free disk space is %d bytes, uploads are accepted", free)
+		}
+		return
+	}
+
+	gcSize, err := db.gcSize.Get()
+	if err != nil {
+		db.logger.Debugf("localstore: free disk space: gc size: %v", err)
+		db.logger.Error("localstore: free disk space: gc size")
+		return
+	}
+	if !db.lowDiskSpace {
+		db.logger.Warningf("localstore: free disk space is %d bytes, below %d bytes, uploads are refused", free, db.minFreeDiskSpace)
+	}
+	db.lowDiskSpace = true
+	db.lowDiskSpaceGCTarget = uint64(float64(gcSize) * gcTargetRatio)
+	db.metrics.LowDiskSpace.Set(1)
+	db.triggerGarbageCollection()
+}
+
+// DiskUsage returns the number of bytes of the database files, without
+// the free slots of the blob store which are reused by new chunks, zero
+// if the database is in memory.
+func (db *DB) DiskUsage() (size uint64, err error) {
+	if db.path == "" {
+		return 0, nil
+	}
+	err = filepath.Walk(db.path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			// files are removed by compactions during the walk
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if free := uint64(db.blobs.FreeSize()); free < size {
+		size -= free
+	}
+	return size, nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// TestDB_lowDiskSpace validates that uploads are refused and that garbage
+// collection frees space while the free disk space is below the minimum.
+func TestDB_lowDiskSpace(t *testing.T) {
+	var free uint64 = 1 << 30
+	checked := stubFreeDiskSpace(t, &free)
+
+	dir, err := ioutil.TempDir("", "localstore-disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := New(dir, make([]byte, 32), &Options{
+		Capacity:         100,
+		MinFreeDiskSpace: 1 << 20,
+	}, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	<-checked
+
+	chunks := generateTestRandomChunks(50)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Set(context.Background(), storage.ModeSetSyncPull, chunkAddresses(chunks)...); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := db.DiskUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage == 0 {
+		t.Error("got no disk usage")
+	}
+
+	atomic.StoreUint64(&free, 1<<10)
+	db.checkDisk()
+
+	_, err = db.Put(context.Background(), storage.ModePutUpload, generateTestRandomChunk())
+	if !errors.Is(err, storage.ErrInsufficientSpace) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrInsufficientSpace)
+	}
+	// chunks from the network are garbage collected, so they are accepted
+	if _, err := db.Put(context.Background(), storage.ModePutSync, generateTestRandomChunk()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.CollectGarbage(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 90% of 50 chunks are left
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, 45))
+
+	t.Run("gc size", newIndexGCSizeTest(db))
+
+	atomic.StoreUint64(&free, 1<<30)
+	db.checkDisk()
+
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, generateTestRandomChunk()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestDB_diskCapacity validates that garbage collection removes chunks
+// below the capacity while the disk usage exceeds the disk capacity.
+func TestDB_diskCapacity(t *testing.T) {
+	var free uint64 = 1 << 30
+	checked := stubFreeDiskSpace(t, &free)
+
+	dir, err := ioutil.TempDir("", "localstore-disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := New(dir, make([]byte, 32), &Options{
+		Capacity:         100,
+		DiskCapacity:     1 << 40,
+		MinFreeDiskSpace: 1,
+	}, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	<-checked
+
+	chunks := generateTestRandomChunks(50)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Set(context.Background(), storage.ModeSetSyncPull, chunkAddresses(chunks)...); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := db.DiskUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the capacity is exceeded by the size of ten chunks
+	db.diskCapacity = usage - 10*swarm.ChunkSize
+	db.checkDisk()
+
+	if _, err := db.CollectGarbage(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	gcSize, err := db.gcSize.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gcSize > 40 {
+		t.Errorf("got gc size %v, want at most 40", gcSize)
+	}
+	t.Run("gc size", newIndexGCSizeTest(db))
+
+	// the free slots of the collected chunks are not counted
+	newUsage, err := db.DiskUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newUsage >= usage {
+		t.Errorf("got disk usage %v, want less than %v", newUsage, usage)
+	}
+
+	db.diskCapacity = 1 << 40
+	db.checkDisk()
+	db.batchMu.Lock()
+	overCapacity := db.overCapacity
+	db.batchMu.Unlock()
+	if overCapacity {
+		t.Error("disk capacity is exceeded")
+	}
+}
+
+// TestDB_disableBackgroundWorkers validates that the database is not
+// changed in the background if background workers are disabled.
+func TestDB_disableBackgroundWorkers(t *testing.T) {
+	var free uint64 = 1 << 10
+	checked := stubFreeDiskSpace(t, &free)
+
+	dir, err := ioutil.TempDir("", "localstore-disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := New(dir, make([]byte, 32), &Options{
+		Capacity:                 10,
+		MinFreeDiskSpace:         1 << 20,
+		DisableBackgroundWorkers: true,
+	}, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	putSyncedChunks(t, db, 20)
+
+	select {
+	case <-checked:
+		t.Fatal("disk checked")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, 20))
+
+	t.Run("gc size", newIndexGCSizeTest(db))
+}
+
+// stubFreeDiskSpace replaces the free disk space measurement with the value
+// of free for the duration of the test. The returned channel is closed when
+// the free disk space is measured for the first time, by the initial check
+// of the disk watchdog, after which the test can check the disk on its own.
+func stubFreeDiskSpace(t *testing.T, free *uint64) (checked <-chan struct{}) {
+	t.Helper()
+
+	c := make(chan struct{})
+	var once sync.Once
+	f := freeDiskSpace
+	t.Cleanup(func() { freeDiskSpace = f })
+	freeDiskSpace = func(string) (uint64, error) {
+		once.Do(func() { close(c) })
+		return atomic.LoadUint64(free), nil
+	}
+	return c
+}
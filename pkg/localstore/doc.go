@@ -48,8 +48,9 @@ Push and Pull syncing.
 DB implements an internal garbage collector that removes only synced
 Chunks from the database based on their most recent access time.
 
-Internally, DB stores Chunk data and any required information, such as
-store and access timestamps in different shed indexes that can be
-iterated on by garbage collector or subscriptions.
+Internally, DB stores Chunk data in a blob store and any required
+information, such as data locations, store and access timestamps in
+different shed indexes that can be iterated on by garbage collector
+or subscriptions.
 */
 package localstore
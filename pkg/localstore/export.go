@@ -19,7 +19,9 @@ package localstore
 import (
 	"archive/tar"
 	"context"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -28,6 +30,7 @@ import (
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 const (
@@ -38,10 +41,67 @@ const (
 	currentExportVersion = "1"
 )
 
-// Export writes a tar structured data to the writer of
-// all chunks in the retrieval data index. It returns the
-// number of chunks exported.
-func (db *DB) Export(w io.Writer) (count int64, err error) {
+// ExportOptions select the chunks that are exported. All chunks
+// are selected by the zero value, and every set field narrows the
+// selection.
+type ExportOptions struct {
+	// Pinned selects only pinned chunks.
+	Pinned bool
+	// Root selects only chunks of the tree of the root reference,
+	// as created by the splitter, if it is not zero. References in
+	// the data of the tree chunks, like file entries, are not followed.
+	Root swarm.Address
+	// Bins selects only chunks in the range of proximity order bins.
+	Bins *BinRange
+	// StoredAfter selects only chunks stored after the time in
+	// unix nanoseconds.
+	StoredAfter int64
+	// AfterBinIDs selects only chunks with a bin ID greater than the one
+	// of their bin. Passing the last bin IDs of the previous export, as
+	// returned by LastPullSubscriptionBinID, makes the export incremental.
+	AfterBinIDs map[uint8]uint64
+	// UpToBinIDs selects only chunks with a bin ID not greater than the
+	// one of their bin. Passing the last bin IDs taken before the export
+	// limits it to the chunks stored before, so that the next export
+	// after them does not miss the chunks stored during the export.
+	UpToBinIDs map[uint8]uint64
+}
+
+// BinRange is an inclusive range of proximity order bins.
+type BinRange struct {
+	From, To uint8
+}
+
+// selects reports whether the chunk of the item with
+// retrieval data index fields is selected.
+func (o *ExportOptions) selects(db *DB, item shed.Item) (bool, error) {
+	po := db.po(swarm.NewAddress(item.Address))
+	if o.Bins != nil && (po < o.Bins.From || po > o.Bins.To) {
+		return false, nil
+	}
+	if item.StoreTimestamp <= o.StoredAfter {
+		return false, nil
+	}
+	if id, ok := o.AfterBinIDs[po]; ok && item.BinID <= id {
+		return false, nil
+	}
+	if id, ok := o.UpToBinIDs[po]; ok && item.BinID > id {
+		return false, nil
+	}
+	if o.Pinned {
+		return db.pinIndex.Has(item)
+	}
+	return true, nil
+}
+
+// Export writes a tar structured data to the writer of chunks in the
+// retrieval data index that are selected by the options, all chunks if
+// the options are nil. It returns the number of chunks exported.
+func (db *DB) Export(w io.Writer, o *ExportOptions) (count int64, err error) {
+	if o == nil {
+		o = new(ExportOptions)
+	}
+
 	tw := tar.NewWriter(w)
 	defer tw.Close()
 
@@ -56,7 +116,19 @@ func (db *DB) Export(w io.Writer) (count int64, err error) {
 		return 0, err
 	}
 
-	err = db.retrievalDataIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+	export := func(item shed.Item) (err error) {
+		ok, err := o.selects(db, item)
+		if err != nil || !ok {
+			return err
+		}
+		item, err = db.readData(item)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				// the chunk is removed after the iteration started
+				return nil
+			}
+			return err
+		}
 
 		hdr := &tar.Header{
 			Name: hex.EncodeToString(item.Address),
@@ -65,136 +137,198 @@ func (db *DB) Export(w io.Writer) (count int64, err error) {
 		}
 
 		if err := tw.WriteHeader(hdr); err != nil {
-			return false, err
+			return err
 		}
 		if _, err := tw.Write(item.Data); err != nil {
-			return false, err
+			return err
 		}
 		count++
+		return nil
+	}
+
+	if !o.Root.IsZero() {
+		err = db.iterateTree(o.Root, false, export)
+		return count, err
+	}
+
+	if o.Pinned {
+		err = db.pinIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+			item, err = db.retrievalDataIndex.Get(item)
+			if err != nil {
+				if errors.Is(err, leveldb.ErrNotFound) {
+					return false, nil
+				}
+				return true, err
+			}
+			if err := export(item); err != nil {
+				return true, err
+			}
+			return false, nil
+		}, nil)
+		return count, err
+	}
+
+	err = db.retrievalDataIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		if err := export(item); err != nil {
+			return true, err
+		}
 		return false, nil
 	}, nil)
 
 	return count, err
 }
 
-// Import reads a tar structured data from the reader and
-// stores chunks in the database. It returns the number of
-// chunks imported.
-func (db *DB) Import(r io.Reader, legacy bool) (count int64, err error) {
-	tr := tar.NewReader(r)
+// iterateTree calls the function with the retrieval data index item of
+// every chunk in the tree of the root reference, once for every chunk.
+// Chunks that are not stored, with their subtrees, are skipped if
+// skipMissing is true, otherwise an error is returned.
+func (db *DB) iterateTree(root swarm.Address, skipMissing bool, fn func(shed.Item) error) error {
+	seen := make(map[string]struct{})
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	var iterate func(addr swarm.Address) error
+	iterate = func(addr swarm.Address) error {
+		if _, ok := seen[string(addr.Bytes())]; ok {
+			return nil
+		}
+		seen[string(addr.Bytes())] = struct{}{}
 
-	errC := make(chan error)
-	doneC := make(chan struct{})
-	tokenPool := make(chan struct{}, 100)
-	var wg sync.WaitGroup
-	go func() {
-		var (
-			firstFile = true
-
-			// if exportVersionFilename file is not present
-			// assume current version
-			version = currentExportVersion
-		)
-		for {
-			hdr, err := tr.Next()
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				select {
-				case errC <- err:
-				case <-ctx.Done():
+		item, err := db.retrievalDataIndex.Get(addressToItem(addr))
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				if skipMissing {
+					return nil
 				}
+				return fmt.Errorf("chunk %s: %w", addr, storage.ErrNotFound)
 			}
-			if firstFile {
-				firstFile = false
-				if hdr.Name == exportVersionFilename {
-					data, err := ioutil.ReadAll(tr)
-					if err != nil {
-						select {
-						case errC <- err:
-						case <-ctx.Done():
-						}
-					}
-					version = string(data)
-					continue
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+
+		item, err = db.readData(item)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				if skipMissing {
+					return nil
 				}
+				return fmt.Errorf("chunk %s: %w", addr, storage.ErrNotFound)
 			}
-
-			if len(hdr.Name) != 64 {
-				db.logger.Warningf("localstore export: ignoring non-chunk file: %s", hdr.Name)
-				continue
+			return err
+		}
+		data := item.Data
+		if len(data) < 8 {
+			return fmt.Errorf("chunk %s: invalid data length %d", addr, len(data))
+		}
+		if binary.LittleEndian.Uint64(data[:8]) <= swarm.ChunkSize {
+			// data chunk
+			return nil
+		}
+		for refs := data[8:]; len(refs) >= swarm.HashSize; refs = refs[swarm.HashSize:] {
+			if err := iterate(swarm.NewAddress(refs[:swarm.HashSize])); err != nil {
+				return err
 			}
+		}
+		return nil
+	}
+	return iterate(root)
+}
 
-			keybytes, err := hex.DecodeString(hdr.Name)
-			if err != nil {
-				db.logger.Warningf("localstore export: ignoring invalid chunk file %s: %v", hdr.Name, err)
-				continue
-			}
+// Import reads a tar structured data from the reader and
+// stores chunks in the database with the put mode. It returns
+// the number of chunks imported.
+func (db *DB) Import(r io.Reader, mode storage.ModePut) (count int64, err error) {
+	tr := tar.NewReader(r)
 
-			data, err := ioutil.ReadAll(tr)
-			if err != nil {
-				select {
-				case errC <- err:
-				case <-ctx.Done():
-				}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		putErr    error
+		putErrMu  sync.Mutex
+		tokenPool = make(chan struct{}, 100)
+
+		firstFile = true
+		// if exportVersionFilename file is not present
+		// assume current version
+		version = currentExportVersion
+	)
+	// wait for all chunks to be stored
+	defer func() {
+		wg.Wait()
+		if err == nil {
+			err = putErr
+		}
+	}()
+
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return count, nil
 			}
-			key := swarm.NewAddress(keybytes)
-
-			var ch swarm.Chunk
-			switch version {
-			case currentExportVersion:
-				ch = swarm.NewChunk(key, data)
-			default:
-				select {
-				case errC <- fmt.Errorf("unsupported export data version %q", version):
-				case <-ctx.Done():
+			return count, err
+		}
+		if firstFile {
+			firstFile = false
+			if hdr.Name == exportVersionFilename {
+				data, err := ioutil.ReadAll(tr)
+				if err != nil {
+					return count, err
 				}
+				version = string(data)
+				continue
 			}
-			tokenPool <- struct{}{}
-			wg.Add(1)
-
-			go func() {
-				_, err := db.Put(ctx, storage.ModePutUpload, ch)
-				select {
-				case errC <- err:
-				case <-ctx.Done():
-					wg.Done()
-					<-tokenPool
-				default:
-					_, err := db.Put(ctx, storage.ModePutUpload, ch)
-					if err != nil {
-						errC <- err
-					}
-					wg.Done()
-					<-tokenPool
-				}
-			}()
+		}
 
-			count++
+		if len(hdr.Name) != 64 {
+			db.logger.Warningf("localstore export: ignoring non-chunk file: %s", hdr.Name)
+			continue
+		}
+
+		keybytes, err := hex.DecodeString(hdr.Name)
+		if err != nil {
+			db.logger.Warningf("localstore export: ignoring invalid chunk file %s: %v", hdr.Name, err)
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return count, err
+		}
+		key := swarm.NewAddress(keybytes)
+
+		var ch swarm.Chunk
+		switch version {
+		case currentExportVersion:
+			ch = swarm.NewChunk(key, data)
+		default:
+			return count, fmt.Errorf("unsupported export data version %q", version)
 		}
-		wg.Wait()
-		close(doneC)
-	}()
 
-	// wait for all chunks to be stored
-	for {
 		select {
-		case err := <-errC:
-			if err != nil {
-				return count, err
-			}
+		case tokenPool <- struct{}{}:
 		case <-ctx.Done():
-			return count, ctx.Err()
-		default:
-			select {
-			case <-doneC:
-				return count, nil
-			default:
-			}
+			// a chunk is not stored
+			return count, nil
 		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-tokenPool
+				wg.Done()
+			}()
+			if _, err := db.Put(ctx, mode, ch); err != nil {
+				putErrMu.Lock()
+				if putErr == nil {
+					putErr = err
+					cancel()
+				}
+				putErrMu.Unlock()
+			}
+		}()
+
+		count++
 	}
 }
@@ -19,8 +19,12 @@ package localstore
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"errors"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/splitter"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
@@ -46,7 +50,7 @@ func TestExportImport(t *testing.T) {
 
 	var buf bytes.Buffer
 
-	c, err := db1.Export(&buf)
+	c, err := db1.Export(&buf, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -57,7 +61,7 @@ func TestExportImport(t *testing.T) {
 
 	db2 := newTestDB(t, nil)
 
-	c, err = db2.Import(&buf, false)
+	c, err = db2.Import(&buf, storage.ModePutUpload)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -77,3 +81,184 @@ func TestExportImport(t *testing.T) {
 		}
 	}
 }
+
+// TestExport_filters validates that only chunks
+// selected by the export options are exported.
+func TestExport_filters(t *testing.T) {
+	var timestamp int64
+	defer setNow(func() int64 {
+		timestamp++
+		return timestamp
+	})()
+
+	db := newTestDB(t, nil)
+
+	chunks := generateTestRandomChunks(50)
+	for _, ch := range chunks {
+		if _, err := db.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Set(context.Background(), storage.ModeSetPin, chunkAddresses(chunks[:3])...); err != nil {
+		t.Fatal(err)
+	}
+	lastBinIDs := make(map[uint8]uint64)
+	for bin := uint8(0); bin <= swarm.MaxPO; bin++ {
+		id, err := db.LastPullSubscriptionBinID(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lastBinIDs[bin] = id
+	}
+	newChunks := generateTestRandomChunks(5)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, newChunks...); err != nil {
+		t.Fatal(err)
+	}
+	all := append(chunks, newChunks...)
+
+	for _, tc := range []struct {
+		name string
+		o    *ExportOptions
+		want []swarm.Chunk
+	}{
+		{
+			name: "pinned",
+			o:    &ExportOptions{Pinned: true},
+			want: chunks[:3],
+		},
+		{
+			name: "stored after",
+			o:    &ExportOptions{StoredAfter: 45},
+			want: all[45:],
+		},
+		{
+			name: "after bin ids",
+			o:    &ExportOptions{AfterBinIDs: lastBinIDs},
+			want: newChunks,
+		},
+		{
+			name: "up to bin ids",
+			o:    &ExportOptions{UpToBinIDs: lastBinIDs},
+			want: chunks,
+		},
+		{
+			name: "bins",
+			o:    &ExportOptions{Bins: &BinRange{From: 1, To: 2}},
+			want: filterChunks(all, func(ch swarm.Chunk) bool {
+				po := db.po(ch.Address())
+				return po >= 1 && po <= 2
+			}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			testExport(t, db, tc.o, tc.want)
+		})
+	}
+}
+
+// TestExport_root validates that chunks of
+// the tree of a root reference are exported.
+func TestExport_root(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	data := make([]byte, 3*swarm.ChunkSize+10)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	root, err := splitter.NewSimpleSplitter(db).Split(context.Background(), file.NewSimpleReadCloser(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, generateTestRandomChunks(10)...); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	c, err := db.Export(&buf, &ExportOptions{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// four data chunks and the root chunk
+	if c != 5 {
+		t.Errorf("got export count %v, want 5", c)
+	}
+
+	db2 := newTestDB(t, nil)
+	if _, err := db2.Import(&buf, storage.ModePutUpload); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db2.Get(context.Background(), storage.ModeGetLookup, root); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Export(&buf, &ExportOptions{Root: generateTestRandomChunk().Address()})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+// TestImport_mode validates that chunks are
+// imported with the provided put mode.
+func TestImport_mode(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	chunks := generateTestRandomChunks(10)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := db.Export(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	db2 := newTestDB(t, nil)
+	c, err := db2.Import(&buf, storage.ModePutSync)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != int64(len(chunks)) {
+		t.Errorf("got import count %v, want %v", c, len(chunks))
+	}
+
+	t.Run("push index count", newItemsCountTest(db2.pushIndex, 0))
+
+	t.Run("pull index count", newItemsCountTest(db2.pullIndex, len(chunks)))
+}
+
+// testExport validates that exactly the wanted chunks are exported.
+func testExport(t *testing.T, db *DB, o *ExportOptions, want []swarm.Chunk) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	c, err := db.Export(&buf, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != int64(len(want)) {
+		t.Errorf("got export count %v, want %v", c, len(want))
+	}
+
+	db2 := newTestDB(t, nil)
+	if _, err := db2.Import(&buf, storage.ModePutUpload); err != nil {
+		t.Fatal(err)
+	}
+	for _, ch := range want {
+		got, err := db2.Get(context.Background(), storage.ModeGetLookup, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(ch) {
+			t.Errorf("got chunk %s, want %s", got.Address(), ch.Address())
+		}
+	}
+}
+
+// filterChunks returns the chunks for which the function returns true.
+func filterChunks(chunks []swarm.Chunk, f func(swarm.Chunk) bool) (filtered []swarm.Chunk) {
+	for _, ch := range chunks {
+		if f(ch) {
+			filtered = append(filtered, ch)
+		}
+	}
+	return filtered
+}
@@ -17,11 +17,13 @@
 package localstore
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -78,21 +80,31 @@ func (db *DB) collectGarbageWorker() {
 // the rest of the garbage as the batch size limit is reached.
 // This function is called in collectGarbageWorker.
 func (db *DB) collectGarbage() (collectedCount uint64, done bool, err error) {
+	start := time.Now()
 	db.metrics.GCCounter.Inc()
-	defer totalTimeMetric(db.metrics.TotalTimeCollectGarbage, time.Now())
+	defer totalTimeMetric(db.metrics.TotalTimeCollectGarbage, start)
 	defer func() {
 		if err != nil {
 			db.metrics.GCErrorCounter.Inc()
 		}
+		db.recordGCRun(start, collectedCount)
 	}()
 
 	batch := new(leveldb.Batch)
-	target := db.gcTarget()
 
 	// protect database from changing idexes and gcSize
 	db.batchMu.Lock()
 	defer db.batchMu.Unlock()
 
+	target := db.gcTarget()
+	// remove more chunks to free disk space
+	if db.lowDiskSpace && db.lowDiskSpaceGCTarget < target {
+		target = db.lowDiskSpaceGCTarget
+	}
+	if db.overCapacity && db.overCapacityGCTarget < target {
+		target = db.overCapacityGCTarget
+	}
+
 	// run through the recently pinned chunks and
 	// remove them from the gcIndex before iterating through gcIndex
 	err = db.removeChunksInExcludeIndexFromGC()
@@ -107,10 +119,27 @@ func (db *DB) collectGarbage() (collectedCount uint64, done bool, err error) {
 	db.metrics.GCSize.Inc()
 
 	done = true
+	radius := db.reserveRadius
+	// continue after the chunks that the previous run iterated on, as
+	// the ones in the reserve would be skipped again
+	from := db.gcCursor
+	var cursor *shed.Item
+	var removed []shed.Item
 	err = db.gcIndex.Iterate(func(item shed.Item) (stop bool, err error) {
 		if gcSize-collectedCount <= target {
 			return true, nil
 		}
+		// keep the chunks in the reserve
+		if db.po(swarm.NewAddress(item.Address)) >= radius {
+			return false, nil
+		}
+		item, err = db.fillGCItem(item)
+		if err != nil {
+			return true, err
+		}
+		if !db.gcPolicy.Select(db.gcItem(item)) {
+			return false, nil
+		}
 
 		db.metrics.GCStoreTimeStamps.Set(float64(item.StoreTimestamp))
 		db.metrics.GCStoreAccessTimeStamps.Set(float64(item.AccessTimestamp))
@@ -118,43 +147,129 @@ func (db *DB) collectGarbage() (collectedCount uint64, done bool, err error) {
 		// delete from retrieve, pull, gc
 		err = db.retrievalDataIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return true, nil
+			return true, err
 		}
 		err = db.retrievalAccessIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return true, nil
+			return true, err
 		}
 		err = db.pullIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return true, nil
+			return true, err
 		}
 		err = db.gcIndex.DeleteInBatch(batch, item)
 		if err != nil {
-			return true, nil
+			return true, err
 		}
+		removed = append(removed, item)
 		collectedCount++
 		if collectedCount >= gcBatchSize {
 			// bach size limit reached,
 			// another gc run is needed
 			done = false
+			cursor = &item
 			return true, nil
 		}
 		return false, nil
-	}, nil)
+	}, &shed.IterateOptions{
+		StartFrom:         from,
+		SkipStartFromItem: true,
+	})
 	if err != nil {
 		return 0, false, err
 	}
 	db.metrics.GCCollectedCounter.Inc()
 
+	if done && gcSize-collectedCount > target {
+		if from != nil {
+			// chunks before the cursor may have become collectable,
+			// run again from the start before shrinking the reserve
+			done = false
+		} else if radius <= swarm.MaxPO {
+			// only the chunks in the reserve are left above the target,
+			// shrink the reserve and run again to collect its farthest bin
+			db.reserveRadius++
+			db.metrics.GCReserveRadius.Set(float64(db.reserveRadius))
+			db.logger.Debugf("localstore: reserve is full, radius increased to %d", db.reserveRadius)
+			done = false
+		}
+	}
+
 	db.gcSize.PutInBatch(batch, gcSize-collectedCount)
 	err = db.shed.WriteBatch(batch)
 	if err != nil {
 		db.metrics.GCExcludeWriteBatchError.Inc()
 		return 0, false, err
 	}
+	db.gcCursor = cursor
+	db.chunksRemoved(removed...)
 	return collectedCount, done, nil
 }
 
+// CollectGarbage runs garbage collection until the number of chunks in
+// the gc index is not above the gc target, regardless of the capacity
+// being reached. It returns the number of removed chunks.
+func (db *DB) CollectGarbage(ctx context.Context) (collectedCount uint64, err error) {
+	for {
+		c, done, err := db.collectGarbage()
+		collectedCount += c
+		if err != nil {
+			return collectedCount, err
+		}
+		if done {
+			return collectedCount, nil
+		}
+		select {
+		case <-ctx.Done():
+			return collectedCount, ctx.Err()
+		default:
+		}
+	}
+}
+
+// SetNeighborhoodDepth sets the radius of the reserve to the neighborhood
+// depth. Chunks with proximity order to the base key at or above the
+// radius are garbage collected only after all other chunks are removed,
+// when the radius is increased to shrink the reserve. The radius is not
+// changed if the depth is the same as the last one. As all chunks are
+// in the reserve at depth 0, the depth should be set only when it is
+// established.
+func (db *DB) SetNeighborhoodDepth(depth uint8) {
+	db.batchMu.Lock()
+	defer db.batchMu.Unlock()
+
+	if db.neighborhoodDepth == depth {
+		return
+	}
+	db.neighborhoodDepth = depth
+	db.reserveRadius = depth
+	// chunks that the reserve had before may be collected
+	db.gcCursor = nil
+	db.metrics.GCReserveRadius.Set(float64(depth))
+	db.logger.Debugf("localstore: reserve radius set to %d", depth)
+}
+
+// GCStats holds the statistics of garbage collection runs since the
+// database was opened. A run removes at most gcBatchSize chunks.
+type GCStats struct {
+	Runs           uint64
+	LastRun        time.Time // zero if garbage collection has not run
+	LastCollected  uint64
+	TotalCollected uint64
+}
+
+// recordGCRun updates garbage collection statistics
+// with the run that started at the start time.
+func (db *DB) recordGCRun(start time.Time, collectedCount uint64) {
+	db.gcStatsMu.Lock()
+	defer db.gcStatsMu.Unlock()
+
+	db.gcStats.Runs++
+	db.gcStats.LastRun = start
+	db.gcStats.LastCollected = collectedCount
+	db.gcStats.TotalCollected += collectedCount
+}
+
 // removeChunksInExcludeIndexFromGC removed any recently chunks in the exclude Index, from the gcIndex.
 func (db *DB) removeChunksInExcludeIndexFromGC() (err error) {
 	db.metrics.GCExcludeCounter.Inc()
@@ -169,19 +284,11 @@ func (db *DB) removeChunksInExcludeIndexFromGC() (err error) {
 	excludedCount := 0
 	var gcSizeChange int64
 	err = db.gcExcludeIndex.Iterate(func(item shed.Item) (stop bool, err error) {
-		// Get access timestamp
-		retrievalAccessIndexItem, err := db.retrievalAccessIndex.Get(item)
-		if err != nil {
-			return false, err
-		}
-		item.AccessTimestamp = retrievalAccessIndexItem.AccessTimestamp
-
-		// Get the binId
-		retrievalDataIndexItem, err := db.retrievalDataIndex.Get(item)
+		// Get access timestamp, access count and the binId
+		item, err = db.fillGCItem(item)
 		if err != nil {
 			return false, err
 		}
-		item.BinID = retrievalDataIndexItem.BinID
 
 		// Check if this item is in gcIndex and remove it
 		ok, err := db.gcIndex.Has(item)
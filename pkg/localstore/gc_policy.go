@@ -0,0 +1,306 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// GCPolicy defines the order in which chunks are garbage collected.
+type GCPolicy interface {
+	// Name identifies the policy. When the database is opened with a
+	// policy with a different name than the last time, the gc index is
+	// rebuilt, so policies with different orders must have different names.
+	Name() string
+	// Key returns a new gc index key of the chunk, without its address.
+	// Chunks with lower keys are garbage collected first. The key must be
+	// derived only from the GCItem fields.
+	Key(item GCItem) []byte
+	// Access returns the chunk state after it is accessed with the current
+	// time in unix nanoseconds. The gc index is updated only if ok is true
+	// and only AccessTimestamp and AccessCount changes are stored.
+	Access(item GCItem, now int64) (updated GCItem, ok bool)
+	// Select reports whether the chunk is removed in the garbage collection
+	// batch. Chunks that are not selected are kept until the next run.
+	Select(item GCItem) bool
+}
+
+// GCItem holds the state of a chunk in the gc index.
+type GCItem struct {
+	Address swarm.Address
+	// Proximity is the proximity order of the chunk to the base key.
+	Proximity uint8
+	BinID     uint64
+	// AccessTimestamp is the time of the last access in unix nanoseconds,
+	// or the time when the chunk was added to the gc index.
+	AccessTimestamp int64
+	// AccessCount is the number of accesses since the chunk was stored.
+	AccessCount uint64
+}
+
+// NewLRUPolicy returns a policy that garbage collects
+// least recently accessed chunks first. It is the default policy.
+func NewLRUPolicy() GCPolicy {
+	return lruPolicy{}
+}
+
+type lruPolicy struct{}
+
+func (lruPolicy) Name() string { return "lru" }
+
+func (lruPolicy) Key(item GCItem) []byte {
+	key := make([]byte, 16, 16+swarm.HashSize)
+	binary.BigEndian.PutUint64(key[:8], uint64(item.AccessTimestamp))
+	binary.BigEndian.PutUint64(key[8:16], item.BinID)
+	return key
+}
+
+func (lruPolicy) Access(item GCItem, now int64) (GCItem, bool) {
+	item.AccessTimestamp = now
+	item.AccessCount++
+	return item, true
+}
+
+func (lruPolicy) Select(GCItem) bool { return true }
+
+// NewLFUPolicy returns a policy that garbage collects least frequently
+// accessed chunks first, and least recently accessed ones among the chunks
+// with the same number of accesses.
+func NewLFUPolicy() GCPolicy {
+	return lfuPolicy{}
+}
+
+type lfuPolicy struct{}
+
+func (lfuPolicy) Name() string { return "lfu" }
+
+func (lfuPolicy) Key(item GCItem) []byte {
+	key := make([]byte, 24, 24+swarm.HashSize)
+	binary.BigEndian.PutUint64(key[:8], item.AccessCount)
+	binary.BigEndian.PutUint64(key[8:16], uint64(item.AccessTimestamp))
+	binary.BigEndian.PutUint64(key[16:24], item.BinID)
+	return key
+}
+
+func (lfuPolicy) Access(item GCItem, now int64) (GCItem, bool) {
+	item.AccessTimestamp = now
+	item.AccessCount++
+	return item, true
+}
+
+func (lfuPolicy) Select(GCItem) bool { return true }
+
+// NewProximityPolicy returns a policy that garbage collects least recently
+// accessed chunks first, where every proximity order of a chunk to the base
+// key counts as if it was accessed later by the weight duration. With a
+// weight of an hour, a chunk in bin 3 is kept longer than a chunk in bin 1
+// accessed up to two hours later.
+func NewProximityPolicy(weight time.Duration) GCPolicy {
+	return proximityPolicy{weight: weight}
+}
+
+type proximityPolicy struct {
+	weight time.Duration
+}
+
+func (p proximityPolicy) Name() string { return "proximity:" + p.weight.String() }
+
+func (p proximityPolicy) Key(item GCItem) []byte {
+	key := make([]byte, 16, 16+swarm.HashSize)
+	binary.BigEndian.PutUint64(key[:8], uint64(item.AccessTimestamp+int64(item.Proximity)*int64(p.weight)))
+	binary.BigEndian.PutUint64(key[8:16], item.BinID)
+	return key
+}
+
+func (proximityPolicy) Access(item GCItem, now int64) (GCItem, bool) {
+	item.AccessTimestamp = now
+	item.AccessCount++
+	return item, true
+}
+
+func (proximityPolicy) Select(GCItem) bool { return true }
+
+// gcKey returns the gc index key of the item without the index prefix.
+func (db *DB) gcKey(item shed.Item) []byte {
+	return append(db.gcPolicy.Key(db.gcItem(item)), item.Address...)
+}
+
+// newRawGCIndex returns the gc index with its keys as they are stored in
+// the Data field of items, so that items can be deleted regardless of
+// the policy that their keys are encoded with.
+func (db *DB) newRawGCIndex() (shed.Index, error) {
+	return db.shed.NewIndex("AccessTimestamp|BinID|Hash->nil", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			return fields.Data, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.Address = key[len(key)-swarm.HashSize:]
+			e.Data = key
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			return nil, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			return e, nil
+		},
+	})
+}
+
+// gcPolicyByName returns the policy with the name returned by its Name method.
+func gcPolicyByName(name string) (GCPolicy, error) {
+	switch {
+	case name == "lru":
+		return NewLRUPolicy(), nil
+	case name == "lfu":
+		return NewLFUPolicy(), nil
+	case strings.HasPrefix(name, "proximity:"):
+		weight, err := time.ParseDuration(strings.TrimPrefix(name, "proximity:"))
+		if err != nil {
+			return nil, fmt.Errorf("gc policy %q: %w", name, err)
+		}
+		return NewProximityPolicy(weight), nil
+	}
+	return nil, fmt.Errorf("unknown gc policy %q", name)
+}
+
+// gcItem returns the gc policy state of the item.
+func (db *DB) gcItem(item shed.Item) GCItem {
+	addr := swarm.NewAddress(item.Address)
+	return GCItem{
+		Address:         addr,
+		Proximity:       db.po(addr),
+		BinID:           item.BinID,
+		AccessTimestamp: item.AccessTimestamp,
+		AccessCount:     item.AccessCount,
+	}
+}
+
+// fillGCItem sets the item fields that gc index keys are derived from,
+// as only the address is decoded from the gc index keys.
+func (db *DB) fillGCItem(item shed.Item) (shed.Item, error) {
+	i, err := db.retrievalAccessIndex.Get(item)
+	if err != nil {
+		return item, fmt.Errorf("retrieval access index: %w", err)
+	}
+	item.AccessTimestamp = i.AccessTimestamp
+	item.AccessCount = i.AccessCount
+
+	i, err = db.retrievalDataIndex.Get(item)
+	if err != nil {
+		return item, fmt.Errorf("retrieval data index: %w", err)
+	}
+	item.BinID = i.BinID
+	item.StoreTimestamp = i.StoreTimestamp
+	item.Location = i.Location
+	return item, nil
+}
+
+// migrateGCPolicy rebuilds the gc index if it was built with a different
+// policy. The gc index keys are moved to a temporary index first, so that
+// the rebuild can continue if it is interrupted.
+func (db *DB) migrateGCPolicy() error {
+	stored, err := db.gcPolicyName.Get()
+	if err != nil {
+		return err
+	}
+	current := stored
+	if current == "" {
+		// the gc index was built before policies were introduced
+		current = NewLRUPolicy().Name()
+	}
+	if db.gcPolicy == nil {
+		db.gcPolicy, err = gcPolicyByName(current)
+		if err != nil {
+			return err
+		}
+	}
+	name := db.gcPolicy.Name()
+	if current == name {
+		if stored == "" {
+			return db.gcPolicyName.Put(name)
+		}
+		return nil
+	}
+	gcSize, err := db.gcSize.Get()
+	if err != nil {
+		return err
+	}
+	if gcSize == 0 {
+		// nothing to rebuild
+		return db.gcPolicyName.Put(name)
+	}
+	db.logger.Infof("localstore migration: rebuilding gc index from policy %s to %s", current, name)
+
+	// keys of the gc index encoded with the previous policy
+	rawGCIndex, err := db.newRawGCIndex()
+	if err != nil {
+		return err
+	}
+	rebuildIndex, err := db.shed.NewIndex("GCRebuild|Hash->nil", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			return fields.Address, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.Address = key
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			return nil, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			return e, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// move the chunk addresses from the gc index to the rebuild index
+	err = inBatches(db, rawGCIndex, func(batch *leveldb.Batch, item shed.Item) error {
+		if err := rawGCIndex.DeleteInBatch(batch, item); err != nil {
+			return err
+		}
+		return rebuildIndex.PutInBatch(batch, item)
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("clear gc index: %w", err)
+	}
+
+	// add the chunks back to the gc index with the keys of the new policy
+	var count int
+	err = inBatches(db, rebuildIndex, func(batch *leveldb.Batch, item shed.Item) error {
+		if err := rebuildIndex.DeleteInBatch(batch, item); err != nil {
+			return err
+		}
+		item, err := db.fillGCItem(item)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				// the chunk is removed, its gc size is fixed below
+				return nil
+			}
+			return err
+		}
+		count++
+		return db.gcIndex.PutInBatch(batch, item)
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("rebuild gc index: %w", err)
+	}
+	if err := db.gcSize.Put(uint64(count)); err != nil {
+		return err
+	}
+
+	db.logger.Infof("localstore migration: rebuilt gc index with %d chunks", count)
+	return db.gcPolicyName.Put(name)
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// TestDB_collectGarbage_LFU validates that least frequently
+// accessed chunks are garbage collected first with the LFU policy.
+func TestDB_collectGarbage_LFU(t *testing.T) {
+	db := newTestDB(t, &Options{
+		Capacity: 100,
+		GCPolicy: NewLFUPolicy(),
+	})
+
+	chunks := putSyncedChunks(t, db, 95)
+
+	// accessed most, but least recently
+	for i := 0; i < 3; i++ {
+		for _, ch := range chunks[:5] {
+			accessChunk(t, db, ch.Address())
+		}
+	}
+	for _, ch := range chunks[5:] {
+		accessChunk(t, db, ch.Address())
+	}
+
+	if _, err := db.CollectGarbage(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, 90))
+
+	t.Run("gc size", newIndexGCSizeTest(db))
+
+	for i, ch := range chunks {
+		has, err := db.Has(context.Background(), ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i < 5 || i >= 10; has != want {
+			t.Errorf("chunk %v: got stored %v, want %v", i, has, want)
+		}
+	}
+}
+
+// TestDB_collectGarbage_proximity validates that chunks farther
+// from the base key are garbage collected first with the proximity policy.
+func TestDB_collectGarbage_proximity(t *testing.T) {
+	var timestamp int64
+	defer setNow(func() int64 {
+		timestamp++
+		return timestamp
+	})()
+
+	db := newTestDB(t, &Options{
+		Capacity: 100,
+		GCPolicy: NewProximityPolicy(time.Hour),
+	})
+
+	chunks := putSyncedChunks(t, db, 95)
+
+	// timestamps are negligible compared to the weight of
+	// proximity, so the order is by proximity first
+	sorted := append([]swarm.Chunk(nil), chunks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return db.po(sorted[i].Address()) < db.po(sorted[j].Address())
+	})
+
+	if _, err := db.CollectGarbage(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, ch := range sorted {
+		has, err := db.Has(context.Background(), ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i >= 5; has != want {
+			t.Errorf("chunk %v with proximity %v: got stored %v, want %v", i, db.po(ch.Address()), has, want)
+		}
+	}
+}
+
+// TestDB_migrateGCPolicy validates that the gc index is
+// rebuilt when the database is opened with a different policy.
+func TestDB_migrateGCPolicy(t *testing.T) {
+	defer func(s int) { migrationBatchSize = s }(migrationBatchSize)
+	migrationBatchSize = 3
+
+	dir, err := ioutil.TempDir("", "localstore-gc-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	baseKey := make([]byte, 32)
+	logger := logging.New(ioutil.Discard, 0)
+
+	db, err := New(dir, baseKey, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := putSyncedChunks(t, db, 20)
+	// the last chunks are accessed more, so they are the last in lfu order
+	for _, ch := range chunks[15:] {
+		accessChunk(t, db, ch.Address())
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, policy := range []GCPolicy{
+		NewLFUPolicy(),
+		NewLFUPolicy(),
+		NewProximityPolicy(time.Minute),
+		NewLRUPolicy(),
+	} {
+		db, err := New(dir, baseKey, &Options{GCPolicy: policy}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name, err := db.gcPolicyName.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != policy.Name() {
+			t.Errorf("got gc policy %q, want %q", name, policy.Name())
+		}
+
+		t.Run(policy.Name()+" gc index count", newItemsCountTest(db.gcIndex, len(chunks)))
+
+		t.Run(policy.Name()+" gc size", newIndexGCSizeTest(db))
+
+		// gc index is iterated in the order of the policy keys
+		var prev []byte
+		err = db.gcIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+			item, err = db.fillGCItem(item)
+			if err != nil {
+				return true, err
+			}
+			key := policy.Key(db.gcItem(item))
+			if string(key) < string(prev) {
+				t.Errorf("%s: chunk %s out of order", policy.Name(), swarm.NewAddress(item.Address))
+			}
+			prev = key
+			return false, nil
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the stored policy is kept if none is provided
+	for _, policy := range []GCPolicy{
+		NewProximityPolicy(time.Minute),
+		nil,
+	} {
+		db, err := New(dir, baseKey, &Options{GCPolicy: policy}, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := db.gcPolicy.Name(); name != "proximity:1m0s" {
+			t.Errorf("got gc policy %q, want %q", name, "proximity:1m0s")
+		}
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// putSyncedChunks uploads and syncs a number of random chunks.
+func putSyncedChunks(t *testing.T, db *DB, count int) []swarm.Chunk {
+	t.Helper()
+
+	chunks := generateTestRandomChunks(count)
+	for _, ch := range chunks {
+		if _, err := db.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Set(context.Background(), storage.ModeSetSyncPull, ch.Address()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return chunks
+}
+
+// accessChunk updates the gc index as if the chunk was requested.
+func accessChunk(t *testing.T, db *DB, addr swarm.Address) {
+	t.Helper()
+
+	item, err := db.retrievalDataIndex.Get(addressToItem(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.updateGC(item); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -139,6 +139,201 @@ func testDBCollectGarbageWorker(t *testing.T) {
 	})
 }
 
+// TestDB_CollectGarbage tests that garbage collection can be run
+// before the capacity is reached and that its runs are recorded.
+func TestDB_CollectGarbage(t *testing.T) {
+	// lower the maximal number of chunks in a single
+	// gc batch to ensure multiple runs.
+	defer func(s uint64) { gcBatchSize = s }(gcBatchSize)
+	gcBatchSize = 2
+
+	db := newTestDB(t, &Options{
+		Capacity: 100,
+	})
+
+	// stay below the capacity, so that the garbage
+	// collection is not triggered, but above the target
+	for i := 0; i < 95; i++ {
+		ch := generateTestRandomChunk()
+
+		_, err := db.Put(context.Background(), storage.ModePutUpload, ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = db.Set(context.Background(), storage.ModeSetSyncPull, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now()
+	collected, err := db.CollectGarbage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collected != 5 {
+		t.Errorf("got %v collected chunks, want %v", collected, 5)
+	}
+
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, int(db.gcTarget())))
+
+	t.Run("gc size", newIndexGCSizeTest(db))
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// two runs with two chunks, one with the last chunk
+	if stats.GC.Runs != 3 || stats.GC.LastCollected != 1 || stats.GC.TotalCollected != 5 {
+		t.Errorf("got gc stats %+v, want 3 runs with 5 collected chunks", stats.GC)
+	}
+	if stats.GC.LastRun.Before(start) {
+		t.Errorf("got last gc run at %v, want after %v", stats.GC.LastRun, start)
+	}
+}
+
+// TestDB_disableGarbageCollection validates that chunks are not garbage
+// collected above the capacity if garbage collection is disabled, but
+// that they are collected by CollectGarbage.
+func TestDB_disableGarbageCollection(t *testing.T) {
+	db := newTestDB(t, &Options{
+		Capacity:                 10,
+		DisableGarbageCollection: true,
+	})
+
+	putSyncedChunks(t, db, 20)
+
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, 20))
+
+	collected, err := db.CollectGarbage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collected != 11 {
+		t.Errorf("got %v collected chunks, want %v", collected, 11)
+	}
+
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, int(db.gcTarget())))
+
+	t.Run("gc size", newIndexGCSizeTest(db))
+}
+
+// TestDB_CollectGarbage_reserve tests that chunks within the neighborhood
+// depth are kept while there are other chunks to collect and that the
+// reserve radius is increased when it is not the case.
+func TestDB_CollectGarbage_reserve(t *testing.T) {
+	// collect in multiple runs that continue after the skipped reserve chunks
+	defer func(s uint64) { gcBatchSize = s }(gcBatchSize)
+	gcBatchSize = 2
+
+	db := newTestDB(t, &Options{
+		Capacity: 100,
+	})
+	db.SetNeighborhoodDepth(1)
+
+	putSynced := func(count int) (reserve []swarm.Address) {
+		t.Helper()
+
+		for i := 0; i < count; i++ {
+			ch := generateTestRandomChunk()
+
+			_, err := db.Put(context.Background(), storage.ModePutUpload, ch)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = db.Set(context.Background(), storage.ModeSetSyncPull, ch.Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if db.po(ch.Address()) >= 1 {
+				reserve = append(reserve, ch.Address())
+			}
+		}
+		return reserve
+	}
+
+	reserve := putSynced(95)
+
+	collected, err := db.CollectGarbage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collected != 5 {
+		t.Errorf("got %v collected chunks, want %v", collected, 5)
+	}
+	for _, addr := range reserve {
+		has, err := db.Has(context.Background(), addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !has {
+			t.Errorf("reserve chunk %s is garbage collected", addr)
+		}
+	}
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.ReserveRadius != 1 || stats.ReserveSize != uint64(len(reserve)) {
+		t.Errorf("got reserve radius %v with %v chunks, want 1 with %v", stats.ReserveRadius, stats.ReserveSize, len(reserve))
+	}
+
+	// all chunks are in the reserve, so it must be shrunk
+	// to collect the chunks in the farthest bin
+	db.SetNeighborhoodDepth(0)
+	putSynced(5)
+
+	collected, err = db.CollectGarbage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collected != 5 {
+		t.Errorf("got %v collected chunks, want %v", collected, 5)
+	}
+	for _, addr := range reserve {
+		has, err := db.Has(context.Background(), addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !has {
+			t.Errorf("reserve chunk %s is garbage collected", addr)
+		}
+	}
+
+	stats, err = db.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.ReserveRadius != 1 {
+		t.Errorf("got reserve radius %v, want %v", stats.ReserveRadius, 1)
+	}
+	if db.gcCursor != nil {
+		t.Errorf("got gc cursor %v after all garbage is collected", db.gcCursor)
+	}
+
+	// the shrunk reserve is kept until the depth changes
+	for _, tc := range []struct {
+		depth, radius uint8
+	}{
+		{depth: 0, radius: 1},
+		{depth: 2, radius: 2},
+	} {
+		db.SetNeighborhoodDepth(tc.depth)
+		stats, err = db.Stats()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.ReserveRadius != tc.radius {
+			t.Errorf("depth %v: got reserve radius %v, want %v", tc.depth, stats.ReserveRadius, tc.radius)
+		}
+	}
+
+	t.Run("gc size", newIndexGCSizeTest(db))
+}
+
 // Pin a file, upload chunks to go past the gc limit to trigger GC,
 // check if the pinned files are still around and removed from gcIndex
 func TestPinGC(t *testing.T) {
@@ -470,6 +665,16 @@ func TestDB_gcSize(t *testing.T) {
 	defer db.Close()
 
 	t.Run("gc index size", newIndexGCSizeTest(db))
+
+	t.Run("gc size", func(t *testing.T) {
+		got, err := db.GCSize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != uint64(count) {
+			t.Errorf("got gc size %v, want %v", got, count)
+		}
+	})
 }
 
 // setTestHookCollectGarbage sets testHookCollectGarbage and
@@ -0,0 +1,124 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// ChunkInfo holds the state of a chunk in the database indexes.
+type ChunkInfo struct {
+	Indexes ChunkIndexes
+	BinID   uint64
+	// StoreTimestamp and AccessTimestamp are unix timestamps in
+	// nanoseconds, zero if the chunk is not stored or not accessed.
+	StoreTimestamp  int64
+	AccessTimestamp int64
+	PinCounter      uint64
+	Tag             uint32
+}
+
+// ChunkIndexes tells which database indexes contain a chunk.
+type ChunkIndexes struct {
+	Retrieval bool
+	Push      bool
+	Pull      bool
+	GC        bool
+	GCExclude bool
+	Pin       bool
+}
+
+// ChunkInfo returns the state of the chunk with the address in the
+// database indexes. It returns storage.ErrNotFound if none of the
+// indexes contain the chunk.
+func (db *DB) ChunkInfo(addr swarm.Address) (info ChunkInfo, err error) {
+	item := addressToItem(addr)
+
+	stored, err := getItem(db.retrievalDataIndex, item)
+	if err != nil {
+		return ChunkInfo{}, fmt.Errorf("retrieval data index: %w", err)
+	}
+	if stored != nil {
+		info.Indexes.Retrieval = true
+		info.BinID = stored.BinID
+		info.StoreTimestamp = stored.StoreTimestamp
+		item.BinID = stored.BinID
+		item.StoreTimestamp = stored.StoreTimestamp
+
+		pushed, err := getItem(db.pushIndex, item)
+		if err != nil {
+			return ChunkInfo{}, fmt.Errorf("push index: %w", err)
+		}
+		if pushed != nil {
+			info.Indexes.Push = true
+			info.Tag = pushed.Tag
+		}
+
+		pulled, err := getItem(db.pullIndex, item)
+		if err != nil {
+			return ChunkInfo{}, fmt.Errorf("pull index: %w", err)
+		}
+		if pulled != nil {
+			info.Indexes.Pull = true
+			if info.Tag == 0 {
+				info.Tag = pulled.Tag
+			}
+		}
+	}
+
+	accessed, err := getItem(db.retrievalAccessIndex, item)
+	if err != nil {
+		return ChunkInfo{}, fmt.Errorf("retrieval access index: %w", err)
+	}
+	if accessed != nil {
+		info.AccessTimestamp = accessed.AccessTimestamp
+		item.AccessTimestamp = accessed.AccessTimestamp
+		item.AccessCount = accessed.AccessCount
+
+		if stored != nil {
+			info.Indexes.GC, err = db.gcIndex.Has(item)
+			if err != nil {
+				return ChunkInfo{}, fmt.Errorf("gc index: %w", err)
+			}
+		}
+	}
+
+	info.Indexes.GCExclude, err = db.gcExcludeIndex.Has(item)
+	if err != nil {
+		return ChunkInfo{}, fmt.Errorf("gc exclude index: %w", err)
+	}
+
+	pinned, err := getItem(db.pinIndex, item)
+	if err != nil {
+		return ChunkInfo{}, fmt.Errorf("pin index: %w", err)
+	}
+	if pinned != nil {
+		info.Indexes.Pin = true
+		info.PinCounter = pinned.PinCounter
+	}
+
+	if info.Indexes == (ChunkIndexes{}) {
+		return ChunkInfo{}, storage.ErrNotFound
+	}
+	return info, nil
+}
+
+// getItem returns the item from the index, or nil if it is not found.
+func getItem(index shed.Index, item shed.Item) (*shed.Item, error) {
+	i, err := index.Get(item)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &i, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+func TestChunkInfo(t *testing.T) {
+	db := newTestDB(t, nil)
+	ch := generateTestRandomChunk().WithTagID(7)
+
+	if _, err := db.ChunkInfo(ch.Address()); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+
+	check := func(t *testing.T, want ChunkInfo) {
+		t.Helper()
+
+		got, err := db.ChunkInfo(ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("got chunk info %+v, want %+v", got, want)
+		}
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		defer setNow(func() int64 { return 1000 })()
+
+		if _, err := db.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+			t.Fatal(err)
+		}
+
+		check(t, ChunkInfo{
+			Indexes:        ChunkIndexes{Retrieval: true, Push: true, Pull: true},
+			BinID:          1,
+			StoreTimestamp: 1000,
+			Tag:            7,
+		})
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		defer setNow(func() int64 { return 2000 })()
+
+		if err := db.Set(context.Background(), storage.ModeSetSyncPush, ch.Address()); err != nil {
+			t.Fatal(err)
+		}
+
+		check(t, ChunkInfo{
+			Indexes:         ChunkIndexes{Retrieval: true, Pull: true, GC: true},
+			BinID:           1,
+			StoreTimestamp:  1000,
+			AccessTimestamp: 2000,
+			Tag:             7,
+		})
+	})
+
+	t.Run("pin", func(t *testing.T) {
+		if err := db.Set(context.Background(), storage.ModeSetPin, ch.Address()); err != nil {
+			t.Fatal(err)
+		}
+
+		check(t, ChunkInfo{
+			Indexes:         ChunkIndexes{Retrieval: true, Pull: true, GC: true, GCExclude: true, Pin: true},
+			BinID:           1,
+			StoreTimestamp:  1000,
+			AccessTimestamp: 2000,
+			PinCounter:      1,
+			Tag:             7,
+		})
+	})
+}
@@ -19,11 +19,14 @@ package localstore
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"sync"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/blobstore"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -39,6 +42,9 @@ var (
 	// ErrInvalidMode is retuned when an unknown Mode
 	// is provided to the function.
 	ErrInvalidMode = errors.New("invalid mode")
+	// ErrBaseKeyUnknown is returned by New if the base key is not
+	// provided and it is not stored in the persisted database.
+	ErrBaseKeyUnknown = errors.New("base key unknown")
 )
 
 var (
@@ -55,6 +61,10 @@ type DB struct {
 	shed *shed.DB
 	tags *tags.Tags
 
+	// chunk data store, the retrieval data
+	// index holds locations of chunk data in it
+	blobs *blobstore.Store
+
 	// schema name of loaded data
 	schemaName shed.StringField
 
@@ -86,16 +96,63 @@ type DB struct {
 	// pin files Index
 	pinIndex shed.Index
 
+	// named pins of trees by their names
+	pinsIndex shed.Index
+
+	// pins that are being created or removed by their names
+	pendingPinsIndex shed.Index
+
 	// field that stores number of intems in gc index
 	gcSize shed.Uint64Field
 
+	// order of chunks in gc index and the name of the
+	// policy that the gc index is built with
+	gcPolicy     GCPolicy
+	gcPolicyName shed.StringField
+
 	// garbage collection is triggered when gcSize exceeds
 	// the capacity value
 	capacity uint64
+	// garbage collection removes chunks until the
+	// overCapacityGCTarget while the disk usage exceeds the
+	// diskCapacity, protected by batchMu
+	diskCapacity         uint64
+	overCapacity         bool
+	overCapacityGCTarget uint64
 
 	// triggers garbage collection event loop
 	collectGarbageTrigger chan struct{}
 
+	// path of the database directory, empty for in-memory database
+	path string
+	// uploads are refused when the free disk space is below
+	// minFreeDiskSpace and garbage collection removes chunks
+	// until the lowDiskSpaceGCTarget, protected by batchMu
+	minFreeDiskSpace     uint64
+	lowDiskSpace         bool
+	lowDiskSpaceGCTarget uint64
+
+	// chunks with proximity order to baseKey greater or equal to
+	// reserveRadius are in the reserve and are not garbage collected
+	// while there are other chunks to collect, protected by batchMu
+	reserveRadius uint8
+	// neighborhoodDepth is the last depth set by SetNeighborhoodDepth,
+	// which the reserveRadius is increased from when the reserve is
+	// full, or swarm.MaxPO+1 if it is not set, protected by batchMu
+	neighborhoodDepth uint8
+	// gcCursor is the last item of the gc index iterated by the garbage
+	// collection run that reached gcBatchSize, from which the next run
+	// continues, protected by batchMu
+	gcCursor *shed.Item
+
+	// called with the addresses of chunks removed by garbage
+	// collection or ModeSetRemove, protected by batchMu
+	removeHook func(addrs ...swarm.Address)
+
+	// statistics of garbage collection runs
+	gcStats   GCStats
+	gcStatsMu sync.RWMutex
+
 	// a buffered channel acting as a semaphore
 	// to limit the maximal number of goroutines
 	// created by Getters to call updateGC function
@@ -104,10 +161,15 @@ type DB struct {
 	// are done before closing the database
 	updateGCWG sync.WaitGroup
 
-	// baseKey is the overlay address
-	baseKey []byte
+	// baseKey is the overlay address, it is persisted
+	// so that the database can be opened without it
+	baseKey      []byte
+	baseKeyField shed.StringField
 
 	batchMu sync.Mutex
+	// pinsMu serializes changes of named pins, which
+	// pin and unpin chunks in multiple batches
+	pinsMu sync.Mutex
 
 	// this channel is closed when close function is called
 	// to terminate other goroutines
@@ -117,6 +179,12 @@ type DB struct {
 	// garbage collection and gc size write workers
 	// are done
 	collectGarbageWorkerDone chan struct{}
+	// closed when the disk watchdog is done
+	diskWatchdogDone chan struct{}
+	// closed when the background integrity check is done
+	integrityCheckDone chan struct{}
+	// closed when the pin expiry worker is done
+	pinExpiryDone chan struct{}
 
 	// wait for all subscriptions to finish before closing
 	// underlaying BadgerDB to prevent possible panics from
@@ -131,8 +199,36 @@ type DB struct {
 // Options struct holds optional parameters for configuring DB.
 type Options struct {
 	// Capacity is a limit that triggers garbage collection when
-	// number of items in gcIndex equals or exceeds it.
+	// number of items in gcIndex equals or exceeds it. Pinned chunks
+	// and the overhead of indexes are not counted, so it is an upper
+	// bound of chunks if the disk usage is limited by DiskCapacity.
 	Capacity uint64
+	// DiskCapacity is the number of bytes that the database files may
+	// take, including pinned chunks and the overhead of indexes. The
+	// disk usage is measured periodically and garbage collection removes
+	// chunks below the Capacity while it exceeds the DiskCapacity. Zero
+	// disables the check.
+	DiskCapacity uint64
+	// MinFreeDiskSpace is the number of bytes of free disk space below
+	// which uploads are refused and garbage collection removes chunks
+	// below the capacity to free space. Zero disables the check.
+	MinFreeDiskSpace uint64
+	// IntegrityCheckInterval is the interval at which indexes are verified
+	// and repaired in the background. Zero disables the check.
+	IntegrityCheckInterval time.Duration
+	// DisableGarbageCollection disables garbage collection when the
+	// capacity is reached, like for maintenance of a database that is
+	// not used by a node. CollectGarbage still removes chunks.
+	DisableGarbageCollection bool
+	// DisableBackgroundWorkers disables all workers that change the
+	// database in the background: garbage collection, disk usage checks,
+	// the integrity check and removal of expired pins. It is meant for
+	// tools that open the database of a node that is not running.
+	DisableBackgroundWorkers bool
+	// GCPolicy defines the order in which chunks are garbage collected.
+	// If it is nil, the policy that the gc index is built with is kept,
+	// which collects least recently accessed chunks first for a new database.
+	GCPolicy GCPolicy
 	// MetricsPrefix defines a prefix for metrics names.
 	MetricsPrefix string
 	Tags          *tags.Tags
@@ -140,7 +236,8 @@ type Options struct {
 
 // New returns a new DB.  All fields and indexes are initialized
 // and possible conflicts with schema from existing database is checked.
-// One goroutine for writing batches is created.
+// One goroutine for writing batches is created. If the base key is nil,
+// the one that the persisted database was last opened with is used.
 func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB, err error) {
 	if o == nil {
 		// default options
@@ -150,9 +247,16 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 	}
 
 	db = &DB{
-		capacity: o.Capacity,
-		baseKey:  baseKey,
-		tags:     o.Tags,
+		capacity:         o.Capacity,
+		diskCapacity:     o.DiskCapacity,
+		path:             path,
+		minFreeDiskSpace: o.MinFreeDiskSpace,
+		gcPolicy:         o.GCPolicy,
+		baseKey:          baseKey,
+		tags:             o.Tags,
+		// nothing is reserved until the neighborhood depth is set
+		reserveRadius:     swarm.MaxPO + 1,
+		neighborhoodDepth: swarm.MaxPO + 1,
 		// channel collectGarbageTrigger
 		// needs to be buffered with the size of 1
 		// to signal another event if it
@@ -160,6 +264,9 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 		collectGarbageTrigger:    make(chan struct{}, 1),
 		close:                    make(chan struct{}),
 		collectGarbageWorkerDone: make(chan struct{}),
+		diskWatchdogDone:         make(chan struct{}),
+		integrityCheckDone:       make(chan struct{}),
+		pinExpiryDone:            make(chan struct{}),
 		metrics:                  newMetrics(),
 		logger:                   logger,
 	}
@@ -167,12 +274,16 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 		db.capacity = defaultCapacity
 	}
 
-	capacityMB := float64(db.capacity*swarm.ChunkSize) * 9.5367431640625e-7
-
-	if capacityMB <= 1000 {
-		db.logger.Infof("database capacity: %d chunks (approximately %fMB)", db.capacity, capacityMB)
+	if db.diskCapacity > 0 {
+		db.logger.Infof("database capacity: %d bytes of disk usage, at most %d chunks in gc index", db.diskCapacity, db.capacity)
 	} else {
-		db.logger.Infof("database capacity: %d chunks (approximately %0.1fGB)", db.capacity, capacityMB/1000)
+		capacityMB := float64(db.capacity*swarm.ChunkSize) * 9.5367431640625e-7
+
+		if capacityMB <= 1000 {
+			db.logger.Infof("database capacity: %d chunks in gc index (approximately %fMB)", db.capacity, capacityMB)
+		} else {
+			db.logger.Infof("database capacity: %d chunks in gc index (approximately %0.1fGB)", db.capacity, capacityMB/1000)
+		}
 	}
 
 	if maxParallelUpdateGC > 0 {
@@ -184,6 +295,35 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 		return nil, err
 	}
 
+	db.baseKeyField, err = db.shed.NewStringField("base-key")
+	if err != nil {
+		return nil, err
+	}
+	if len(baseKey) == 0 && path != "" {
+		k, err := db.baseKeyField.Get()
+		if err != nil {
+			return nil, err
+		}
+		if k == "" {
+			db.shed.Close()
+			return nil, ErrBaseKeyUnknown
+		}
+		db.baseKey = []byte(k)
+	} else if err := db.baseKeyField.Put(string(baseKey)); err != nil {
+		return nil, err
+	}
+
+	var blobsPath string
+	if path != "" {
+		blobsPath = filepath.Join(path, blobsDir)
+	}
+	db.blobs, err = blobstore.New(blobsPath, blobstore.Options{
+		MaxDataSize: maxChunkDataSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: %w", err)
+	}
+
 	// Identify current storage schema by arbitrary name.
 	db.schemaName, err = db.shed.NewStringField("schema-name")
 	if err != nil {
@@ -213,34 +353,20 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 		return nil, err
 	}
 
-	// Index storing actual chunk address, data and bin id.
-	db.retrievalDataIndex, err = db.shed.NewIndex("Address->StoreTimestamp|BinID|Data", shed.IndexFuncs{
-		EncodeKey: func(fields shed.Item) (key []byte, err error) {
-			return fields.Address, nil
-		},
-		DecodeKey: func(key []byte) (e shed.Item, err error) {
-			e.Address = key
-			return e, nil
-		},
-		EncodeValue: func(fields shed.Item) (value []byte, err error) {
-			b := make([]byte, 16)
-			binary.BigEndian.PutUint64(b[:8], fields.BinID)
-			binary.BigEndian.PutUint64(b[8:16], uint64(fields.StoreTimestamp))
-			value = append(b, fields.Data...)
-			return value, nil
-		},
-		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
-			e.StoreTimestamp = int64(binary.BigEndian.Uint64(value[8:16]))
-			e.BinID = binary.BigEndian.Uint64(value[:8])
-			e.Data = value[16:]
-			return e, nil
-		},
-	})
+	// Persist the name of the gc policy.
+	db.gcPolicyName, err = db.shed.NewStringField("gc-policy")
+	if err != nil {
+		return nil, err
+	}
+
+	// Index storing actual chunk address, data location and bin id.
+	db.retrievalDataIndex, err = db.newRetrievalDataIndex()
 	if err != nil {
 		return nil, err
 	}
-	// Index storing access timestamp for a particular address.
-	// It is needed in order to update gc index keys for iteration order.
+	// Index storing access timestamp and access count for a particular
+	// address. It is needed in order to update gc index keys for iteration
+	// order. The access count is not stored by older versions.
 	db.retrievalAccessIndex, err = db.shed.NewIndex("Address->AccessTimestamp", shed.IndexFuncs{
 		EncodeKey: func(fields shed.Item) (key []byte, err error) {
 			return fields.Address, nil
@@ -250,12 +376,16 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 			return e, nil
 		},
 		EncodeValue: func(fields shed.Item) (value []byte, err error) {
-			b := make([]byte, 8)
-			binary.BigEndian.PutUint64(b, uint64(fields.AccessTimestamp))
+			b := make([]byte, 16)
+			binary.BigEndian.PutUint64(b[:8], uint64(fields.AccessTimestamp))
+			binary.BigEndian.PutUint64(b[8:16], fields.AccessCount)
 			return b, nil
 		},
 		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
-			e.AccessTimestamp = int64(binary.BigEndian.Uint64(value))
+			e.AccessTimestamp = int64(binary.BigEndian.Uint64(value[:8]))
+			if len(value) >= 16 {
+				e.AccessCount = binary.BigEndian.Uint64(value[8:16])
+			}
 			return e, nil
 		},
 	})
@@ -333,18 +463,15 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 	// create a push syncing triggers used by SubscribePush function
 	db.pushTriggers = make([]chan struct{}, 0)
 	// gc index for removable chunk ordered by ascending last access time
+	// The gc index key is the gc policy key followed by the chunk address,
+	// the index name is kept from when the key was always the access
+	// timestamp and bin id, as in the LRU policy.
 	db.gcIndex, err = db.shed.NewIndex("AccessTimestamp|BinID|Hash->nil", shed.IndexFuncs{
 		EncodeKey: func(fields shed.Item) (key []byte, err error) {
-			b := make([]byte, 16, 16+len(fields.Address))
-			binary.BigEndian.PutUint64(b[:8], uint64(fields.AccessTimestamp))
-			binary.BigEndian.PutUint64(b[8:16], fields.BinID)
-			key = append(b, fields.Address...)
-			return key, nil
+			return db.gcKey(fields), nil
 		},
 		DecodeKey: func(key []byte) (e shed.Item, err error) {
-			e.AccessTimestamp = int64(binary.BigEndian.Uint64(key[:8]))
-			e.BinID = binary.BigEndian.Uint64(key[8:16])
-			e.Address = key[16:]
+			e.Address = key[len(key)-swarm.HashSize:]
 			return e, nil
 		},
 		EncodeValue: func(fields shed.Item) (value []byte, err error) {
@@ -381,6 +508,50 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 		return nil, err
 	}
 
+	// Create a index structure for named pins with the name as the
+	// Address and the pin encoded by pinToItem as the Data
+	db.pinsIndex, err = db.shed.NewIndex("Name->Root|Owner|Created|Expires", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			return fields.Address, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.Address = key
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			return fields.Data, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			e.Data = value
+			return e, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a index structure for pins that are being created or removed
+	// with the pin name as the Address and the encoded pendingPin as the Data
+	db.pendingPinsIndex, err = db.shed.NewIndex("Name->Root|Pinned", shed.IndexFuncs{
+		EncodeKey: func(fields shed.Item) (key []byte, err error) {
+			return fields.Address, nil
+		},
+		DecodeKey: func(key []byte) (e shed.Item, err error) {
+			e.Address = key
+			return e, nil
+		},
+		EncodeValue: func(fields shed.Item) (value []byte, err error) {
+			return fields.Data, nil
+		},
+		DecodeValue: func(keyItem shed.Item, value []byte) (e shed.Item, err error) {
+			e.Data = value
+			return e, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a index structure for excluding pinned chunks from gcIndex
 	db.gcExcludeIndex, err = db.shed.NewIndex("Hash->nil", shed.IndexFuncs{
 		EncodeKey: func(fields shed.Item) (key []byte, err error) {
@@ -401,8 +572,47 @@ func New(path string, baseKey []byte, o *Options, logger logging.Logger) (db *DB
 		return nil, err
 	}
 
-	// start garbage collection worker
-	go db.collectGarbageWorker()
+	// find free chunk data slots if the database was not closed
+	if err := db.recoverBlobs(); err != nil {
+		return nil, fmt.Errorf("blobstore recovery: %w", err)
+	}
+
+	// rebuild the gc index if the policy is changed
+	if err := db.migrateGCPolicy(); err != nil {
+		return nil, fmt.Errorf("gc policy migration: %w", err)
+	}
+
+	// unpin chunks of pins that were not created or removed completely
+	if err := db.rollbackPendingPins(); err != nil {
+		return nil, fmt.Errorf("pending pins: %w", err)
+	}
+
+	if !o.DisableGarbageCollection && !o.DisableBackgroundWorkers {
+		// start garbage collection worker
+		go db.collectGarbageWorker()
+	} else {
+		close(db.collectGarbageWorkerDone)
+	}
+
+	if path != "" && !o.DisableBackgroundWorkers {
+		// start checking disk usage and free disk space
+		go db.diskWatchdog()
+	} else {
+		close(db.diskWatchdogDone)
+	}
+
+	if o.IntegrityCheckInterval > 0 && !o.DisableBackgroundWorkers {
+		go db.integrityCheckWorker(o.IntegrityCheckInterval)
+	} else {
+		close(db.integrityCheckDone)
+	}
+
+	if !o.DisableBackgroundWorkers {
+		// start removing expired pins
+		go db.pinExpiryWorker()
+	} else {
+		close(db.pinExpiryDone)
+	}
 	return db, nil
 }
 
@@ -418,6 +628,9 @@ func (db *DB) Close() (err error) {
 		// wait for gc worker to
 		// return before closing the shed
 		<-db.collectGarbageWorkerDone
+		<-db.diskWatchdogDone
+		<-db.integrityCheckDone
+		<-db.pinExpiryDone
 		close(done)
 	}()
 	select {
@@ -432,9 +645,49 @@ func (db *DB) Close() (err error) {
 			return err
 		}
 	}
+	if err := db.blobs.Close(); err != nil {
+		db.shed.Close()
+		return err
+	}
 	return db.shed.Close()
 }
 
+// Capacity returns the number of chunks in the garbage collection
+// index at which the garbage collection is triggered.
+func (db *DB) Capacity() uint64 {
+	return db.capacity
+}
+
+// DiskCapacity returns the number of bytes of disk usage at which
+// the garbage collection is triggered, zero if it is not limited.
+func (db *DB) DiskCapacity() uint64 {
+	return db.diskCapacity
+}
+
+// GCSize returns the number of chunks in the garbage collection index,
+// which are counted against the capacity. It returns an error if the
+// database is closed.
+func (db *DB) GCSize() (uint64, error) {
+	return db.gcSize.Get()
+}
+
+// BaseKey returns the base key that proximity orders of chunks
+// are computed against.
+func (db *DB) BaseKey() []byte {
+	return db.baseKey
+}
+
+// SchemaName returns the name of the current database schema.
+func (db *DB) SchemaName() (string, error) {
+	return db.schemaName.Get()
+}
+
+// Compact compacts the underlying database to
+// reclaim space of removed entries.
+func (db *DB) Compact() error {
+	return db.shed.Compact()
+}
+
 // po computes the proximity order between the address
 // and database base key.
 func (db *DB) po(addr swarm.Address) (bin uint8) {
@@ -445,15 +698,7 @@ func (db *DB) po(addr swarm.Address) (bin uint8) {
 // the returned map keys are the index name, values are the number of elements in the index
 func (db *DB) DebugIndices() (indexInfo map[string]int, err error) {
 	indexInfo = make(map[string]int)
-	for k, v := range map[string]shed.Index{
-		"retrievalDataIndex":   db.retrievalDataIndex,
-		"retrievalAccessIndex": db.retrievalAccessIndex,
-		"pushIndex":            db.pushIndex,
-		"pullIndex":            db.pullIndex,
-		"gcIndex":              db.gcIndex,
-		"gcExcludeIndex":       db.gcExcludeIndex,
-		"pinIndex":             db.pinIndex,
-	} {
+	for k, v := range db.indexes() {
 		indexSize, err := v.Count()
 		if err != nil {
 			return indexInfo, err
@@ -469,6 +714,20 @@ func (db *DB) DebugIndices() (indexInfo map[string]int, err error) {
 	return indexInfo, err
 }
 
+// indexes returns all indexes in localstore by their names.
+func (db *DB) indexes() map[string]shed.Index {
+	return map[string]shed.Index{
+		"retrievalDataIndex":   db.retrievalDataIndex,
+		"retrievalAccessIndex": db.retrievalAccessIndex,
+		"pushIndex":            db.pushIndex,
+		"pullIndex":            db.pullIndex,
+		"gcIndex":              db.gcIndex,
+		"gcExcludeIndex":       db.gcExcludeIndex,
+		"pinIndex":             db.pinIndex,
+		"pinsIndex":            db.pinsIndex,
+	}
+}
+
 // chunkToItem creates new Item with data provided by the Chunk.
 func chunkToItem(ch swarm.Chunk) shed.Item {
 	return shed.Item{
@@ -62,6 +62,24 @@ type metrics struct {
 	GCSize                  prometheus.Gauge
 	GCStoreTimeStamps       prometheus.Gauge
 	GCStoreAccessTimeStamps prometheus.Gauge
+	GCReserveRadius         prometheus.Gauge
+
+	DiskUsage    prometheus.Gauge
+	DiskFree     prometheus.Gauge
+	LowDiskSpace prometheus.Gauge
+
+	CacheHit              prometheus.Counter
+	CacheMiss             prometheus.Counter
+	CacheSize             prometheus.Gauge
+	CacheAccessFlush      prometheus.Counter
+	CacheAccessFlushError prometheus.Counter
+
+	IntegrityCheck      prometheus.Counter
+	IntegrityCheckError prometheus.Counter
+	IntegrityRepairs    prometheus.Counter
+
+	ExpiredPins    prometheus.Counter
+	PinExpiryError prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -340,6 +358,92 @@ func newMetrics() metrics {
 			Name:      "gc_access_time_stamp",
 			Help:      "Access timestamp in Garbage collection iteration.",
 		}),
+		GCReserveRadius: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "gc_reserve_radius",
+			Help:      "Proximity order of chunks protected from Garbage collection.",
+		}),
+
+		DiskUsage: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "disk_usage_bytes",
+			Help:      "Size of database files in bytes.",
+		}),
+		DiskFree: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "disk_free_bytes",
+			Help:      "Free disk space in bytes on the database file system.",
+		}),
+		LowDiskSpace: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "low_disk_space",
+			Help:      "Set to 1 when uploads are refused because of low free disk space.",
+		}),
+
+		CacheHit: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "cache_hit_count",
+			Help:      "Number of chunks returned from the in-memory cache, also counted as gets.",
+		}),
+		CacheMiss: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "cache_miss_count",
+			Help:      "Number of chunks not found in the in-memory cache.",
+		}),
+		CacheSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "cache_size",
+			Help:      "Number of chunks in the in-memory cache.",
+		}),
+		CacheAccessFlush: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "cache_access_flush_count",
+			Help:      "Number of writes of cached chunk accesses to the gc index.",
+		}),
+		CacheAccessFlushError: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "cache_access_flush_error_count",
+			Help:      "Number of errors writing cached chunk accesses to the gc index.",
+		}),
+		IntegrityCheck: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "integrity_check_count",
+			Help:      "Number of background integrity checks.",
+		}),
+		IntegrityCheckError: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "integrity_check_error_count",
+			Help:      "Number of failed background integrity checks.",
+		}),
+		IntegrityRepairs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "integrity_repairs_count",
+			Help:      "Number of repaired chunks and index inconsistencies.",
+		}),
+		ExpiredPins: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "expired_pins_count",
+			Help:      "Number of removed expired pins.",
+		}),
+		PinExpiryError: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "pin_expiry_error_count",
+			Help:      "Number of errors removing expired pins.",
+		}),
 	}
 }
 
@@ -19,6 +19,9 @@ package localstore
 import (
 	"errors"
 	"fmt"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 var errMissingCurrentSchema = errors.New("could not find current db schema")
@@ -33,6 +36,7 @@ type migration struct {
 // in order to run data migrations in the correct sequence
 var schemaMigrations = []migration{
 	{name: DbSchemaCode, fn: func(db *DB) error { return nil }},
+	{name: DbSchemaBlobstore, fn: migrateBlobstore},
 }
 
 func (db *DB) migrate(schemaName string) error {
@@ -98,3 +102,39 @@ func getMigrations(currentSchema, targetSchema string, allSchemeMigrations []mig
 	}
 	return migrations, nil
 }
+
+// migrationBatchSize limits the number of index
+// items in a single batch in data migrations.
+var migrationBatchSize = 10000
+
+// inBatches calls the function for every item of the index, writing the
+// batch after every migrationBatchSize items. The function is called
+// on a snapshot, so it may change the index. If beforeWrite is not nil,
+// it is called before every batch is written.
+func inBatches(db *DB, index shed.Index, fn func(batch *leveldb.Batch, item shed.Item) error, beforeWrite func() error) error {
+	for {
+		batch := new(leveldb.Batch)
+		var n int
+		err := index.Iterate(func(item shed.Item) (stop bool, err error) {
+			if err := fn(batch, item); err != nil {
+				return true, err
+			}
+			n++
+			return n >= migrationBatchSize, nil
+		}, nil)
+		if err != nil {
+			return err
+		}
+		if beforeWrite != nil {
+			if err := beforeWrite(); err != nil {
+				return err
+			}
+		}
+		if err := db.shed.WriteBatch(batch); err != nil {
+			return err
+		}
+		if n < migrationBatchSize {
+			return nil
+		}
+	}
+}
@@ -62,6 +62,10 @@ func (db *DB) get(mode storage.ModeGet, addr swarm.Address) (out shed.Item, err
 	if err != nil {
 		return out, err
 	}
+	out, err = db.readData(out)
+	if err != nil {
+		return out, err
+	}
 	switch mode {
 	// update the access timestamp and gc index
 	case storage.ModeGetRequest:
@@ -119,21 +123,33 @@ func (db *DB) updateGCItems(items ...shed.Item) {
 }
 
 // updateGC updates garbage collection index for
-// a single item. Provided item is expected to have
-// only Address and Data fields with non zero values,
-// which is ensured by the get function.
-func (db *DB) updateGC(item shed.Item) (err error) {
+// the items in a single batch. Provided items are expected
+// to have only Address, Data and BinID fields with non zero
+// values, which is ensured by the get function.
+func (db *DB) updateGC(items ...shed.Item) (err error) {
 	db.batchMu.Lock()
 	defer db.batchMu.Unlock()
 
 	batch := new(leveldb.Batch)
+	for _, item := range items {
+		if err := db.updateGCInBatch(batch, item); err != nil {
+			return err
+		}
+	}
+	return db.shed.WriteBatch(batch)
+}
 
+// updateGCInBatch updates access timestamp in retrieve
+// access and gc indexes for a single item.
+// Provided batch is updated.
+func (db *DB) updateGCInBatch(batch *leveldb.Batch, item shed.Item) (err error) {
 	// update accessTimeStamp in retrieve, gc
 
 	i, err := db.retrievalAccessIndex.Get(item)
 	switch {
 	case err == nil:
 		item.AccessTimestamp = i.AccessTimestamp
+		item.AccessCount = i.AccessCount
 	case errors.Is(err, leveldb.ErrNotFound):
 		// no chunk accesses
 	default:
@@ -144,13 +160,19 @@ func (db *DB) updateGC(item shed.Item) (err error) {
 		// do not add it to the gc index
 		return nil
 	}
+	// the gc policy may not change the gc index on access
+	accessed, update := db.gcPolicy.Access(db.gcItem(item), now())
+	if !update {
+		return nil
+	}
 	// delete current entry from the gc index
 	err = db.gcIndex.DeleteInBatch(batch, item)
 	if err != nil {
 		return err
 	}
-	// update access timestamp
-	item.AccessTimestamp = now()
+	// update access timestamp and count
+	item.AccessTimestamp = accessed.AccessTimestamp
+	item.AccessCount = accessed.AccessCount
 	// update retrieve access index
 	err = db.retrievalAccessIndex.PutInBatch(batch, item)
 	if err != nil {
@@ -169,7 +191,7 @@ func (db *DB) updateGC(item shed.Item) (err error) {
 		}
 	}
 
-	return db.shed.WriteBatch(batch)
+	return nil
 }
 
 // testHookUpdateGC is a hook that can provide
@@ -67,6 +67,12 @@ func (db *DB) getMulti(mode storage.ModeGet, addrs ...swarm.Address) (out []shed
 	if err != nil {
 		return nil, err
 	}
+	for i := range out {
+		out[i], err = db.readData(out[i])
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	switch mode {
 	// update the access timestamp and gc index
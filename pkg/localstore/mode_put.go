@@ -19,8 +19,10 @@ package localstore
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/blobstore"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -72,6 +74,14 @@ func (db *DB) put(mode storage.ModePut, chs ...swarm.Chunk) (exist []bool, err e
 	// Values from this map are stored with the batch
 	binIDs := make(map[uint8]uint64)
 
+	// chunk data locations to be released if the batch is not written
+	var written []blobstore.Location
+	defer func() {
+		if err != nil {
+			db.releaseLocations(written)
+		}
+	}()
+
 	switch mode {
 	case storage.ModePutRequest:
 		for i, ch := range chs {
@@ -79,7 +89,7 @@ func (db *DB) put(mode storage.ModePut, chs ...swarm.Chunk) (exist []bool, err e
 				exist[i] = true
 				continue
 			}
-			exists, c, err := db.putRequest(batch, binIDs, chunkToItem(ch))
+			exists, c, err := db.putRequest(batch, binIDs, &written, chunkToItem(ch))
 			if err != nil {
 				return nil, err
 			}
@@ -88,12 +98,15 @@ func (db *DB) put(mode storage.ModePut, chs ...swarm.Chunk) (exist []bool, err e
 		}
 
 	case storage.ModePutUpload:
+		if db.lowDiskSpace {
+			return nil, storage.ErrInsufficientSpace
+		}
 		for i, ch := range chs {
 			if containsChunk(ch.Address(), chs[:i]...) {
 				exist[i] = true
 				continue
 			}
-			exists, c, err := db.putUpload(batch, binIDs, chunkToItem(ch))
+			exists, c, err := db.putUpload(batch, binIDs, &written, chunkToItem(ch))
 			if err != nil {
 				return nil, err
 			}
@@ -113,7 +126,7 @@ func (db *DB) put(mode storage.ModePut, chs ...swarm.Chunk) (exist []bool, err e
 				exist[i] = true
 				continue
 			}
-			exists, c, err := db.putSync(batch, binIDs, chunkToItem(ch))
+			exists, c, err := db.putSync(batch, binIDs, &written, chunkToItem(ch))
 			if err != nil {
 				return nil, err
 			}
@@ -139,6 +152,15 @@ func (db *DB) put(mode storage.ModePut, chs ...swarm.Chunk) (exist []bool, err e
 		return nil, err
 	}
 
+	// chunk data must be stored before the
+	// batch with its locations is written
+	if len(written) > 0 {
+		err = db.blobs.Sync()
+		if err != nil {
+			return nil, fmt.Errorf("sync chunk data: %w", err)
+		}
+	}
+
 	err = db.shed.WriteBatch(batch)
 	if err != nil {
 		return nil, err
@@ -158,16 +180,21 @@ func (db *DB) put(mode storage.ModePut, chs ...swarm.Chunk) (exist []bool, err e
 //  - it does not enter the syncpool
 // The batch can be written to the database.
 // Provided batch and binID map are updated.
-func (db *DB) putRequest(batch *leveldb.Batch, binIDs map[uint8]uint64, item shed.Item) (exists bool, gcSizeChange int64, err error) {
+func (db *DB) putRequest(batch *leveldb.Batch, binIDs map[uint8]uint64, written *[]blobstore.Location, item shed.Item) (exists bool, gcSizeChange int64, err error) {
 	i, err := db.retrievalDataIndex.Get(item)
 	switch {
 	case err == nil:
 		exists = true
 		item.StoreTimestamp = i.StoreTimestamp
 		item.BinID = i.BinID
+		item.Location = i.Location
 	case errors.Is(err, leveldb.ErrNotFound):
 		// no chunk accesses
 		exists = false
+		item, err = db.writeData(item, written)
+		if err != nil {
+			return false, 0, err
+		}
 	default:
 		return false, 0, err
 	}
@@ -198,7 +225,7 @@ func (db *DB) putRequest(batch *leveldb.Batch, binIDs map[uint8]uint64, item she
 //  - put to indexes: retrieve, push, pull
 // The batch can be written to the database.
 // Provided batch and binID map are updated.
-func (db *DB) putUpload(batch *leveldb.Batch, binIDs map[uint8]uint64, item shed.Item) (exists bool, gcSizeChange int64, err error) {
+func (db *DB) putUpload(batch *leveldb.Batch, binIDs map[uint8]uint64, written *[]blobstore.Location, item shed.Item) (exists bool, gcSizeChange int64, err error) {
 	exists, err = db.retrievalDataIndex.Has(item)
 	if err != nil {
 		return false, 0, err
@@ -220,6 +247,10 @@ func (db *DB) putUpload(batch *leveldb.Batch, binIDs map[uint8]uint64, item shed
 	if err != nil {
 		return false, 0, err
 	}
+	item, err = db.writeData(item, written)
+	if err != nil {
+		return false, 0, err
+	}
 	err = db.retrievalDataIndex.PutInBatch(batch, item)
 	if err != nil {
 		return false, 0, err
@@ -242,7 +273,7 @@ func (db *DB) putUpload(batch *leveldb.Batch, binIDs map[uint8]uint64, item shed
 //  - put to indexes: retrieve, pull
 // The batch can be written to the database.
 // Provided batch and binID map are updated.
-func (db *DB) putSync(batch *leveldb.Batch, binIDs map[uint8]uint64, item shed.Item) (exists bool, gcSizeChange int64, err error) {
+func (db *DB) putSync(batch *leveldb.Batch, binIDs map[uint8]uint64, written *[]blobstore.Location, item shed.Item) (exists bool, gcSizeChange int64, err error) {
 	exists, err = db.retrievalDataIndex.Has(item)
 	if err != nil {
 		return false, 0, err
@@ -256,6 +287,10 @@ func (db *DB) putSync(batch *leveldb.Batch, binIDs map[uint8]uint64, item shed.I
 	if err != nil {
 		return false, 0, err
 	}
+	item, err = db.writeData(item, written)
+	if err != nil {
+		return false, 0, err
+	}
 	err = db.retrievalDataIndex.PutInBatch(batch, item)
 	if err != nil {
 		return false, 0, err
@@ -286,6 +321,7 @@ func (db *DB) setGC(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, e
 	switch {
 	case err == nil:
 		item.AccessTimestamp = i.AccessTimestamp
+		item.AccessCount = i.AccessCount
 		err = db.gcIndex.DeleteInBatch(batch, item)
 		if err != nil {
 			return 0, err
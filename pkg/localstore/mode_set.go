@@ -23,6 +23,7 @@ import (
 
 	"github.com/syndtr/goleveldb/leveldb"
 
+	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
@@ -57,6 +58,7 @@ func (db *DB) set(mode storage.ModeSet, addrs ...swarm.Address) (err error) {
 	// to be done after write batch function successfully executes
 	var gcSizeChange int64                      // number to add or subtract from gcSize
 	triggerPullFeed := make(map[uint8]struct{}) // signal pull feed subscriptions to iterate
+	var removed []shed.Item                     // release chunk data of removed chunks
 
 	switch mode {
 	case storage.ModeSetAccess:
@@ -88,10 +90,11 @@ func (db *DB) set(mode storage.ModeSet, addrs ...swarm.Address) (err error) {
 
 	case storage.ModeSetRemove:
 		for _, addr := range addrs {
-			c, err := db.setRemove(batch, addr)
+			item, c, err := db.setRemove(batch, addr)
 			if err != nil {
 				return err
 			}
+			removed = append(removed, item)
 			gcSizeChange += c
 		}
 
@@ -104,10 +107,11 @@ func (db *DB) set(mode storage.ModeSet, addrs ...swarm.Address) (err error) {
 		}
 	case storage.ModeSetUnpin:
 		for _, addr := range addrs {
-			err := db.setUnpin(batch, addr)
+			c, err := db.setUnpin(batch, addr)
 			if err != nil {
 				return err
 			}
+			gcSizeChange += c
 		}
 
 	default:
@@ -126,6 +130,7 @@ func (db *DB) set(mode storage.ModeSet, addrs ...swarm.Address) (err error) {
 	for po := range triggerPullFeed {
 		db.triggerPullSubscriptions(po)
 	}
+	db.chunksRemoved(removed...)
 	return nil
 }
 
@@ -162,6 +167,7 @@ func (db *DB) setAccess(batch *leveldb.Batch, binIDs map[uint8]uint64, addr swar
 	switch {
 	case err == nil:
 		item.AccessTimestamp = i.AccessTimestamp
+		item.AccessCount = i.AccessCount
 		err = db.gcIndex.DeleteInBatch(batch, item)
 		if err != nil {
 			return 0, err
@@ -306,6 +312,7 @@ func (db *DB) setSync(batch *leveldb.Batch, addr swarm.Address, mode storage.Mod
 	switch {
 	case err == nil:
 		item.AccessTimestamp = i.AccessTimestamp
+		item.AccessCount = i.AccessCount
 		err = db.gcIndex.DeleteInBatch(batch, item)
 		if err != nil {
 			return 0, err
@@ -340,8 +347,9 @@ func (db *DB) setSync(batch *leveldb.Batch, addr swarm.Address, mode storage.Mod
 
 // setRemove removes the chunk by updating indexes:
 //  - delete from retrieve, pull, gc
-// Provided batch is updated.
-func (db *DB) setRemove(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange int64, err error) {
+// Provided batch is updated. The removed item is returned
+// to release its data after the batch is written.
+func (db *DB) setRemove(batch *leveldb.Batch, addr swarm.Address) (removed shed.Item, gcSizeChange int64, err error) {
 	item := addressToItem(addr)
 
 	// need to get access timestamp here as it is not
@@ -351,32 +359,34 @@ func (db *DB) setRemove(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange
 	switch {
 	case err == nil:
 		item.AccessTimestamp = i.AccessTimestamp
+		item.AccessCount = i.AccessCount
 	case errors.Is(err, leveldb.ErrNotFound):
 	default:
-		return 0, err
+		return shed.Item{}, 0, err
 	}
 	i, err = db.retrievalDataIndex.Get(item)
 	if err != nil {
-		return 0, err
+		return shed.Item{}, 0, err
 	}
 	item.StoreTimestamp = i.StoreTimestamp
 	item.BinID = i.BinID
+	item.Location = i.Location
 
 	err = db.retrievalDataIndex.DeleteInBatch(batch, item)
 	if err != nil {
-		return 0, err
+		return shed.Item{}, 0, err
 	}
 	err = db.retrievalAccessIndex.DeleteInBatch(batch, item)
 	if err != nil {
-		return 0, err
+		return shed.Item{}, 0, err
 	}
 	err = db.pullIndex.DeleteInBatch(batch, item)
 	if err != nil {
-		return 0, err
+		return shed.Item{}, 0, err
 	}
 	err = db.gcIndex.DeleteInBatch(batch, item)
 	if err != nil {
-		return 0, err
+		return shed.Item{}, 0, err
 	}
 	// a check is needed for decrementing gcSize
 	// as delete is not reporting if the key/value pair
@@ -385,7 +395,7 @@ func (db *DB) setRemove(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange
 		gcSizeChange = -1
 	}
 
-	return gcSizeChange, nil
+	return item, gcSizeChange, nil
 }
 
 // setPin increments pin counter for the chunk by updating
@@ -425,14 +435,16 @@ func (db *DB) setPin(batch *leveldb.Batch, addr swarm.Address) (err error) {
 }
 
 // setUnpin decrements pin counter for the chunk by updating pin index.
-// Provided batch is updated.
-func (db *DB) setUnpin(batch *leveldb.Batch, addr swarm.Address) (err error) {
+// When the chunk is not pinned anymore, it is added back to the gc index
+// if it has an access timestamp, which chunks that are not synced yet do
+// not have. Provided batch is updated.
+func (db *DB) setUnpin(batch *leveldb.Batch, addr swarm.Address) (gcSizeChange int64, err error) {
 	item := addressToItem(addr)
 
 	// Get the existing pin counter of the chunk
 	pinnedChunk, err := db.pinIndex.Get(item)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Decrement the pin counter or
@@ -441,14 +453,38 @@ func (db *DB) setUnpin(batch *leveldb.Batch, addr swarm.Address) (err error) {
 		item.PinCounter = pinnedChunk.PinCounter - 1
 		err = db.pinIndex.PutInBatch(batch, item)
 		if err != nil {
-			return err
-		}
-	} else {
-		err = db.pinIndex.DeleteInBatch(batch, item)
-		if err != nil {
-			return err
+			return 0, err
 		}
+		return 0, nil
 	}
 
-	return nil
+	err = db.pinIndex.DeleteInBatch(batch, item)
+	if err != nil {
+		return 0, err
+	}
+	// the chunk may still be waiting to be removed from the gc index
+	err = db.gcExcludeIndex.DeleteInBatch(batch, item)
+	if err != nil {
+		return 0, err
+	}
+
+	item, err = db.fillGCItem(item)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	ok, err := db.gcIndex.Has(item)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return 0, nil
+	}
+	err = db.gcIndex.PutInBatch(batch, item)
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
 }
@@ -0,0 +1,526 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	// DefaultPinsLimit is the number of pins returned by Pins if no limit
+	// is given.
+	DefaultPinsLimit = 100
+	// MaxPinNameLength is the maximal length of a pin name in bytes.
+	MaxPinNameLength = 256
+)
+
+var (
+	// pinExpiryInterval is the interval at which expired pins are removed.
+	pinExpiryInterval = time.Minute
+	// pinBatchSize is the maximal number of chunks that are pinned
+	// or unpinned in a single batch, with the batchMu lock held.
+	pinBatchSize = 1000
+)
+
+var (
+	// ErrPinExists is returned when a pin is created with the
+	// name of an existing pin.
+	ErrPinExists = errors.New("pin exists")
+	// ErrInvalidPin is returned when a pin is created with
+	// an invalid name or root reference.
+	ErrInvalidPin = errors.New("invalid pin")
+)
+
+// Pin is a named pin of all chunks in the tree of a root reference.
+// Chunks of pinned trees are not garbage collected.
+type Pin struct {
+	Name string
+	Root swarm.Address
+	// Owner identifies who created the pin.
+	Owner   string
+	Created time.Time
+	// Expires is the time after which the pin is removed,
+	// zero if the pin does not expire.
+	Expires time.Time
+}
+
+// expired reports whether the pin is expired at the unix timestamp.
+func (p Pin) expired(t int64) bool {
+	return !p.Expires.IsZero() && p.Expires.UnixNano() <= t
+}
+
+// pinValue is the encoded value of the pins index.
+type pinValue struct {
+	Root    []byte `json:"root"`
+	Owner   string `json:"owner,omitempty"`
+	Created int64  `json:"created"`
+	Expires int64  `json:"expires,omitempty"`
+}
+
+// pendingPin is the encoded value of the pending pins index. It records
+// that the first Pinned chunks of the tree of the Root, in the order of
+// iterateTree, are pinned by a pin that is being created or removed.
+type pendingPin struct {
+	Root   []byte `json:"root"`
+	Pinned int    `json:"pinned"`
+}
+
+// pinToItem returns the pins index item with the pin name
+// as the Address and the encoded pin as the Data.
+func pinToItem(p Pin) (shed.Item, error) {
+	v := pinValue{
+		Root:    p.Root.Bytes(),
+		Owner:   p.Owner,
+		Created: p.Created.UnixNano(),
+	}
+	if !p.Expires.IsZero() {
+		v.Expires = p.Expires.UnixNano()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return shed.Item{}, err
+	}
+	return shed.Item{
+		Address: []byte(p.Name),
+		Data:    data,
+	}, nil
+}
+
+// itemToPin returns the pin of the pins index item.
+func itemToPin(item shed.Item) (p Pin, err error) {
+	var v pinValue
+	if err := json.Unmarshal(item.Data, &v); err != nil {
+		return p, fmt.Errorf("pin %q: %w", item.Address, err)
+	}
+	p = Pin{
+		Name:    string(item.Address),
+		Root:    swarm.NewAddress(v.Root),
+		Owner:   v.Owner,
+		Created: time.Unix(0, v.Created).UTC(),
+	}
+	if v.Expires != 0 {
+		p.Expires = time.Unix(0, v.Expires).UTC()
+	}
+	return p, nil
+}
+
+// CreatePin pins all chunks in the tree of the pin root reference and
+// stores the pin with its creation time. All chunks of the tree must be
+// stored. An expired pin with the same name is replaced.
+func (db *DB) CreatePin(p Pin) (Pin, error) {
+	if p.Name == "" || len(p.Name) > MaxPinNameLength {
+		return Pin{}, fmt.Errorf("%w: name length %d", ErrInvalidPin, len(p.Name))
+	}
+	if len(p.Root.Bytes()) != swarm.HashSize {
+		return Pin{}, fmt.Errorf("%w: root reference %s", ErrInvalidPin, p.Root)
+	}
+
+	db.pinsMu.Lock()
+	defer db.pinsMu.Unlock()
+
+	if err := db.rollbackPendingPins(); err != nil {
+		return Pin{}, err
+	}
+
+	t := now()
+	existing, err := db.getPin(p.Name)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+	case err != nil:
+		return Pin{}, err
+	case !existing.expired(t):
+		return Pin{}, ErrPinExists
+	default:
+		if err := db.removePin(existing); err != nil {
+			return Pin{}, err
+		}
+	}
+
+	p.Created = time.Unix(0, t).UTC()
+	item, err := pinToItem(p)
+	if err != nil {
+		return Pin{}, err
+	}
+
+	// every chunk is pinned once, even if it is referenced
+	// multiple times in the tree
+	addrs, err := db.treeAddresses(p.Root, false)
+	if err != nil {
+		return Pin{}, err
+	}
+	// the pin is stored with the last batch, so that it is
+	// not removed with chunks that are not pinned yet
+	err = db.pinChunks(p.Name, p.Root, addrs, func(batch *leveldb.Batch) error {
+		return db.pinsIndex.PutInBatch(batch, item)
+	})
+	if err != nil {
+		return Pin{}, err
+	}
+	return p, nil
+}
+
+// GetPin returns the pin with the name.
+// It returns storage.ErrNotFound if there is no such pin.
+func (db *DB) GetPin(name string) (Pin, error) {
+	return db.getPin(name)
+}
+
+func (db *DB) getPin(name string) (Pin, error) {
+	item, err := db.pinsIndex.Get(shed.Item{Address: []byte(name)})
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return Pin{}, storage.ErrNotFound
+		}
+		return Pin{}, err
+	}
+	return itemToPin(item)
+}
+
+// DeletePin removes the pin with the name and unpins all chunks in the
+// tree of its root reference. It returns storage.ErrNotFound if there is
+// no such pin.
+func (db *DB) DeletePin(name string) error {
+	db.pinsMu.Lock()
+	defer db.pinsMu.Unlock()
+
+	if err := db.rollbackPendingPins(); err != nil {
+		return err
+	}
+
+	p, err := db.getPin(name)
+	if err != nil {
+		return err
+	}
+	return db.removePin(p)
+}
+
+// removePin removes the pin and unpins the chunks of its tree. The pin
+// is removed with the first batch, and the chunks that are still pinned
+// are recorded as a pending pin, so that they are unpinned exactly once
+// even if the removal is interrupted. It must be called with pinsMu held.
+func (db *DB) removePin(p Pin) error {
+	// chunks that are removed are skipped,
+	// so that the pin can always be removed
+	addrs, err := db.treeAddresses(p.Root, true)
+	if err != nil {
+		return err
+	}
+	return db.unpinChunks(p.Name, p.Root, addrs, func(batch *leveldb.Batch) error {
+		return db.pinsIndex.DeleteInBatch(batch, shed.Item{Address: []byte(p.Name)})
+	})
+}
+
+// rollbackPendingPins unpins the chunks of pins that were being created or
+// removed when the operation was interrupted, so that no chunks are left
+// pinned without a pin that refers to them. Pins that were being created
+// are not stored and pins that were being removed are removed completely.
+// It must be called with pinsMu held or before the database is used.
+func (db *DB) rollbackPendingPins() error {
+	var items []shed.Item
+	err := db.pendingPinsIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		items = append(items, item)
+		return false, nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		var p pendingPin
+		if err := json.Unmarshal(item.Data, &p); err != nil {
+			return fmt.Errorf("pending pin %q: %w", item.Address, err)
+		}
+		root := swarm.NewAddress(p.Root)
+		addrs, err := db.treeAddresses(root, true)
+		if err != nil {
+			return fmt.Errorf("pending pin %q: %w", item.Address, err)
+		}
+		if len(addrs) > p.Pinned {
+			addrs = addrs[:p.Pinned]
+		}
+		if err := db.unpinChunks(string(item.Address), root, addrs, nil); err != nil {
+			return fmt.Errorf("pending pin %q: %w", item.Address, err)
+		}
+		db.logger.Debugf("localstore: unpinned %d chunks of pending pin %q", len(addrs), item.Address)
+	}
+	return nil
+}
+
+// putPendingPinInBatch records that the first pinned chunks of the
+// tree of the root are pinned by the pin with the name.
+func (db *DB) putPendingPinInBatch(batch *leveldb.Batch, name string, root swarm.Address, pinned int) error {
+	data, err := json.Marshal(pendingPin{Root: root.Bytes(), Pinned: pinned})
+	if err != nil {
+		return err
+	}
+	return db.pendingPinsIndex.PutInBatch(batch, shed.Item{
+		Address: []byte(name),
+		Data:    data,
+	})
+}
+
+// treeAddresses returns addresses of all stored chunks in the tree of
+// the root reference. If skipMissing is false, storage.ErrNotFound is
+// returned if a chunk is not stored.
+func (db *DB) treeAddresses(root swarm.Address, skipMissing bool) (addrs []swarm.Address, err error) {
+	err = db.iterateTree(root, skipMissing, func(item shed.Item) error {
+		addrs = append(addrs, swarm.NewAddress(item.Address))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+// pinChunks pins the chunks of the tree of the root for the pin with the
+// name in batches of at most pinBatchSize chunks, and calls last with the
+// last batch if it is not nil. Chunk pin counters are read from the
+// database, so every batch is written before the next one is built. The
+// number of pinned chunks is recorded as a pending pin with every batch but
+// the last one, so that they are unpinned if the pinning is interrupted. If
+// a chunk is not stored, the chunks of previous batches are unpinned and
+// storage.ErrNotFound is returned.
+func (db *DB) pinChunks(name string, root swarm.Address, addrs []swarm.Address, last func(batch *leveldb.Batch) error) error {
+	for i := 0; i < len(addrs); i += pinBatchSize {
+		end := i + pinBatchSize
+		if end > len(addrs) {
+			end = len(addrs)
+		}
+		err := db.pinBatch(addrs[i:end], func(batch *leveldb.Batch) error {
+			if end < len(addrs) {
+				return db.putPendingPinInBatch(batch, name, root, end)
+			}
+			if err := db.pendingPinsIndex.DeleteInBatch(batch, shed.Item{Address: []byte(name)}); err != nil {
+				return err
+			}
+			if last == nil {
+				return nil
+			}
+			return last(batch)
+		})
+		if err != nil {
+			if uerr := db.unpinChunks(name, root, addrs[:i], nil); uerr != nil {
+				return fmt.Errorf("%v: unpin chunks: %w", err, uerr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// pinBatch pins the chunks and calls fn, if it is not nil,
+// in a single batch with the batchMu lock held.
+func (db *DB) pinBatch(addrs []swarm.Address, fn func(batch *leveldb.Batch) error) error {
+	db.batchMu.Lock()
+	defer db.batchMu.Unlock()
+
+	batch := new(leveldb.Batch)
+	for _, addr := range addrs {
+		// chunks may be garbage collected after the tree is iterated
+		has, err := db.retrievalDataIndex.Has(addressToItem(addr))
+		if err != nil {
+			return err
+		}
+		if !has {
+			return fmt.Errorf("chunk %s: %w", addr, storage.ErrNotFound)
+		}
+		if err := db.setPin(batch, addr); err != nil {
+			return err
+		}
+	}
+	if fn != nil {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return db.shed.WriteBatch(batch)
+}
+
+// unpinChunks unpins the chunks of the tree of the root for the pin with
+// the name in batches of at most pinBatchSize chunks, from the last one,
+// and calls first with the first batch if it is not nil. The number of
+// chunks that are still pinned is recorded as a pending pin with every
+// batch, and the pending pin is removed with the last one. Chunks that are
+// removed or not pinned are skipped, and unpinned chunks are garbage
+// collected again.
+func (db *DB) unpinChunks(name string, root swarm.Address, addrs []swarm.Address, first func(batch *leveldb.Batch) error) error {
+	fn := first
+	for end := len(addrs); end > 0 || fn != nil; {
+		start := end - pinBatchSize
+		if start < 0 {
+			start = 0
+		}
+		err := db.unpinBatch(addrs[start:end], func(batch *leveldb.Batch) error {
+			if fn != nil {
+				if err := fn(batch); err != nil {
+					return err
+				}
+			}
+			if start == 0 {
+				return db.pendingPinsIndex.DeleteInBatch(batch, shed.Item{Address: []byte(name)})
+			}
+			return db.putPendingPinInBatch(batch, name, root, start)
+		})
+		if err != nil {
+			return err
+		}
+		fn = nil
+		end = start
+	}
+	return nil
+}
+
+// unpinBatch unpins the chunks and calls fn, if it is not nil,
+// in a single batch with the batchMu lock held.
+func (db *DB) unpinBatch(addrs []swarm.Address, fn func(batch *leveldb.Batch) error) error {
+	db.batchMu.Lock()
+	defer db.batchMu.Unlock()
+
+	batch := new(leveldb.Batch)
+	if fn != nil {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	var gcSizeChange int64
+	for _, addr := range addrs {
+		c, err := db.setUnpin(batch, addr)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+		gcSizeChange += c
+	}
+	if err := db.incGCSizeInBatch(batch, gcSizeChange); err != nil {
+		return err
+	}
+	return db.shed.WriteBatch(batch)
+}
+
+// PinsOptions select and paginate the pins returned by Pins.
+type PinsOptions struct {
+	// After is the name of the last pin of the previous page.
+	After string
+	// Limit is the maximal number of pins, DefaultPinsLimit if it is zero.
+	Limit int
+	// Prefix selects pins with names that start with it.
+	Prefix string
+	// Owner selects pins of the owner.
+	Owner string
+	// Root selects pins of the root reference.
+	Root swarm.Address
+}
+
+// Pins returns pins ordered by their names, selected by the options. The
+// returned next name is the After option for the next page, and it is
+// empty if there are no more pins.
+func (db *DB) Pins(o PinsOptions) (pins []Pin, next string, err error) {
+	limit := o.Limit
+	if limit <= 0 {
+		limit = DefaultPinsLimit
+	}
+
+	iterateOptions := &shed.IterateOptions{
+		Prefix: []byte(o.Prefix),
+	}
+	// names before the prefix are not iterated on
+	if o.After != "" && o.After >= o.Prefix {
+		iterateOptions.StartFrom = &shed.Item{Address: []byte(o.After)}
+		iterateOptions.SkipStartFromItem = true
+	}
+
+	err = db.pinsIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		p, err := itemToPin(item)
+		if err != nil {
+			return true, err
+		}
+		if o.Owner != "" && p.Owner != o.Owner {
+			return false, nil
+		}
+		if !o.Root.IsZero() && !p.Root.Equal(o.Root) {
+			return false, nil
+		}
+		if len(pins) == limit {
+			next = pins[len(pins)-1].Name
+			return true, nil
+		}
+		pins = append(pins, p)
+		return false, nil
+	}, iterateOptions)
+	if err != nil {
+		return nil, "", err
+	}
+	return pins, next, nil
+}
+
+// pinExpiryWorker removes expired pins until the database is closed.
+func (db *DB) pinExpiryWorker() {
+	defer close(db.pinExpiryDone)
+
+	ticker := time.NewTicker(pinExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-db.close:
+			return
+		}
+
+		count, err := db.removeExpiredPins()
+		if err != nil {
+			db.metrics.PinExpiryError.Inc()
+			db.logger.Debugf("localstore: remove expired pins: %v", err)
+			db.logger.Error("localstore: remove expired pins")
+		}
+		if count > 0 {
+			db.metrics.ExpiredPins.Add(float64(count))
+			db.logger.Debugf("localstore: removed %d expired pins", count)
+		}
+	}
+}
+
+// removeExpiredPins removes all expired pins and
+// returns the number of removed pins.
+func (db *DB) removeExpiredPins() (count int, err error) {
+	db.pinsMu.Lock()
+	defer db.pinsMu.Unlock()
+
+	if err := db.rollbackPendingPins(); err != nil {
+		return 0, err
+	}
+
+	t := now()
+	var expired []Pin
+	err = db.pinsIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		p, err := itemToPin(item)
+		if err != nil {
+			return true, err
+		}
+		if p.expired(t) {
+			expired = append(expired, p)
+		}
+		return false, nil
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range expired {
+		if err := db.removePin(p); err != nil {
+			return count, fmt.Errorf("pin %q: %w", p.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
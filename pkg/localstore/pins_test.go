@@ -0,0 +1,476 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/splitter"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// TestDB_pins validates that chunks of trees are pinned and unpinned
+// consistently with named pins, also if trees share chunks.
+func TestDB_pins(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	// trees with the same data chunk referenced multiple times
+	chunkData := make([]byte, swarm.ChunkSize)
+	if _, err := rand.Read(chunkData); err != nil {
+		t.Fatal(err)
+	}
+	var dataAddr swarm.Address
+	roots := make([]swarm.Address, 2)
+	for i := range roots {
+		data := bytes.Repeat(chunkData, i+2)
+		root, err := splitter.NewSimpleSplitter(db).Split(context.Background(), file.NewSimpleReadCloser(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots[i] = root
+		ch, err := db.Get(context.Background(), storage.ModeGetLookup, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dataAddr = swarm.NewAddress(ch.Data()[8 : 8+swarm.HashSize])
+	}
+
+	checkPinCounters := func(t *testing.T, want map[string]uint64) {
+		t.Helper()
+
+		for _, addr := range []swarm.Address{roots[0], roots[1], dataAddr} {
+			got, err := db.PinInfo(addr)
+			if errors.Is(err, storage.ErrNotFound) {
+				err = nil
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want[addr.String()] {
+				t.Errorf("chunk %s: got pin counter %v, want %v", addr, got, want[addr.String()])
+			}
+		}
+	}
+
+	p, err := db.CreatePin(Pin{Name: "first", Root: roots[0], Owner: "owner"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Created.IsZero() {
+		t.Error("pin creation time is not set")
+	}
+	if _, err := db.CreatePin(Pin{Name: "second", Root: roots[1]}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreatePin(Pin{Name: "third", Root: roots[1]}); err != nil {
+		t.Fatal(err)
+	}
+	checkPinCounters(t, map[string]uint64{
+		roots[0].String(): 1,
+		roots[1].String(): 2,
+		dataAddr.String(): 3,
+	})
+
+	if _, err := db.CreatePin(Pin{Name: "first", Root: roots[1]}); !errors.Is(err, ErrPinExists) {
+		t.Errorf("got error %v, want %v", err, ErrPinExists)
+	}
+	if _, err := db.CreatePin(Pin{Name: "missing", Root: generateTestRandomChunk().Address()}); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	if _, err := db.GetPin("missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+
+	got, err := db.GetPin("first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != p.Name || !got.Root.Equal(p.Root) || got.Owner != p.Owner || !got.Created.Equal(p.Created) || !got.Expires.IsZero() {
+		t.Errorf("got pin %+v, want %+v", got, p)
+	}
+
+	if err := db.DeletePin("second"); err != nil {
+		t.Fatal(err)
+	}
+	checkPinCounters(t, map[string]uint64{
+		roots[0].String(): 1,
+		roots[1].String(): 1,
+		dataAddr.String(): 2,
+	})
+
+	for _, name := range []string{"first", "third"} {
+		if err := db.DeletePin(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkPinCounters(t, nil)
+	t.Run("pin index count", newItemsCountTest(db.pinIndex, 0))
+	t.Run("pins index count", newItemsCountTest(db.pinsIndex, 0))
+
+	if err := db.DeletePin("first"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+// TestDB_Pins validates pagination and filters of the pins list.
+func TestDB_Pins(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	roots := []swarm.Address{splitTestData(t, db, 10), splitTestData(t, db, 10)}
+	for i := 0; i < 10; i++ {
+		_, err := db.CreatePin(Pin{
+			Name:  fmt.Sprintf("pin-%v", i),
+			Root:  roots[i%2],
+			Owner: fmt.Sprintf("owner-%v", i%3),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(pins []Pin) (names []string) {
+		for _, p := range pins {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+
+	for _, tc := range []struct {
+		name      string
+		options   PinsOptions
+		wantNames string
+		wantNext  string
+	}{
+		{
+			name:      "all",
+			wantNames: "[pin-0 pin-1 pin-2 pin-3 pin-4 pin-5 pin-6 pin-7 pin-8 pin-9]",
+		},
+		{
+			name:      "first page",
+			options:   PinsOptions{Limit: 4},
+			wantNames: "[pin-0 pin-1 pin-2 pin-3]",
+			wantNext:  "pin-3",
+		},
+		{
+			name:      "last page",
+			options:   PinsOptions{After: "pin-7", Limit: 4},
+			wantNames: "[pin-8 pin-9]",
+		},
+		{
+			name:      "owner",
+			options:   PinsOptions{Owner: "owner-0", Limit: 2},
+			wantNames: "[pin-0 pin-3]",
+			wantNext:  "pin-3",
+		},
+		{
+			name:      "root",
+			options:   PinsOptions{Root: roots[1], After: "pin-3"},
+			wantNames: "[pin-5 pin-7 pin-9]",
+		},
+		{
+			name:      "prefix",
+			options:   PinsOptions{Prefix: "pin-1"},
+			wantNames: "[pin-1]",
+		},
+		{
+			name:      "after before prefix",
+			options:   PinsOptions{Prefix: "pin-", After: "a"},
+			wantNames: "[pin-0 pin-1 pin-2 pin-3 pin-4 pin-5 pin-6 pin-7 pin-8 pin-9]",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pins, next, err := db.Pins(tc.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(names(pins)); got != tc.wantNames {
+				t.Errorf("got pins %v, want %v", got, tc.wantNames)
+			}
+			if next != tc.wantNext {
+				t.Errorf("got next %q, want %q", next, tc.wantNext)
+			}
+		})
+	}
+}
+
+// TestDB_pinExpiry validates that expired pins are removed
+// with pins of their chunks.
+func TestDB_pinExpiry(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	root := splitTestData(t, db, 10)
+
+	start := time.Now().UTC()
+	defer setNow(func() int64 { return start.UnixNano() })()
+
+	if _, err := db.CreatePin(Pin{Name: "expiring", Root: root, Expires: start.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreatePin(Pin{Name: "permanent", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := db.removeExpiredPins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("got %v removed pins, want 0", count)
+	}
+
+	setNow(func() int64 { return start.Add(time.Hour).UnixNano() })
+
+	// an expired pin is replaced
+	if _, err := db.CreatePin(Pin{Name: "expiring", Root: root, Expires: start.Add(2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	setNow(func() int64 { return start.Add(2 * time.Hour).UnixNano() })
+
+	count, err = db.removeExpiredPins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("got %v removed pins, want 1", count)
+	}
+	if _, err := db.GetPin("expiring"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	pinCounter, err := db.PinInfo(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinCounter != 1 {
+		t.Errorf("got pin counter %v, want 1", pinCounter)
+	}
+}
+
+// TestDB_pinsBatches validates that trees are pinned and unpinned in
+// multiple batches, and that chunks pinned in previous batches are
+// unpinned if a chunk is missing.
+func TestDB_pinsBatches(t *testing.T) {
+	defer func(s int) { pinBatchSize = s }(pinBatchSize)
+	pinBatchSize = 2
+
+	db := newTestDB(t, nil)
+
+	root := splitTestData(t, db, 4*swarm.ChunkSize)
+	addrs, err := db.treeAddresses(root, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 5 {
+		t.Fatalf("got %v chunks in the tree, want 5", len(addrs))
+	}
+
+	checkPinned := func(t *testing.T, addrs []swarm.Address, want bool) {
+		t.Helper()
+
+		for _, addr := range addrs {
+			_, err := db.PinInfo(addr)
+			if errors.Is(err, storage.ErrNotFound) {
+				err = nil
+				if want {
+					t.Errorf("chunk %s is not pinned", addr)
+				}
+			} else if !want {
+				t.Errorf("chunk %s is pinned", addr)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if _, err := db.CreatePin(Pin{Name: "pin", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	checkPinned(t, addrs, true)
+	if err := db.DeletePin("pin"); err != nil {
+		t.Fatal(err)
+	}
+	checkPinned(t, addrs, false)
+	t.Run("pins index count", newItemsCountTest(db.pinsIndex, 0))
+
+	missing := generateTestRandomChunk().Address()
+	err = db.pinChunks("pin", root, append(addrs[:3:3], missing), nil)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	checkPinned(t, addrs, false)
+	t.Run("pin index count", newItemsCountTest(db.pinIndex, 0))
+	t.Run("pending pins index count", newItemsCountTest(db.pendingPinsIndex, 0))
+}
+
+// TestDB_pendingPins validates that chunks pinned by pins that were not
+// created or removed completely are unpinned when the database is opened.
+func TestDB_pendingPins(t *testing.T) {
+	defer func(s int) { pinBatchSize = s }(pinBatchSize)
+	pinBatchSize = 2
+
+	dir, err := ioutil.TempDir("", "localstore-pins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logger := logging.New(ioutil.Discard, 0)
+
+	db, err := New(dir, make([]byte, 32), nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := splitTestData(t, db, 4*swarm.ChunkSize)
+	createdAddrs, err := db.treeAddresses(created, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed := splitTestData(t, db, 4*swarm.ChunkSize)
+	removedAddrs, err := db.treeAddresses(removed, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreatePin(Pin{Name: "removed", Root: removed}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the creation is interrupted after the first batch
+	err = db.pinBatch(createdAddrs[:2], func(batch *leveldb.Batch) error {
+		return db.putPendingPinInBatch(batch, "created", created, 2)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the removal is interrupted after the first batch
+	err = db.unpinBatch(removedAddrs[3:], func(batch *leveldb.Batch) error {
+		if err := db.pinsIndex.DeleteInBatch(batch, shed.Item{Address: []byte("removed")}); err != nil {
+			return err
+		}
+		return db.putPendingPinInBatch(batch, "removed", removed, 3)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("pending pins index count", newItemsCountTest(db.pendingPinsIndex, 2))
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = New(dir, make([]byte, 32), nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, addr := range append(createdAddrs, removedAddrs...) {
+		if _, err := db.PinInfo(addr); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("chunk %s: got error %v, want %v", addr, err, storage.ErrNotFound)
+		}
+	}
+	t.Run("pins index count", newItemsCountTest(db.pinsIndex, 0))
+	t.Run("pending pins index count", newItemsCountTest(db.pendingPinsIndex, 0))
+	t.Run("pin index count", newItemsCountTest(db.pinIndex, 0))
+	t.Run("gc size", newIndexGCSizeTest(db))
+}
+
+// TestDB_pinsGC validates that chunks of a tree are garbage
+// collected after its pin is deleted or expired.
+func TestDB_pinsGC(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		unpin func(t *testing.T, db *DB)
+	}{
+		{
+			name: "delete",
+			unpin: func(t *testing.T, db *DB) {
+				if err := db.DeletePin("pin"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "expiry",
+			unpin: func(t *testing.T, db *DB) {
+				defer setNow(func() int64 { return time.Now().Add(2 * time.Hour).UnixNano() })()
+
+				count, err := db.removeExpiredPins()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if count != 1 {
+					t.Fatalf("got %v removed pins, want 1", count)
+				}
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// chunks are collected only by the test
+			db := newTestDB(t, &Options{Capacity: 1, DisableGarbageCollection: true})
+
+			root := splitTestData(t, db, 3*swarm.ChunkSize)
+			if _, err := db.CreatePin(Pin{Name: "pin", Root: root, Expires: time.Now().Add(time.Hour)}); err != nil {
+				t.Fatal(err)
+			}
+			addrs, err := db.treeAddresses(root, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Set(context.Background(), storage.ModeSetSyncPush, addrs...); err != nil {
+				t.Fatal(err)
+			}
+			t.Run("gc index count", newItemsCountTest(db.gcIndex, 0))
+			t.Run("gc size", newIndexGCSizeTest(db))
+
+			tc.unpin(t, db)
+
+			t.Run("gc index count", newItemsCountTest(db.gcIndex, len(addrs)))
+			t.Run("gc exclude index count", newItemsCountTest(db.gcExcludeIndex, 0))
+			t.Run("gc size", newIndexGCSizeTest(db))
+
+			if _, err := db.CollectGarbage(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			for _, addr := range addrs {
+				has, err := db.Has(context.Background(), addr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if has {
+					t.Errorf("chunk %s is not garbage collected", addr)
+				}
+			}
+		})
+	}
+}
+
+// splitTestData stores random data of the size
+// and returns the root reference of its tree.
+func splitTestData(t *testing.T, db *DB, size int) swarm.Address {
+	t.Helper()
+
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	root, err := splitter.NewSimpleSplitter(db).Split(context.Background(), file.NewSimpleReadCloser(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
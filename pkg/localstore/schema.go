@@ -18,10 +18,14 @@ package localstore
 
 // The DB schema we want to use. The actual/current DB schema might differ
 // until migrations are run.
-var DbSchemaCurrent = DbSchemaCode
+var DbSchemaCurrent = DbSchemaBlobstore
 
 // There was a time when we had no schema at all.
 const DbSchemaNone = ""
 
 // DbSchemaCode is the first bee schema identifier
 const DbSchemaCode = "code"
+
+// DbSchemaBlobstore is the bee schema identifier with chunk
+// data stored in the blob store instead of leveldb
+const DbSchemaBlobstore = "blobstore"
@@ -0,0 +1,107 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"errors"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Stats holds the statistics of the database indexes
+// and the garbage collection.
+type Stats struct {
+	// Indexes holds the number of items by index name.
+	Indexes map[string]int
+	// Bins holds the statistics of every proximity order bin.
+	Bins []BinStats
+	// GCSize is the number of chunks in the gc index, the garbage
+	// collection is triggered when it reaches the Capacity and removes
+	// chunks until it is at the GCTarget.
+	GCSize   uint64
+	Capacity uint64
+	GCTarget uint64
+	// GCPolicy is the name of the garbage collection policy.
+	GCPolicy string
+	// PinnedChunks is the number of pinned chunks and PinCounters is the
+	// sum of their pin counters.
+	PinnedChunks uint64
+	PinCounters  uint64
+	// ReserveRadius is the proximity order of the reserve, ReserveSize
+	// is the number of chunks in bins at or above it.
+	ReserveRadius uint8
+	ReserveSize   uint64
+	GC            GCStats
+}
+
+// BinStats holds the statistics of a proximity order bin.
+type BinStats struct {
+	// Chunks is the number of chunks in the pull index.
+	Chunks uint64
+	// BinID is the last bin id assigned to a chunk.
+	BinID uint64
+}
+
+// Stats returns the statistics of the database indexes and the garbage
+// collection. It iterates over the pull and pin indexes, so it should not
+// be called frequently.
+func (db *DB) Stats() (stats Stats, err error) {
+	stats.Indexes = make(map[string]int)
+	for name, index := range db.indexes() {
+		count, err := index.Count()
+		if err != nil {
+			return Stats{}, err
+		}
+		stats.Indexes[name] = count
+	}
+
+	stats.Bins = make([]BinStats, swarm.MaxPO+1)
+	for bin := range stats.Bins {
+		binID, err := db.binIDs.Get(uint64(bin))
+		if err != nil {
+			return Stats{}, err
+		}
+		stats.Bins[bin].BinID = binID
+	}
+	err = db.pullIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		stats.Bins[db.po(swarm.NewAddress(item.Address))].Chunks++
+		return false, nil
+	}, nil)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats.GCSize, err = db.gcSize.Get()
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return Stats{}, err
+	}
+	stats.Capacity = db.capacity
+	stats.GCTarget = db.gcTarget()
+	stats.GCPolicy = db.gcPolicy.Name()
+
+	db.batchMu.Lock()
+	stats.ReserveRadius = db.reserveRadius
+	db.batchMu.Unlock()
+	for bin := int(stats.ReserveRadius); bin < len(stats.Bins); bin++ {
+		stats.ReserveSize += stats.Bins[bin].Chunks
+	}
+
+	err = db.pinIndex.Iterate(func(item shed.Item) (stop bool, err error) {
+		stats.PinnedChunks++
+		stats.PinCounters += item.PinCounter
+		return false, nil
+	}, nil)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	db.gcStatsMu.RLock()
+	stats.GC = db.gcStats
+	db.gcStatsMu.RUnlock()
+
+	return stats, nil
+}
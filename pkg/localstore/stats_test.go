@@ -0,0 +1,95 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestDB_Stats(t *testing.T) {
+	db := newTestDB(t, &Options{
+		Capacity: 100,
+	})
+
+	chunks := generateTestRandomChunks(20)
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, chunks...); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Set(context.Background(), storage.ModeSetSyncPull, chunkAddresses(chunks[:10])...); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := db.Set(context.Background(), storage.ModeSetPin, chunks[0].Address()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Set(context.Background(), storage.ModeSetPin, chunks[1].Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indexInfo, err := db.DebugIndices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(indexInfo, "gcSize")
+	for name, count := range indexInfo {
+		if stats.Indexes[name] != count {
+			t.Errorf("got %v items in %s, want %v", stats.Indexes[name], name, count)
+		}
+	}
+	if len(stats.Indexes) != len(indexInfo) {
+		t.Errorf("got %v indexes, want %v", len(stats.Indexes), len(indexInfo))
+	}
+
+	var binChunks int
+	for bin, s := range stats.Bins {
+		// no chunks are removed, so bin ids are not reused
+		if s.Chunks != s.BinID {
+			t.Errorf("got %v chunks in bin %v, want %v", s.Chunks, bin, s.BinID)
+		}
+		binChunks += int(s.Chunks)
+	}
+	if binChunks != len(chunks) {
+		t.Errorf("got %v chunks in bins, want %v", binChunks, len(chunks))
+	}
+
+	if stats.GCSize != 10 {
+		t.Errorf("got gc size %v, want %v", stats.GCSize, 10)
+	}
+	if stats.Capacity != 100 || stats.GCTarget != 90 {
+		t.Errorf("got capacity %v and gc target %v, want 100 and 90", stats.Capacity, stats.GCTarget)
+	}
+	if stats.GCPolicy != "lru" {
+		t.Errorf("got gc policy %q, want %q", stats.GCPolicy, "lru")
+	}
+	if stats.PinnedChunks != 2 || stats.PinCounters != 3 {
+		t.Errorf("got %v pinned chunks with %v pins, want 2 with 3", stats.PinnedChunks, stats.PinCounters)
+	}
+	if stats.ReserveRadius != swarm.MaxPO+1 || stats.ReserveSize != 0 {
+		t.Errorf("got reserve radius %v with %v chunks, want no reserve", stats.ReserveRadius, stats.ReserveSize)
+	}
+
+	db.SetNeighborhoodDepth(0)
+	stats, err = db.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.ReserveRadius != 0 || stats.ReserveSize != uint64(len(chunks)) {
+		t.Errorf("got reserve radius %v with %v chunks, want 0 with %v", stats.ReserveRadius, stats.ReserveSize, len(chunks))
+	}
+
+	if stats.GC.Runs != 0 || !stats.GC.LastRun.IsZero() {
+		t.Errorf("got gc stats %+v, want no runs", stats.GC)
+	}
+}
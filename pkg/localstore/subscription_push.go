@@ -72,6 +72,10 @@ func (db *DB) SubscribePush(ctx context.Context) (c <-chan swarm.Chunk, stop fun
 					if err != nil {
 						return true, err
 					}
+					dataItem, err = db.readData(dataItem)
+					if err != nil {
+						return true, err
+					}
 
 					select {
 					case chunks <- swarm.NewChunk(swarm.NewAddress(dataItem.Address), dataItem.Data).WithTagID(item.Tag):
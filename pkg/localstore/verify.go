@@ -0,0 +1,423 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	// verifyBatchSize limits the number of index items that are
+	// checked while the database is locked.
+	verifyBatchSize = 1000
+	// integrityCheckPause is the pause between batches of the
+	// background integrity check.
+	integrityCheckPause = 100 * time.Millisecond
+)
+
+// errVerifyStopped is returned when verification is stopped
+// because the database is closed.
+var errVerifyStopped = errors.New("verify stopped")
+
+// VerifyOptions holds optional parameters of Verify.
+type VerifyOptions struct {
+	// Validator validates the data of every chunk. Chunk data is not
+	// read, so invalid chunks and chunks without data are not found,
+	// if it is nil.
+	Validator swarm.ChunkValidator
+	// Repair removes invalid chunks and chunks without data, removes
+	// index items of chunks that are not stored, adds missing index
+	// items and corrects the gc size and bin IDs.
+	Repair bool
+	// Pause is the duration between batches of index items during which
+	// the database is not locked, so that verification of a database in
+	// use does not block other operations for long.
+	Pause time.Duration
+}
+
+// VerifyResult holds the problems found by Verify.
+type VerifyResult struct {
+	// Chunks is the number of verified chunks.
+	Chunks int
+	// InvalidChunks are addresses of chunks with
+	// data that is not valid for the address.
+	InvalidChunks []swarm.Address
+	// MissingData are addresses of chunks without data.
+	MissingData []swarm.Address
+	// IndexErrors describe inconsistencies between indexes.
+	IndexErrors []string
+	// Repaired reports whether the problems are repaired.
+	Repaired bool
+}
+
+// OK reports whether no problems are found.
+func (r VerifyResult) OK() bool {
+	return len(r.InvalidChunks) == 0 && len(r.MissingData) == 0 && len(r.IndexErrors) == 0
+}
+
+func (r *VerifyResult) indexError(format string, a ...interface{}) {
+	r.IndexErrors = append(r.IndexErrors, fmt.Sprintf(format, a...))
+}
+
+// Verify checks that every chunk in the retrieval index has valid data and
+// that all indexes, the gc size and the bin IDs are consistent with the
+// retrieval index. Problems are repaired if the options say so.
+func (db *DB) Verify(o VerifyOptions) (r VerifyResult, err error) {
+	r.Repaired = o.Repair
+
+	// chunks with data
+	err = db.verifyInBatches(db.retrievalDataIndex, o.Pause, func(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, err error) {
+		r.Chunks++
+		addr := swarm.NewAddress(item.Address)
+
+		if o.Validator != nil {
+			var remove bool
+			data, err := db.readData(item)
+			switch {
+			case errors.Is(err, leveldb.ErrNotFound):
+				r.MissingData = append(r.MissingData, addr)
+				remove = true
+			case err != nil:
+				return 0, err
+			case !o.Validator.Validate(swarm.NewChunk(addr, data.Data)):
+				r.InvalidChunks = append(r.InvalidChunks, addr)
+				remove = true
+			}
+			if remove {
+				if o.Repair {
+					return db.verifyRemove(batch, item)
+				}
+				return 0, nil
+			}
+		}
+
+		has, err := db.pullIndex.Has(item)
+		if err != nil {
+			return 0, err
+		}
+		if !has {
+			r.indexError("chunk %s is not in pull index", addr)
+			if o.Repair {
+				return 0, db.pullIndex.PutInBatch(batch, item)
+			}
+		}
+		return 0, nil
+	})
+	if err != nil {
+		return r, err
+	}
+
+	// syncing index items of stored chunks, with bin IDs
+	// and store timestamps of the retrieval index
+	err = db.verifyInBatches(db.pullIndex, o.Pause, func(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, err error) {
+		i, err := db.retrievalDataIndex.Get(item)
+		switch {
+		case errors.Is(err, leveldb.ErrNotFound):
+			r.indexError("chunk %s in pull index is not in retrieval index", swarm.NewAddress(item.Address))
+		case err != nil:
+			return 0, err
+		case i.BinID != item.BinID:
+			r.indexError("chunk %s in pull index has bin id %d instead of %d", swarm.NewAddress(item.Address), item.BinID, i.BinID)
+		default:
+			return 0, nil
+		}
+		if o.Repair {
+			return 0, db.pullIndex.DeleteInBatch(batch, item)
+		}
+		return 0, nil
+	})
+	if err != nil {
+		return r, err
+	}
+	err = db.verifyInBatches(db.pushIndex, o.Pause, func(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, err error) {
+		i, err := db.retrievalDataIndex.Get(item)
+		switch {
+		case errors.Is(err, leveldb.ErrNotFound):
+			r.indexError("chunk %s in push index is not in retrieval index", swarm.NewAddress(item.Address))
+		case err != nil:
+			return 0, err
+		case i.StoreTimestamp != item.StoreTimestamp:
+			r.indexError("chunk %s in push index has store timestamp %d instead of %d", swarm.NewAddress(item.Address), item.StoreTimestamp, i.StoreTimestamp)
+		default:
+			return 0, nil
+		}
+		if o.Repair {
+			return 0, db.pushIndex.DeleteInBatch(batch, item)
+		}
+		return 0, nil
+	})
+	if err != nil {
+		return r, err
+	}
+
+	// gc index items of stored chunks with keys of the gc policy
+	rawGCIndex, err := db.newRawGCIndex()
+	if err != nil {
+		return r, err
+	}
+	err = db.verifyInBatches(rawGCIndex, o.Pause, func(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, err error) {
+		addr := swarm.NewAddress(item.Address)
+		i, err := db.fillGCItem(item)
+		switch {
+		case errors.Is(err, leveldb.ErrNotFound):
+			i, err := db.retrievalDataIndex.Get(item)
+			if errors.Is(err, leveldb.ErrNotFound) {
+				r.indexError("chunk %s in gc index is not in retrieval index", addr)
+				break
+			}
+			if err != nil {
+				return 0, err
+			}
+			r.indexError("chunk %s in gc index has no access timestamp", addr)
+			if o.Repair {
+				i.AccessTimestamp = i.StoreTimestamp
+				if err := db.retrievalAccessIndex.PutInBatch(batch, i); err != nil {
+					return 0, err
+				}
+				if err := db.gcIndex.PutInBatch(batch, i); err != nil {
+					return 0, err
+				}
+			}
+		case err != nil:
+			return 0, err
+		default:
+			if bytes.Equal(db.gcKey(i), item.Data) {
+				return 0, nil
+			}
+			r.indexError("chunk %s in gc index has a stale key", addr)
+			if o.Repair {
+				if err := db.gcIndex.PutInBatch(batch, i); err != nil {
+					return 0, err
+				}
+			}
+		}
+		if o.Repair {
+			return 0, rawGCIndex.DeleteInBatch(batch, item)
+		}
+		return 0, nil
+	})
+	if err != nil {
+		return r, err
+	}
+
+	// index items by address of stored chunks
+	for _, i := range []struct {
+		name   string
+		index  shed.Index
+		remove bool
+	}{
+		{name: "retrieval access", index: db.retrievalAccessIndex, remove: true},
+		{name: "gc exclude", index: db.gcExcludeIndex, remove: true},
+		// pins are reported only, as they are set by users
+		{name: "pin", index: db.pinIndex},
+	} {
+		err = db.verifyInBatches(i.index, o.Pause, func(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, err error) {
+			has, err := db.retrievalDataIndex.Has(item)
+			if err != nil || has {
+				return 0, err
+			}
+			r.indexError("chunk %s in %s index is not in retrieval index", swarm.NewAddress(item.Address), i.name)
+			if o.Repair && i.remove {
+				return 0, i.index.DeleteInBatch(batch, item)
+			}
+			return 0, nil
+		})
+		if err != nil {
+			return r, err
+		}
+	}
+
+	// accessed chunks that are not pinned are garbage collected
+	err = db.verifyInBatches(db.retrievalAccessIndex, o.Pause, func(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, err error) {
+		pinned, err := db.pinIndex.Has(item)
+		if err != nil || pinned {
+			return 0, err
+		}
+		i, err := db.fillGCItem(item)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				// reported as not in retrieval index
+				return 0, nil
+			}
+			return 0, err
+		}
+		has, err := db.gcIndex.Has(i)
+		if err != nil || has {
+			return 0, err
+		}
+		r.indexError("chunk %s is not pinned and not in gc index", swarm.NewAddress(item.Address))
+		if o.Repair {
+			return 1, db.gcIndex.PutInBatch(batch, i)
+		}
+		return 0, nil
+	})
+	if err != nil {
+		return r, err
+	}
+
+	// the gc index is counted in a snapshot of the database
+	// taken with the gc size, without blocking other operations
+	db.batchMu.Lock()
+	gcSize, err := db.gcSize.Get()
+	if err != nil {
+		db.batchMu.Unlock()
+		return r, err
+	}
+	countGC := db.gcIndex.Counter()
+	db.batchMu.Unlock()
+	gcCount, err := countGC()
+	if err != nil {
+		return r, err
+	}
+
+	db.batchMu.Lock()
+	defer db.batchMu.Unlock()
+
+	batch := new(leveldb.Batch)
+	if uint64(gcCount) != gcSize {
+		r.indexError("gc size %d does not match %d chunks in gc index", gcSize, gcCount)
+		if o.Repair {
+			// the gc size may have changed after the snapshot
+			if err := db.incGCSizeInBatch(batch, int64(gcCount)-int64(gcSize)); err != nil {
+				return r, err
+			}
+		}
+	}
+	for bin := uint8(0); bin <= swarm.MaxPO; bin++ {
+		last, err := db.pullIndex.Last([]byte{bin})
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				continue
+			}
+			return r, err
+		}
+		id, err := db.binIDs.Get(uint64(bin))
+		if err != nil {
+			return r, err
+		}
+		if id < last.BinID {
+			r.indexError("bin %d id %d is lower than bin id %d in pull index", bin, id, last.BinID)
+			if o.Repair {
+				db.binIDs.PutInBatch(batch, uint64(bin), last.BinID)
+			}
+		}
+	}
+	if err := db.shed.WriteBatch(batch); err != nil {
+		return r, err
+	}
+	if o.Repair {
+		db.metrics.IntegrityRepairs.Add(float64(len(r.InvalidChunks) + len(r.MissingData) + len(r.IndexErrors)))
+	}
+	return r, nil
+}
+
+// verifyRemove removes the chunk of the retrieval data index item
+// from all indexes and releases its data. It returns the change of
+// the gc size.
+func (db *DB) verifyRemove(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, err error) {
+	removed, gcSizeChange, err := db.setRemove(batch, swarm.NewAddress(item.Address))
+	if err != nil {
+		return 0, err
+	}
+	if err := db.pushIndex.DeleteInBatch(batch, removed); err != nil {
+		return 0, err
+	}
+	// the data is not valid, even if it is read before the batch is written
+	db.chunksRemoved(removed)
+	return gcSizeChange, nil
+}
+
+// verifyInBatches calls the function for every item of the index in
+// batches of verifyBatchSize items. Each batch is checked and written
+// with the batchMu lock held, together with the sum of the gc size
+// changes that the function returns, and batches are separated by
+// the pause.
+func (db *DB) verifyInBatches(index shed.Index, pause time.Duration, fn func(batch *leveldb.Batch, item shed.Item) (gcSizeChange int64, err error)) error {
+	var start *shed.Item
+	for {
+		var n int
+		var last shed.Item
+		var gcSizeChange int64
+		db.batchMu.Lock()
+		batch := new(leveldb.Batch)
+		err := index.Iterate(func(item shed.Item) (stop bool, err error) {
+			c, err := fn(batch, item)
+			if err != nil {
+				return true, err
+			}
+			gcSizeChange += c
+			last = item
+			n++
+			return n >= verifyBatchSize, nil
+		}, &shed.IterateOptions{
+			StartFrom:         start,
+			SkipStartFromItem: true,
+		})
+		if err == nil {
+			err = db.incGCSizeInBatch(batch, gcSizeChange)
+		}
+		if err == nil {
+			err = db.shed.WriteBatch(batch)
+		}
+		db.batchMu.Unlock()
+		if err != nil {
+			return err
+		}
+		if n < verifyBatchSize {
+			return nil
+		}
+		start = &last
+
+		if pause > 0 {
+			select {
+			case <-time.After(pause):
+			case <-db.close:
+				return errVerifyStopped
+			}
+		}
+	}
+}
+
+// integrityCheckWorker verifies and repairs the database indexes
+// at the interval until the database is closed.
+func (db *DB) integrityCheckWorker(interval time.Duration) {
+	defer close(db.integrityCheckDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-db.close:
+			return
+		}
+
+		db.metrics.IntegrityCheck.Inc()
+		r, err := db.Verify(VerifyOptions{
+			Repair: true,
+			Pause:  integrityCheckPause,
+		})
+		if err != nil {
+			if errors.Is(err, errVerifyStopped) {
+				return
+			}
+			db.metrics.IntegrityCheckError.Inc()
+			db.logger.Debugf("localstore: integrity check: %v", err)
+			db.logger.Error("localstore: integrity check failed")
+			continue
+		}
+		for _, e := range r.IndexErrors {
+			db.logger.Warningf("localstore: integrity check: repaired: %s", e)
+		}
+		db.logger.Debugf("localstore: integrity check of %d chunks found %d problems", r.Chunks, len(r.IndexErrors))
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package localstore
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/blobstore"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/shed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// TestDB_Verify validates that invalid chunks, chunks without data
+// and inconsistent indexes are reported.
+func TestDB_Verify(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	chunks := putSyncedChunks(t, db, 10)
+
+	valid := chunkValidatorFunc(func(swarm.Chunk) bool { return true })
+
+	r, err := db.Verify(VerifyOptions{Validator: valid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.OK() {
+		t.Fatalf("got problems %+v", r)
+	}
+	if r.Chunks != len(chunks) {
+		t.Errorf("got %v verified chunks, want %v", r.Chunks, len(chunks))
+	}
+
+	invalid := chunks[0].Address()
+
+	// release the data of a chunk
+	item, err := db.retrievalDataIndex.Get(addressToItem(chunks[1].Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := blobstore.ParseLocation(item.Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.blobs.Release(loc)
+	// write to every shard, so that the released slot is reused
+	for i := 0; i < 32; i++ {
+		if _, err := db.blobs.Write(make([]byte, 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// remove a chunk from the pull index
+	item, err = db.retrievalDataIndex.Get(addressToItem(chunks[2].Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.pullIndex.Delete(item); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.gcSize.Put(100); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = db.Verify(VerifyOptions{Validator: chunkValidatorFunc(func(ch swarm.Chunk) bool {
+		return !ch.Address().Equal(invalid)
+	})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.InvalidChunks) != 1 || !r.InvalidChunks[0].Equal(invalid) {
+		t.Errorf("got invalid chunks %v, want %v", r.InvalidChunks, invalid)
+	}
+	if len(r.MissingData) != 1 || !r.MissingData[0].Equal(chunks[1].Address()) {
+		t.Errorf("got chunks without data %v, want %v", r.MissingData, chunks[1].Address())
+	}
+	if len(r.IndexErrors) != 2 {
+		t.Errorf("got index errors %q, want 2", r.IndexErrors)
+	}
+}
+
+// TestDB_VerifyRepair validates that chunks with problems are removed
+// and that inconsistent indexes, the gc size and bin IDs are repaired.
+func TestDB_VerifyRepair(t *testing.T) {
+	defer func(s int) { verifyBatchSize = s }(verifyBatchSize)
+	verifyBatchSize = 3
+
+	db := newTestDB(t, nil)
+
+	chunks := putSyncedChunks(t, db, 10)
+
+	invalid := chunks[0].Address()
+
+	// index items of a chunk that is not stored
+	orphan := shed.Item{
+		Address:         generateTestRandomChunk().Address().Bytes(),
+		BinID:           1000,
+		StoreTimestamp:  now(),
+		AccessTimestamp: now(),
+	}
+	for _, index := range []shed.Index{db.pullIndex, db.pushIndex, db.gcIndex} {
+		if err := index.Put(orphan); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// gc index key with an access timestamp that is not stored
+	item, err := db.retrievalDataIndex.Get(addressToItem(chunks[1].Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err = db.fillGCItem(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.gcIndex.Delete(item); err != nil {
+		t.Fatal(err)
+	}
+	item.AccessTimestamp++
+	if err := db.gcIndex.Put(item); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.gcSize.Put(100); err != nil {
+		t.Fatal(err)
+	}
+	bin := db.po(chunks[2].Address())
+	if err := db.binIDs.Put(uint64(bin), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	validator := chunkValidatorFunc(func(ch swarm.Chunk) bool {
+		return !ch.Address().Equal(invalid)
+	})
+
+	r, err := db.Verify(VerifyOptions{Validator: validator, Repair: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Repaired {
+		t.Error("problems are not repaired")
+	}
+	if len(r.InvalidChunks) != 1 || !r.InvalidChunks[0].Equal(invalid) {
+		t.Errorf("got invalid chunks %v, want %v", r.InvalidChunks, invalid)
+	}
+	// orphan pull, push and gc items, stale gc key, gc size and bin id
+	if len(r.IndexErrors) != 6 {
+		t.Errorf("got index errors %q, want 6", r.IndexErrors)
+	}
+
+	r, err = db.Verify(VerifyOptions{Validator: validator})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.OK() {
+		t.Fatalf("got problems after repair %+v", r)
+	}
+	if r.Chunks != len(chunks)-1 {
+		t.Errorf("got %v verified chunks, want %v", r.Chunks, len(chunks)-1)
+	}
+
+	t.Run("pull index count", newItemsCountTest(db.pullIndex, len(chunks)-1))
+	t.Run("push index count", newItemsCountTest(db.pushIndex, len(chunks)-1))
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, len(chunks)-1))
+	t.Run("gc size", newIndexGCSizeTest(db))
+
+	id, err := db.binIDs.Get(uint64(bin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == 0 {
+		t.Errorf("got bin %v id %v", bin, id)
+	}
+}
+
+// TestDB_VerifyRepairGCSize validates that unpinned chunks missing in the
+// gc index are added to it, and that the gc size is changed by all chunks
+// removed in a batch.
+func TestDB_VerifyRepairGCSize(t *testing.T) {
+	db := newTestDB(t, nil)
+
+	chunks := putSyncedChunks(t, db, 10)
+
+	// a chunk that is not in the gc index, with a consistent gc size
+	item, err := db.retrievalDataIndex.Get(addressToItem(chunks[0].Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err = db.fillGCItem(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.gcIndex.Delete(item); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.gcSize.Put(uint64(len(chunks) - 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// pinned chunks are not in the gc index
+	pinned := chunks[1].Address()
+	if err := db.Set(context.Background(), storage.ModeSetPin, pinned); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.removeChunksInExcludeIndexFromGC(); err != nil {
+		t.Fatal(err)
+	}
+
+	// invalid chunks that are removed in the same batch
+	invalid := make(map[string]bool)
+	for _, ch := range chunks[2:5] {
+		invalid[ch.Address().String()] = true
+	}
+	validator := chunkValidatorFunc(func(ch swarm.Chunk) bool {
+		return !invalid[ch.Address().String()]
+	})
+
+	r, err := db.Verify(VerifyOptions{Validator: validator, Repair: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.InvalidChunks) != len(invalid) {
+		t.Errorf("got invalid chunks %v, want %v", r.InvalidChunks, len(invalid))
+	}
+	// only the chunk that is not in the gc index, not the gc size
+	if len(r.IndexErrors) != 1 {
+		t.Errorf("got index errors %q, want 1", r.IndexErrors)
+	}
+
+	r, err = db.Verify(VerifyOptions{Validator: validator})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.OK() {
+		t.Fatalf("got problems after repair %+v", r)
+	}
+
+	t.Run("gc index count", newItemsCountTest(db.gcIndex, len(chunks)-len(invalid)-1))
+	t.Run("gc size", newIndexGCSizeTest(db))
+}
+
+// TestDB_integrityCheck validates that the background
+// integrity check repairs indexes.
+func TestDB_integrityCheck(t *testing.T) {
+	db := newTestDB(t, &Options{IntegrityCheckInterval: 10 * time.Millisecond})
+
+	putSyncedChunks(t, db, 10)
+
+	db.batchMu.Lock()
+	err := db.gcSize.Put(100)
+	db.batchMu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		db.batchMu.Lock()
+		size, err := db.gcSize.Get()
+		db.batchMu.Unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size == 10 {
+			return
+		}
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatalf("got gc size %v, want 10", size)
+		}
+	}
+}
+
+// TestDB_baseKey validates that the database is
+// opened with the stored base key if none is provided.
+func TestDB_baseKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localstore-base-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	logger := logging.New(ioutil.Discard, 0)
+
+	if _, err := New(dir, nil, nil, logger); !errors.Is(err, ErrBaseKeyUnknown) {
+		t.Fatalf("got error %v, want %v", err, ErrBaseKeyUnknown)
+	}
+
+	baseKey := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c").Bytes()
+	db, err := New(dir, baseKey, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := generateTestRandomChunk()
+	if _, err := db.Put(context.Background(), storage.ModePutUpload, ch); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = New(dir, nil, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if !swarm.NewAddress(db.BaseKey()).Equal(swarm.NewAddress(baseKey)) {
+		t.Errorf("got base key %x, want %x", db.BaseKey(), baseKey)
+	}
+	if _, err := db.Get(context.Background(), storage.ModeGetLookup, ch.Address()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type chunkValidatorFunc func(swarm.Chunk) bool
+
+func (f chunkValidatorFunc) Validate(ch swarm.Chunk) bool {
+	return f(ch)
+}
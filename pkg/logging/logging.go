@@ -5,11 +5,21 @@
 package logging
 
 import (
+	"errors"
 	"io"
+	"sort"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
 
+// RootLoggerName is the name of the logger returned by New.
+const RootLoggerName = "root"
+
+// ErrLoggerNotFound is returned when the level is set
+// for a logger that does not exist.
+var ErrLoggerNotFound = errors.New("logger not found")
+
 type Logger interface {
 	Tracef(format string, args ...interface{})
 	Trace(args ...interface{})
@@ -25,28 +35,170 @@ type Logger interface {
 	WithFields(fields logrus.Fields) *logrus.Entry
 	WriterLevel(logrus.Level) *io.PipeWriter
 	NewEntry() *logrus.Entry
+	// Named returns the logger of the subsystem with the name, creating
+	// it with the level of this logger if it does not exist. Named
+	// loggers share the output of the logger returned by New and their
+	// messages have the logger field with the name.
+	Named(name string) Logger
+	// Loggers returns the names and levels of all loggers, sorted by name.
+	Loggers() []LoggerLevel
+	// SetLoggerLevel changes the level of the logger with the name.
+	SetLoggerLevel(name string, level logrus.Level) error
+}
+
+// LoggerLevel is the level of the logger with the name.
+type LoggerLevel struct {
+	Name  string
+	Level logrus.Level
+}
+
+// Option configures the logger returned by New.
+type Option func(*options)
+
+type options struct {
+	json bool
+}
+
+// WithJSONFormat formats log messages as JSON objects, one per line.
+func WithJSONFormat() Option {
+	return func(o *options) {
+		o.json = true
+	}
 }
 
 type logger struct {
 	*logrus.Logger
-	metrics metrics
+	metrics  metrics
+	registry *registry
 }
 
-func New(w io.Writer, level logrus.Level) Logger {
-	l := logrus.New()
-	l.SetOutput(w)
-	l.SetLevel(level)
-	l.Formatter = &logrus.TextFormatter{
+// registry holds all loggers that share the same output.
+type registry struct {
+	out       io.Writer
+	formatter logrus.Formatter
+	metrics   metrics
+	loggers   map[string]*logger
+	mu        sync.Mutex
+}
+
+func New(w io.Writer, level logrus.Level, opts ...Option) Logger {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var formatter logrus.Formatter = &logrus.TextFormatter{
 		FullTimestamp: true,
 	}
-	metrics := newMetrics()
-	l.AddHook(metrics)
+	if o.json {
+		formatter = &logrus.JSONFormatter{}
+	}
+
+	r := &registry{
+		// messages of different loggers must not interleave
+		out:       &syncWriter{w: w},
+		formatter: formatter,
+		metrics:   newMetrics(),
+		loggers:   make(map[string]*logger),
+	}
+	l := r.newLogger(level, formatter)
+	r.loggers[RootLoggerName] = l
+	return l
+}
+
+func (r *registry) newLogger(level logrus.Level, formatter logrus.Formatter) *logger {
+	l := logrus.New()
+	l.SetOutput(r.out)
+	l.SetLevel(level)
+	l.Formatter = formatter
+	l.AddHook(r.metrics)
 	return &logger{
-		Logger:  l,
-		metrics: metrics,
+		Logger:   l,
+		metrics:  r.metrics,
+		registry: r,
 	}
 }
 
 func (l *logger) NewEntry() *logrus.Entry {
 	return logrus.NewEntry(l.Logger)
 }
+
+func (l *logger) Named(name string) Logger {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n, ok := r.loggers[name]; ok {
+		return n
+	}
+	n := r.newLogger(l.GetLevel(), &namedFormatter{
+		name:      name,
+		formatter: r.formatter,
+	})
+	r.loggers[name] = n
+	return n
+}
+
+func (l *logger) Loggers() []LoggerLevel {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	levels := make([]LoggerLevel, 0, len(r.loggers))
+	for name, n := range r.loggers {
+		levels = append(levels, LoggerLevel{
+			Name:  name,
+			Level: n.GetLevel(),
+		})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Name < levels[j].Name
+	})
+	return levels
+}
+
+func (l *logger) SetLoggerLevel(name string, level logrus.Level) error {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.loggers[name]
+	if !ok {
+		return ErrLoggerNotFound
+	}
+	n.SetLevel(level)
+	return nil
+}
+
+// namedFormatter adds the logger field with the
+// name of the logger to every message.
+type namedFormatter struct {
+	name      string
+	formatter logrus.Formatter
+}
+
+func (f *namedFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	// entries may share their data, so it is copied
+	data := make(logrus.Fields, len(e.Data)+1)
+	for k, v := range e.Data {
+		data[k] = v
+	}
+	data["logger"] = f.name
+
+	entry := *e
+	entry.Data = data
+	return f.formatter.Format(&entry)
+}
+
+// syncWriter serializes writes to the underlying writer.
+type syncWriter struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.w.Write(p)
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+func TestNamed(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logrus.InfoLevel)
+
+	p2p := logger.Named("p2p")
+	if logger.Named("p2p") != p2p {
+		t.Fatal("named logger is created again")
+	}
+	kademlia := logger.Named("kademlia")
+
+	if err := logger.SetLoggerLevel("p2p", logrus.DebugLevel); err != nil {
+		t.Fatal(err)
+	}
+
+	p2p.Debug("p2p debug")
+	kademlia.Debug("kademlia debug")
+	kademlia.Info("kademlia info")
+	logger.Debug("root debug")
+
+	out := buf.String()
+	if !strings.Contains(out, `msg="p2p debug" logger=p2p`) {
+		t.Errorf("p2p debug message not logged: %s", out)
+	}
+	if strings.Contains(out, "kademlia debug") || strings.Contains(out, "root debug") {
+		t.Errorf("debug message logged at info level: %s", out)
+	}
+	if !strings.Contains(out, `msg="kademlia info" logger=kademlia`) {
+		t.Errorf("kademlia info message not logged: %s", out)
+	}
+
+	want := []logging.LoggerLevel{
+		{Name: "kademlia", Level: logrus.InfoLevel},
+		{Name: "p2p", Level: logrus.DebugLevel},
+		{Name: logging.RootLoggerName, Level: logrus.InfoLevel},
+	}
+	// the loggers are shared with named loggers
+	if got := p2p.Loggers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got loggers %v, want %v", got, want)
+	}
+
+	if err := p2p.SetLoggerLevel("pusher", logrus.DebugLevel); !errors.Is(err, logging.ErrLoggerNotFound) {
+		t.Errorf("got error %v, want %v", err, logging.ErrLoggerNotFound)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logrus.InfoLevel, logging.WithJSONFormat())
+
+	logger.Named("api").WithField("status", 200).Info("served")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("message %q is not json: %v", buf.String(), err)
+	}
+	for k, v := range map[string]interface{}{
+		"level":  "info",
+		"msg":    "served",
+		"logger": "api",
+		"status": float64(200),
+	} {
+		if got[k] != v {
+			t.Errorf("got %s %v, want %v", k, got[k], v)
+		}
+	}
+}
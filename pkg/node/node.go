@@ -19,6 +19,7 @@ import (
 
 	"github.com/ethersphere/bee/pkg/addressbook"
 	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/auth"
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/debugapi"
 	"github.com/ethersphere/bee/pkg/hive"
@@ -32,18 +33,23 @@ import (
 	"github.com/ethersphere/bee/pkg/netstore"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/p2p/libp2p"
+	"github.com/ethersphere/bee/pkg/p2p/penalty"
 	"github.com/ethersphere/bee/pkg/pingpong"
 	"github.com/ethersphere/bee/pkg/puller"
 	"github.com/ethersphere/bee/pkg/pullsync"
 	"github.com/ethersphere/bee/pkg/pullsync/pullstorage"
 	"github.com/ethersphere/bee/pkg/pusher"
 	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resolver/ens"
+	"github.com/ethersphere/bee/pkg/resolver/static"
 	"github.com/ethersphere/bee/pkg/retrieval"
-	"github.com/ethersphere/bee/pkg/statestore/leveldb"
+	"github.com/ethersphere/bee/pkg/statestore"
 	mockinmem "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/topology"
 	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/ethersphere/bee/pkg/validator"
 	ma "github.com/multiformats/go-multiaddr"
@@ -67,23 +73,40 @@ type Bee struct {
 }
 
 type Options struct {
-	DataDir            string
-	DBCapacity         uint64
-	Password           string
-	APIAddr            string
-	DebugAPIAddr       string
-	Addr               string
-	NATAddr            string
-	EnableWS           bool
-	EnableQUIC         bool
-	NetworkID          uint64
-	WelcomeMessage     string
-	Bootnodes          []string
-	CORSAllowedOrigins []string
-	Logger             logging.Logger
-	TracingEnabled     bool
-	TracingEndpoint    string
-	TracingServiceName string
+	DataDir                  string
+	DBCapacity               uint64 // in bytes
+	DBMinFreeSpace           uint64 // in bytes
+	DBGCPolicy               localstore.GCPolicy
+	DBCacheCapacity          uint64 // in bytes
+	DBIntegrityCheckInterval time.Duration
+	StateStoreBackend        string
+	Password                 string
+	APIAddr                  string
+	DebugAPIAddr             string
+	Addr                     string
+	NATAddr                  string
+	EnableWS                 bool
+	EnableQUIC               bool
+	NetworkID                uint64
+	WelcomeMessage           string
+	Bootnodes                []string
+	CORSAllowedOrigins       []string
+	Logger                   logging.Logger
+	TracingEnabled           bool
+	TracingEndpoint          string
+	TracingServiceName       string
+	ENSEndpoint              string
+	NamesFile                string
+	ResolverCacheTTL         time.Duration
+	GatewayMode              bool
+	MaxUploadSize            int64
+	UploadRateLimit          int
+	TrustedProxies           []string
+	DenylistFile             string
+	Restricted               bool
+	AdminPassword            string
+	APISpecValidation        bool
+	ReadinessMinPeers        int
 }
 
 func NewBee(o Options) (*Bee, error) {
@@ -144,12 +167,19 @@ func NewBee(o Options) (*Bee, error) {
 		stateStore = mockinmem.NewStateStore()
 		logger.Warning("using in-mem state store. no node state will be persisted")
 	} else {
-		stateStore, err = leveldb.NewStateStore(filepath.Join(o.DataDir, "statestore"))
+		stateStore, err = statestore.Open(o.StateStoreBackend, filepath.Join(o.DataDir, "statestore"), logger)
 		if err != nil {
 			return nil, fmt.Errorf("statestore: %w", err)
 		}
 	}
 	b.stateStoreCloser = stateStore
+	migrated, err := puller.MigrateIntervals(stateStore)
+	if err != nil {
+		return nil, fmt.Errorf("statestore: migrate puller intervals: %w", err)
+	}
+	if migrated > 0 {
+		logger.Debugf("statestore: moved %d puller intervals to the puller namespace", migrated)
+	}
 	addressbook := addressbook.New(stateStore)
 	signer := crypto.NewDefaultSigner(swarmPrivateKey)
 
@@ -160,7 +190,7 @@ func NewBee(o Options) (*Bee, error) {
 		EnableQUIC:     o.EnableQUIC,
 		Addressbook:    addressbook,
 		WelcomeMessage: o.WelcomeMessage,
-		Logger:         logger,
+		Logger:         logger.Named("p2p"),
 		Tracer:         tracer,
 	})
 	if err != nil {
@@ -185,7 +215,7 @@ func NewBee(o Options) (*Bee, error) {
 	// Construct protocols.
 	pingPong := pingpong.New(pingpong.Options{
 		Streamer: p2ps,
-		Logger:   logger,
+		Logger:   logger.Named("pingpong"),
 		Tracer:   tracer,
 	})
 
@@ -197,17 +227,17 @@ func NewBee(o Options) (*Bee, error) {
 		Streamer:    p2ps,
 		AddressBook: addressbook,
 		NetworkID:   o.NetworkID,
-		Logger:      logger,
+		Logger:      logger.Named("hive"),
 	})
 
 	if err = p2ps.AddProtocol(hive.Protocol()); err != nil {
 		return nil, fmt.Errorf("hive service: %w", err)
 	}
 
-	topologyDriver := kademlia.New(kademlia.Options{Base: address, Discovery: hive, AddressBook: addressbook, P2P: p2ps, Logger: logger})
+	topologyDriver := kademlia.New(kademlia.Options{Base: address, Discovery: hive, AddressBook: addressbook, P2P: p2ps, Logger: logger.Named("kademlia")})
 	b.topologyCloser = topologyDriver
 	hive.SetPeerAddedHandler(topologyDriver.AddPeer)
-	p2ps.SetNotifier(topologyDriver)
+	p2ps.SetNotifier(&disconnectNotifier{Notifier: topologyDriver, disconnecters: []topology.Disconnecter{pingPong}})
 	addrs, err := p2ps.Addresses()
 	if err != nil {
 		return nil, fmt.Errorf("get server addresses: %w", err)
@@ -217,27 +247,48 @@ func NewBee(o Options) (*Bee, error) {
 		logger.Debugf("p2p address: %s", addr)
 	}
 
-	var (
-		storer storage.Storer
-		path   = ""
-	)
+	var path string
 
 	if o.DataDir != "" {
 		path = filepath.Join(o.DataDir, "localstore")
 	}
 	lo := &localstore.Options{
-		Capacity: o.DBCapacity,
-	}
-	storer, err = localstore.New(path, address.Bytes(), lo, logger)
+		// the gc index can not hold more chunks than fit in the
+		// capacity, which is enforced against the measured disk usage
+		Capacity:               o.DBCapacity / swarm.ChunkSize,
+		DiskCapacity:           o.DBCapacity,
+		MinFreeDiskSpace:       o.DBMinFreeSpace,
+		GCPolicy:               o.DBGCPolicy,
+		IntegrityCheckInterval: o.DBIntegrityCheckInterval,
+	}
+	storer, err := localstore.New(path, address.Bytes(), lo, logger.Named("localstore"))
 	if err != nil {
 		return nil, fmt.Errorf("localstore: %w", err)
 	}
 	b.localstoreCloser = storer
 
+	// keep the chunks within the neighborhood depth in the localstore reserve
+	go func() {
+		c, unsubscribe := topologyDriver.SubscribePeersChange()
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-c:
+				// the depth is zero until enough peers are connected
+				if topologyDriver.DepthEstablished() {
+					storer.SetNeighborhoodDepth(topologyDriver.NeighborhoodDepth())
+				}
+			case <-p2pCtx.Done():
+				return
+			}
+		}
+	}()
+
 	retrieve := retrieval.New(retrieval.Options{
 		Streamer:    p2ps,
 		ChunkPeerer: topologyDriver,
-		Logger:      logger,
+		Logger:      logger.Named("retrieval"),
 	})
 	tag := tags.NewTags()
 
@@ -245,15 +296,30 @@ func NewBee(o Options) (*Bee, error) {
 		return nil, fmt.Errorf("retrieval service: %w", err)
 	}
 
-	ns := netstore.New(storer, retrieve, validator.NewContentAddressValidator())
+	chunkValidator := validator.NewContentAddressValidator()
+
+	// chunks requested through the api and by other
+	// peers are served from the in-memory cache
+	var requestStorer storage.Storer = storer
+	if capacity := o.DBCacheCapacity / swarm.ChunkSize; capacity > 0 {
+		cache := localstore.NewCache(storer, localstore.CacheOptions{Capacity: capacity})
+		b.localstoreCloser = cache
+		requestStorer = cache
+	}
+
+	ns := netstore.New(requestStorer, retrieve, chunkValidator)
 
 	retrieve.SetStorer(ns)
 
+	penalizer := penalty.New(p2ps, logger.Named("penalty"), penalty.Options{})
+
 	pushSyncProtocol := pushsync.New(pushsync.Options{
 		Streamer:      p2ps,
 		Storer:        storer,
 		ClosestPeerer: topologyDriver,
-		Logger:        logger,
+		Validators:    []swarm.ChunkValidator{chunkValidator},
+		Penalizer:     penalizer,
+		Logger:        logger.Named("pushsync"),
 	})
 
 	if err = p2ps.AddProtocol(pushSyncProtocol.Protocol()); err != nil {
@@ -265,16 +331,18 @@ func NewBee(o Options) (*Bee, error) {
 		PeerSuggester: topologyDriver,
 		PushSyncer:    pushSyncProtocol,
 		Tags:          tag,
-		Logger:        logger,
+		Logger:        logger.Named("pusher"),
 	})
 	b.pusherCloser = pushSyncPusher
 
 	pullStorage := pullstorage.New(storer)
 
 	pullSync := pullsync.New(pullsync.Options{
-		Streamer: p2ps,
-		Storage:  pullStorage,
-		Logger:   logger,
+		Streamer:   p2ps,
+		Storage:    pullStorage,
+		Validators: []swarm.ChunkValidator{chunkValidator},
+		Penalizer:  penalizer,
+		Logger:     logger.Named("pullsync"),
 	})
 	b.pullSyncCloser = pullSync
 
@@ -286,19 +354,99 @@ func NewBee(o Options) (*Bee, error) {
 		StateStore: stateStore,
 		Topology:   topologyDriver,
 		PullSync:   pullSync,
-		Logger:     logger,
+		Logger:     logger.Named("puller"),
 	})
 	b.pullerCloser = puller
 
+	// Token authentication of the API and debug API
+	var authenticator *auth.Authenticator
+	if o.Restricted {
+		if o.AdminPassword == "" {
+			return nil, errors.New("admin password is required for restricted access")
+		}
+		authenticator = auth.New(stateStore, o.AdminPassword)
+	}
+
+	// Conditions for the node to be ready to serve requests
+	readinessChecks := []debugapi.ReadinessCheck{
+		{
+			Name: "localstore",
+			Check: func() error {
+				_, err := storer.GCSize()
+				return err
+			},
+		},
+		{
+			Name: "peers",
+			Check: func() error {
+				if n := len(p2ps.Peers()); n < o.ReadinessMinPeers {
+					return fmt.Errorf("%d of %d peers connected", n, o.ReadinessMinPeers)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "kademlia",
+			Check: func() error {
+				if !topologyDriver.DepthEstablished() {
+					return errors.New("neighborhood depth not established")
+				}
+				return nil
+			},
+		},
+	}
+
 	var apiService api.Service
 	if o.APIAddr != "" {
+		// Name resolvers, with local names taking precedence over ENS names.
+		// The feed resolver is not added, as there is no feed Lookuper yet.
+		var resolvers []resolver.Interface
+		if o.NamesFile != "" {
+			r, err := static.NewFromFile(o.NamesFile)
+			if err != nil {
+				return nil, fmt.Errorf("names file: %w", err)
+			}
+			resolvers = append(resolvers, r)
+		}
+		if o.ENSEndpoint != "" {
+			resolvers = append(resolvers, ens.New(o.ENSEndpoint, nil))
+		}
+		var nameResolver resolver.Interface
+		if len(resolvers) > 0 {
+			nameResolver = resolver.NewChain(resolvers...)
+			if o.ResolverCacheTTL > 0 {
+				nameResolver = resolver.NewCache(nameResolver, o.ResolverCacheTTL)
+			}
+		}
+
+		var denylist []swarm.Address
+		if o.DenylistFile != "" {
+			denylist, err = api.ReadDenylistFile(o.DenylistFile)
+			if err != nil {
+				return nil, fmt.Errorf("denylist file: %w", err)
+			}
+		}
+
+		trustedProxies, err := api.ParseTrustedProxies(o.TrustedProxies)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxies: %w", err)
+		}
+
 		// API server
 		apiService = api.New(api.Options{
 			Tags:               tag,
 			Storer:             ns,
+			Resolver:           nameResolver,
 			CORSAllowedOrigins: o.CORSAllowedOrigins,
-			Logger:             logger,
+			Logger:             logger.Named("api"),
 			Tracer:             tracer,
+			GatewayMode:        o.GatewayMode,
+			MaxUploadSize:      o.MaxUploadSize,
+			UploadRateLimit:    o.UploadRateLimit,
+			TrustedProxies:     trustedProxies,
+			Denylist:           denylist,
+			Authenticator:      authenticator,
+			ValidateSpec:       o.APISpecValidation,
 		})
 		apiListener, err := net.Listen("tcp", o.APIAddr)
 		if err != nil {
@@ -310,9 +458,23 @@ func NewBee(o Options) (*Bee, error) {
 			ErrorLog: log.New(b.errorLogWriter, "", 0),
 		}
 
+		var apiServing int32
+		readinessChecks = append(readinessChecks, debugapi.ReadinessCheck{
+			Name: "api",
+			Check: func() error {
+				if atomic.LoadInt32(&apiServing) == 0 {
+					return errors.New("api server not serving")
+				}
+				return nil
+			},
+		})
+
 		go func() {
 			logger.Infof("api address: %s", apiListener.Addr())
 
+			atomic.StoreInt32(&apiServing, 1)
+			defer atomic.StoreInt32(&apiServing, 0)
+
 			if err := apiServer.Serve(apiListener); err != nil && err != http.ErrServerClosed {
 				logger.Debugf("api server: %v", err)
 				logger.Error("unable to serve api")
@@ -325,18 +487,25 @@ func NewBee(o Options) (*Bee, error) {
 	if o.DebugAPIAddr != "" {
 		// Debug API server
 		debugAPIService := debugapi.New(debugapi.Options{
-			Overlay:        address,
-			P2P:            p2ps,
-			Pingpong:       pingPong,
-			Logger:         logger,
-			Tracer:         tracer,
-			Addressbook:    addressbook,
-			TopologyDriver: topologyDriver,
-			Storer:         storer,
+			Overlay:         address,
+			P2P:             p2ps,
+			Pingpong:        pingPong,
+			Logger:          logger.Named("debugapi"),
+			Tracer:          tracer,
+			Addressbook:     addressbook,
+			TopologyDriver:  topologyDriver,
+			Storer:          storer,
+			NetworkID:       o.NetworkID,
+			ReadinessChecks: readinessChecks,
+			Authenticator:   authenticator,
+			ValidateSpec:    o.APISpecValidation,
 		})
 		// register metrics from components
 		debugAPIService.MustRegisterMetrics(p2ps.Metrics()...)
 		debugAPIService.MustRegisterMetrics(pingPong.Metrics()...)
+		debugAPIService.MustRegisterMetrics(chunkValidator.Metrics()...)
+		debugAPIService.MustRegisterMetrics(pushSyncProtocol.Metrics()...)
+		debugAPIService.MustRegisterMetrics(pullSync.Metrics()...)
 		if apiService != nil {
 			debugAPIService.MustRegisterMetrics(apiService.Metrics()...)
 		}
@@ -519,6 +688,20 @@ func (b *Bee) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// disconnectNotifier notifies the disconnecters
+// as well when peers disconnect.
+type disconnectNotifier struct {
+	topology.Notifier
+	disconnecters []topology.Disconnecter
+}
+
+func (n *disconnectNotifier) Disconnected(addr swarm.Address) {
+	n.Notifier.Disconnected(addr)
+	for _, d := range n.disconnecters {
+		d.Disconnected(addr)
+	}
+}
+
 type multiError struct {
 	errors []error
 }
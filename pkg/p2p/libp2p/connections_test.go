@@ -7,6 +7,7 @@ package libp2p_test
 import (
 	"context"
 	"errors"
+	"io/ioutil"
 	"sync"
 	"testing"
 	"time"
@@ -57,6 +58,103 @@ func TestConnectDisconnect(t *testing.T) {
 	expectPeersEventually(t, s1)
 }
 
+func TestPeerInfo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s1, overlay1 := newService(t, 1, libp2p.Options{})
+
+	s2, overlay2 := newService(t, 1, libp2p.Options{LightNode: true})
+
+	received := make(chan []byte, 1)
+	if err := s1.AddProtocol(newTestProtocol(func(_ context.Context, _ p2p.Peer, stream p2p.Stream) error {
+		defer stream.Close()
+		data, err := ioutil.ReadAll(stream)
+		if err != nil {
+			return err
+		}
+		received <- data
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := serviceUnderlayAddress(t, s1)
+
+	if _, err := s2.Connect(ctx, addr); err != nil {
+		t.Fatal(err)
+	}
+	expectPeersEventually(t, s1, overlay2)
+
+	info, err := s2.Peer(overlay1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Address.Equal(overlay1) {
+		t.Errorf("got address %s, want %s", info.Address, overlay1)
+	}
+	if info.Direction != p2p.DirectionOutbound {
+		t.Errorf("got direction %s, want %s", info.Direction, p2p.DirectionOutbound)
+	}
+	if info.Light {
+		t.Error("full node reported as light")
+	}
+	if len(info.Underlays) != 1 {
+		t.Errorf("got underlays %v, want one", info.Underlays)
+	}
+	if info.ConnectedAt.IsZero() {
+		t.Error("connection time not recorded")
+	}
+
+	info, err = s1.Peer(overlay2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Direction != p2p.DirectionInbound {
+		t.Errorf("got direction %s, want %s", info.Direction, p2p.DirectionInbound)
+	}
+	if !info.Light {
+		t.Error("light node not reported as light")
+	}
+
+	stream, err := s2.NewStream(ctx, overlay1, nil, testProtocolName, testProtocolVersion, testStreamName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case data := <-received:
+		if string(data) != "hello" {
+			t.Fatalf("got data %q, want %q", data, "hello")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the stream data")
+	}
+
+	// headers are exchanged over the stream as well
+	info, err = s2.Peer(overlay1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := info.Protocols[testProtocolName]
+	if stats.BytesSent <= 5 {
+		t.Errorf("got %v bytes sent, want more than 5", stats.BytesSent)
+	}
+	if stats.BytesReceived == 0 {
+		t.Error("got no bytes received")
+	}
+
+	if _, err := s2.Peer(swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")); !errors.Is(err, p2p.ErrPeerNotFound) {
+		t.Errorf("got error %v, want %v", err, p2p.ErrPeerNotFound)
+	}
+}
+
 func TestDoubleConnect(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
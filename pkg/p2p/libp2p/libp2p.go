@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/addressbook"
 	"github.com/ethersphere/bee/pkg/bzz"
@@ -221,7 +222,7 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 			return
 		}
 
-		if exists := s.peers.addIfNotExists(stream.Conn(), i.BzzAddress.Overlay); exists {
+		if exists := s.peers.addIfNotExists(stream.Conn(), i.BzzAddress.Overlay, i.Light); exists {
 			if err = handshakeStream.FullClose(); err != nil {
 				s.logger.Debugf("handshake: could not close stream %s: %v", peerID, err)
 				s.logger.Errorf("unable to handshake with peer %v", peerID)
@@ -281,7 +282,7 @@ func (s *Service) AddProtocol(p p2p.ProtocolSpec) (err error) {
 				return
 			}
 
-			stream := newStream(streamlibp2p)
+			stream := newStream(streamlibp2p, s.peers.protocolStats(peerID, p.Name))
 
 			// exchange headers
 			if err := handleHeaders(ss.Headler, stream); err != nil {
@@ -381,7 +382,7 @@ func (s *Service) Connect(ctx context.Context, addr ma.Multiaddr) (address *bzz.
 		return nil, fmt.Errorf("handshake: %w", err)
 	}
 
-	if exists := s.peers.addIfNotExists(stream.Conn(), i.BzzAddress.Overlay); exists {
+	if exists := s.peers.addIfNotExists(stream.Conn(), i.BzzAddress.Overlay, i.Light); exists {
 		if err := handshakeStream.FullClose(); err != nil {
 			_ = s.disconnect(info.ID)
 			return nil, fmt.Errorf("peer exists, full close: %w", err)
@@ -422,6 +423,27 @@ func (s *Service) Peers() []p2p.Peer {
 	return s.peers.peers()
 }
 
+func (s *Service) Peer(overlay swarm.Address) (p2p.PeerInfo, error) {
+	info, peerID, found := s.peers.peerInfo(overlay)
+	if !found {
+		return p2p.PeerInfo{}, p2p.ErrPeerNotFound
+	}
+
+	for i, addr := range info.Underlays {
+		a, err := buildUnderlayAddress(addr, peerID)
+		if err != nil {
+			return p2p.PeerInfo{}, err
+		}
+		info.Underlays[i] = a
+	}
+
+	if until := s.connectionBreaker.ClosedUntil(); until.After(time.Now()) {
+		info.BreakerClosedUntil = until
+	}
+
+	return info, nil
+}
+
 func (s *Service) SetNotifier(n topology.Notifier) {
 	s.topologyNotifier = n
 	s.peers.setDisconnecter(n)
@@ -439,7 +461,7 @@ func (s *Service) NewStream(ctx context.Context, overlay swarm.Address, headers
 		return nil, fmt.Errorf("new stream for peerid: %w", err)
 	}
 
-	stream := newStream(streamlibp2p)
+	stream := newStream(streamlibp2p, s.peers.protocolStats(peerID, protocolName))
 
 	// tracing: add span context header
 	if headers == nil {
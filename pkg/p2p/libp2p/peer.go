@@ -9,12 +9,15 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/topology"
 	"github.com/libp2p/go-libp2p-core/network"
 	libp2ppeer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 type peerRegistry struct {
@@ -22,18 +25,28 @@ type peerRegistry struct {
 	overlays    map[libp2ppeer.ID]swarm.Address             // map underlay peer id to overlay address
 	connections map[libp2ppeer.ID]map[network.Conn]struct{} // list of connections for safe removal on Disconnect notification
 	streams     map[libp2ppeer.ID]map[network.Stream]context.CancelFunc
+	infos       map[libp2ppeer.ID]*peerInfo // connection metadata of peers
 	mu          sync.RWMutex
 
 	disconnecter     topology.Disconnecter // peerRegistry notifies topology on peer disconnection
 	network.Notifiee                       // peerRegistry can be the receiver for network.Notify
 }
 
+// peerInfo holds the metadata of the connection with a peer.
+type peerInfo struct {
+	direction   p2p.Direction
+	connectedAt time.Time
+	light       bool
+	protocols   map[string]*protocolStats // traffic by protocol name
+}
+
 func newPeerRegistry() *peerRegistry {
 	return &peerRegistry{
 		underlays:   make(map[string]libp2ppeer.ID),
 		overlays:    make(map[libp2ppeer.ID]swarm.Address),
 		connections: make(map[libp2ppeer.ID]map[network.Conn]struct{}),
 		streams:     make(map[libp2ppeer.ID]map[network.Stream]context.CancelFunc),
+		infos:       make(map[libp2ppeer.ID]*peerInfo),
 
 		Notifiee: new(network.NoopNotifiee),
 	}
@@ -61,6 +74,7 @@ func (r *peerRegistry) Disconnected(_ network.Network, c network.Conn) {
 	overlay := r.overlays[peerID]
 	delete(r.overlays, peerID)
 	delete(r.underlays, overlay.ByteString())
+	delete(r.infos, peerID)
 
 	delete(r.connections[peerID], c)
 	if len(r.connections[peerID]) == 0 {
@@ -122,7 +136,67 @@ func (r *peerRegistry) peers() []p2p.Peer {
 	return peers
 }
 
-func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address) (exists bool) {
+// peerInfo returns the information about the connection with the peer and
+// its underlay peer id. Underlay addresses of all connections with the peer
+// are returned as remote multiaddresses, without the peer id.
+func (r *peerRegistry) peerInfo(overlay swarm.Address) (info p2p.PeerInfo, peerID libp2ppeer.ID, found bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	peerID, found = r.underlays[overlay.ByteString()]
+	if !found {
+		return p2p.PeerInfo{}, "", false
+	}
+	i, found := r.infos[peerID]
+	if !found {
+		return p2p.PeerInfo{}, "", false
+	}
+
+	underlays := make([]ma.Multiaddr, 0, len(r.connections[peerID]))
+	for c := range r.connections[peerID] {
+		underlays = append(underlays, c.RemoteMultiaddr())
+	}
+	sort.Slice(underlays, func(i, j int) bool {
+		return underlays[i].String() < underlays[j].String()
+	})
+
+	protocols := make(map[string]p2p.ProtocolStats, len(i.protocols))
+	for name, stats := range i.protocols {
+		protocols[name] = p2p.ProtocolStats{
+			BytesSent:     atomic.LoadUint64(&stats.sent),
+			BytesReceived: atomic.LoadUint64(&stats.received),
+		}
+	}
+
+	return p2p.PeerInfo{
+		Peer:        p2p.Peer{Address: overlay},
+		Underlays:   underlays,
+		Direction:   i.direction,
+		ConnectedAt: i.connectedAt,
+		Light:       i.light,
+		Protocols:   protocols,
+	}, peerID, true
+}
+
+// protocolStats returns the traffic counters of the protocol for the
+// peer, or nil if the peer is not in the registry.
+func (r *peerRegistry) protocolStats(peerID libp2ppeer.ID, protocolName string) *protocolStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.infos[peerID]
+	if !ok {
+		return nil
+	}
+	stats, ok := i.protocols[protocolName]
+	if !ok {
+		stats = new(protocolStats)
+		i.protocols[protocolName] = stats
+	}
+	return stats
+}
+
+func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address, light bool) (exists bool) {
 	peerID := c.RemotePeer()
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -136,6 +210,17 @@ func (r *peerRegistry) addIfNotExists(c network.Conn, overlay swarm.Address) (ex
 	if _, exists := r.underlays[overlay.ByteString()]; !exists {
 		r.underlays[overlay.ByteString()] = peerID
 		r.overlays[peerID] = overlay
+
+		direction := p2p.DirectionOutbound
+		if c.Stat().Direction == network.DirInbound {
+			direction = p2p.DirectionInbound
+		}
+		r.infos[peerID] = &peerInfo{
+			direction:   direction,
+			connectedAt: time.Now(),
+			light:       light,
+			protocols:   make(map[string]*protocolStats),
+		}
 		return false
 	}
 
@@ -161,6 +246,7 @@ func (r *peerRegistry) remove(peerID libp2ppeer.ID) {
 	overlay, found := r.overlays[peerID]
 	delete(r.overlays, peerID)
 	delete(r.underlays, overlay.ByteString())
+	delete(r.infos, peerID)
 	delete(r.connections, peerID)
 	for _, cancel := range r.streams[peerID] {
 		cancel()
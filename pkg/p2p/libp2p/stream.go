@@ -5,6 +5,8 @@
 package libp2p
 
 import (
+	"sync/atomic"
+
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/libp2p/go-libp2p-core/helpers"
 	"github.com/libp2p/go-libp2p-core/network"
@@ -15,15 +17,33 @@ var _ p2p.Stream = (*stream)(nil)
 type stream struct {
 	network.Stream
 	headers map[string][]byte
+	stats   *protocolStats // nil if the traffic is not counted
 }
 
 func NewStream(s network.Stream) p2p.Stream {
 	return &stream{Stream: s}
 }
 
-func newStream(s network.Stream) *stream {
-	return &stream{Stream: s}
+func newStream(s network.Stream, stats *protocolStats) *stream {
+	return &stream{Stream: s, stats: stats}
 }
+
+func (s *stream) Read(p []byte) (int, error) {
+	n, err := s.Stream.Read(p)
+	if s.stats != nil {
+		atomic.AddUint64(&s.stats.received, uint64(n))
+	}
+	return n, err
+}
+
+func (s *stream) Write(p []byte) (int, error) {
+	n, err := s.Stream.Write(p)
+	if s.stats != nil {
+		atomic.AddUint64(&s.stats.sent, uint64(n))
+	}
+	return n, err
+}
+
 func (s *stream) Headers() p2p.Headers {
 	return s.headers
 }
@@ -31,3 +51,10 @@ func (s *stream) Headers() p2p.Headers {
 func (s *stream) FullClose() error {
 	return helpers.FullClose(s)
 }
+
+// protocolStats counts the bytes exchanged with a peer over
+// streams of a protocol.
+type protocolStats struct {
+	sent     uint64 // accessed atomically
+	received uint64 // accessed atomically
+}
@@ -20,6 +20,7 @@ type Service struct {
 	connectFunc     func(ctx context.Context, addr ma.Multiaddr) (address *bzz.Address, err error)
 	disconnectFunc  func(overlay swarm.Address) error
 	peersFunc       func() []p2p.Peer
+	peerFunc        func(overlay swarm.Address) (p2p.PeerInfo, error)
 	setNotifierFunc func(topology.Notifier)
 	addressesFunc   func() ([]ma.Multiaddr, error)
 }
@@ -48,6 +49,12 @@ func WithPeersFunc(f func() []p2p.Peer) Option {
 	})
 }
 
+func WithPeerFunc(f func(overlay swarm.Address) (p2p.PeerInfo, error)) Option {
+	return optionFunc(func(s *Service) {
+		s.peerFunc = f
+	})
+}
+
 func WithSetNotifierFunc(f func(topology.Notifier)) Option {
 	return optionFunc(func(s *Service) {
 		s.setNotifierFunc = f
@@ -111,6 +118,13 @@ func (s *Service) Peers() []p2p.Peer {
 	return s.peersFunc()
 }
 
+func (s *Service) Peer(overlay swarm.Address) (p2p.PeerInfo, error) {
+	if s.peerFunc == nil {
+		return p2p.PeerInfo{}, errors.New("function Peer not configured")
+	}
+	return s.peerFunc(overlay)
+}
+
 type Option interface {
 	apply(*Service)
 }
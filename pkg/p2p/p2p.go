@@ -7,6 +7,7 @@ package p2p
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/bzz"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -20,10 +21,17 @@ type Service interface {
 	Connect(ctx context.Context, addr ma.Multiaddr) (address *bzz.Address, err error)
 	Disconnect(overlay swarm.Address) error
 	Peers() []Peer
+	Peer(overlay swarm.Address) (PeerInfo, error)
 	SetNotifier(topology.Notifier)
 	Addresses() ([]ma.Multiaddr, error)
 }
 
+// Penalizer is notified about peers that misbehave in protocol
+// exchanges, for example by sending invalid chunks.
+type Penalizer interface {
+	Penalize(peer swarm.Address, reason error)
+}
+
 // Streamer is able to create a new Stream.
 type Streamer interface {
 	NewStream(ctx context.Context, address swarm.Address, h Headers, protocol, version, stream string) (Stream, error)
@@ -57,6 +65,38 @@ type Peer struct {
 	Address swarm.Address `json:"address"`
 }
 
+// Direction is the direction in which the connection with a Peer was
+// established.
+type Direction string
+
+// Connection directions.
+const (
+	DirectionInbound  Direction = "inbound"
+	DirectionOutbound Direction = "outbound"
+)
+
+// PeerInfo holds information about the connection with a Peer.
+type PeerInfo struct {
+	Peer
+	Underlays   []ma.Multiaddr
+	Direction   Direction
+	ConnectedAt time.Time
+	Light       bool
+	// Protocols holds the traffic with the Peer by protocol name.
+	Protocols map[string]ProtocolStats
+	// BreakerClosedUntil is the time until which no new connections are
+	// made, after too many of them have failed, zero if they are allowed.
+	// The breaker is shared by all peers.
+	BreakerClosedUntil time.Time
+}
+
+// ProtocolStats holds the number of bytes exchanged with a Peer over
+// streams of a protocol.
+type ProtocolStats struct {
+	BytesSent     uint64
+	BytesReceived uint64
+}
+
 // HandlerFunc handles a received Stream from a Peer.
 type HandlerFunc func(context.Context, Peer, Stream) error
 
@@ -0,0 +1,11 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package penalty
+
+import "time"
+
+func (p *Penalizer) SetTimeFunc(f func() time.Time) {
+	p.now = f
+}
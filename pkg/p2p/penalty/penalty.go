@@ -0,0 +1,132 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package penalty provides a p2p.Penalizer implementation that
+// disconnects peers which misbehave repeatedly.
+package penalty
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var _ p2p.Penalizer = (*Penalizer)(nil)
+
+const (
+	// DefaultThreshold is the default number of penalties within the
+	// window after which the peer is disconnected.
+	DefaultThreshold = 3
+	// DefaultWindow is the default period after which the
+	// penalties of a peer are forgotten.
+	DefaultWindow = 10 * time.Minute
+)
+
+// Disconnecter disconnects peers.
+type Disconnecter interface {
+	Disconnect(overlay swarm.Address) error
+}
+
+// Penalizer counts penalties per peer and disconnects the peer when the
+// number of penalties within the window reaches the threshold.
+type Penalizer struct {
+	disconnecter Disconnecter
+	threshold    int
+	window       time.Duration
+	logger       logging.Logger
+
+	mu      sync.Mutex
+	records map[string]*record
+
+	now func() time.Time // used to mock time in tests
+}
+
+type record struct {
+	count int
+	since time.Time
+}
+
+// Options are optional parameters for the Penalizer.
+// Zero values are replaced by defaults.
+type Options struct {
+	Threshold int
+	Window    time.Duration
+}
+
+// New constructs a new Penalizer.
+func New(d Disconnecter, logger logging.Logger, o Options) *Penalizer {
+	if o.Threshold <= 0 {
+		o.Threshold = DefaultThreshold
+	}
+	if o.Window <= 0 {
+		o.Window = DefaultWindow
+	}
+	return &Penalizer{
+		disconnecter: d,
+		threshold:    o.Threshold,
+		window:       o.Window,
+		logger:       logger,
+		records:      make(map[string]*record),
+		now:          time.Now,
+	}
+}
+
+// Penalize records a penalty for the peer and disconnects it if the
+// threshold is reached.
+func (p *Penalizer) Penalize(peer swarm.Address, reason error) {
+	p.logger.Debugf("penalty: peer %s: %v", peer, reason)
+
+	if !p.add(peer) {
+		return
+	}
+
+	p.logger.Tracef("penalty: disconnecting peer %s", peer)
+	if err := p.disconnecter.Disconnect(peer); err != nil {
+		p.logger.Debugf("penalty: disconnect peer %s: %v", peer, err)
+		return
+	}
+	p.logger.Warningf("disconnected peer %s for misbehaving", peer)
+}
+
+// Penalties returns the number of penalties currently recorded for the peer.
+func (p *Penalizer) Penalties(peer swarm.Address) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	r, ok := p.records[peer.ByteString()]
+	if !ok || p.now().Sub(r.since) > p.window {
+		return 0
+	}
+	return r.count
+}
+
+// add increments the penalty count for the peer and reports if the
+// threshold has been reached, in which case the count is reset.
+func (p *Penalizer) add(peer swarm.Address) (disconnect bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	key := peer.ByteString()
+	r, ok := p.records[key]
+	if !ok || now.Sub(r.since) > p.window {
+		// forget expired penalties of all peers
+		for k, v := range p.records {
+			if now.Sub(v.since) > p.window {
+				delete(p.records, k)
+			}
+		}
+		r = &record{since: now}
+		p.records[key] = r
+	}
+	r.count++
+	if r.count < p.threshold {
+		return false
+	}
+	delete(p.records, key)
+	return true
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package penalty_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/p2p/penalty"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var errTest = errors.New("test error")
+
+func TestPenalize(t *testing.T) {
+	peer := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	other := swarm.MustParseHexAddress("2a1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+
+	d := new(disconnecter)
+	p := penalty.New(d, logging.New(ioutil.Discard, 0), penalty.Options{Threshold: 3})
+
+	p.Penalize(peer, errTest)
+	p.Penalize(peer, errTest)
+	p.Penalize(other, errTest)
+	if len(d.disconnected) != 0 {
+		t.Fatalf("got %d disconnects, want none", len(d.disconnected))
+	}
+	if got := p.Penalties(peer); got != 2 {
+		t.Fatalf("got %d penalties, want %d", got, 2)
+	}
+
+	p.Penalize(peer, errTest)
+	if len(d.disconnected) != 1 || !d.disconnected[0].Equal(peer) {
+		t.Fatalf("got disconnected %v, want %v", d.disconnected, peer)
+	}
+	if got := p.Penalties(peer); got != 0 {
+		t.Fatalf("got %d penalties after disconnect, want none", got)
+	}
+	if got := p.Penalties(other); got != 1 {
+		t.Fatalf("got %d penalties, want %d", got, 1)
+	}
+}
+
+func TestPenalizeWindow(t *testing.T) {
+	peer := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+
+	d := new(disconnecter)
+	p := penalty.New(d, logging.New(ioutil.Discard, 0), penalty.Options{Threshold: 2, Window: time.Minute})
+
+	now := time.Now()
+	p.SetTimeFunc(func() time.Time { return now })
+
+	p.Penalize(peer, errTest)
+
+	now = now.Add(2 * time.Minute)
+	if got := p.Penalties(peer); got != 0 {
+		t.Fatalf("got %d penalties after window, want none", got)
+	}
+
+	p.Penalize(peer, errTest)
+	if len(d.disconnected) != 0 {
+		t.Fatalf("got %d disconnects, want none", len(d.disconnected))
+	}
+
+	p.Penalize(peer, errTest)
+	if len(d.disconnected) != 1 {
+		t.Fatalf("got %d disconnects, want %d", len(d.disconnected), 1)
+	}
+}
+
+type disconnecter struct {
+	disconnected []swarm.Address
+}
+
+func (d *disconnecter) Disconnect(overlay swarm.Address) error {
+	d.disconnected = append(d.disconnected, overlay)
+	return nil
+}
@@ -6,6 +6,7 @@ package mock
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -13,12 +14,33 @@ import (
 
 type Service struct {
 	pingFunc func(ctx context.Context, address swarm.Address, msgs ...string) (rtt time.Duration, err error)
+	rtts     map[string]time.Duration
+	mu       sync.Mutex
 }
 
 func New(pingFunc func(ctx context.Context, address swarm.Address, msgs ...string) (rtt time.Duration, err error)) *Service {
-	return &Service{pingFunc: pingFunc}
+	return &Service{
+		pingFunc: pingFunc,
+		rtts:     make(map[string]time.Duration),
+	}
 }
 
 func (s *Service) Ping(ctx context.Context, address swarm.Address, msgs ...string) (rtt time.Duration, err error) {
-	return s.pingFunc(ctx, address, msgs...)
+	rtt, err = s.pingFunc(ctx, address, msgs...)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.rtts[address.ByteString()] = rtt
+	s.mu.Unlock()
+	return rtt, nil
+}
+
+func (s *Service) RTT(address swarm.Address) (rtt time.Duration, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rtt, ok = s.rtts[address.ByteString()]
+	return rtt, ok
 }
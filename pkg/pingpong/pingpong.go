@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/logging"
@@ -33,6 +34,8 @@ type Service struct {
 	logger   logging.Logger
 	tracer   *tracing.Tracer
 	metrics  metrics
+	rtts     map[string]time.Duration // last round trip times by overlay address of connected peers
+	rttsMu   sync.RWMutex
 }
 
 type Options struct {
@@ -47,6 +50,7 @@ func New(o Options) *Service {
 		logger:   o.Logger,
 		tracer:   o.Tracer,
 		metrics:  newMetrics(),
+		rtts:     make(map[string]time.Duration),
 	}
 }
 
@@ -97,7 +101,29 @@ func (s *Service) Ping(ctx context.Context, address swarm.Address, msgs ...strin
 		logger.Tracef("got pong: %q", pong.Response)
 		s.metrics.PongReceivedCount.Inc()
 	}
-	return time.Since(start), nil
+
+	rtt = time.Since(start)
+	s.rttsMu.Lock()
+	s.rtts[address.ByteString()] = rtt
+	s.rttsMu.Unlock()
+	return rtt, nil
+}
+
+// RTT returns the round trip time of the last successful ping to the peer.
+func (s *Service) RTT(address swarm.Address) (rtt time.Duration, ok bool) {
+	s.rttsMu.RLock()
+	defer s.rttsMu.RUnlock()
+
+	rtt, ok = s.rtts[address.ByteString()]
+	return rtt, ok
+}
+
+// Disconnected removes the round trip time of the disconnected peer.
+func (s *Service) Disconnected(address swarm.Address) {
+	s.rttsMu.Lock()
+	defer s.rttsMu.Unlock()
+
+	delete(s.rtts, address.ByteString())
 }
 
 func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) error {
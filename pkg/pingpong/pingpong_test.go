@@ -64,6 +64,20 @@ func TestPing(t *testing.T) {
 		t.Errorf("invalid RTT value %v", rtt)
 	}
 
+	// check that the RTT of the last ping is recorded
+	if got, ok := client.RTT(addr); !ok || got != rtt {
+		t.Errorf("got last RTT %v (%v), want %v", got, ok, rtt)
+	}
+	if _, ok := server.RTT(addr); ok {
+		t.Error("got last RTT of a peer that was not pinged")
+	}
+
+	// check that the RTT is removed when the peer disconnects
+	client.Disconnected(addr)
+	if _, ok := client.RTT(addr); ok {
+		t.Error("got last RTT of a disconnected peer")
+	}
+
 	// get a record for this stream
 	records, err := recorder.Records(addr, "pingpong", "1.0.0", "pingpong")
 	if err != nil {
@@ -8,12 +8,15 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/intervalstore"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/pullsync"
+	"github.com/ethersphere/bee/pkg/statestore"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/topology"
@@ -54,7 +57,7 @@ type Puller struct {
 
 func New(o Options) *Puller {
 	p := &Puller{
-		statestore: o.StateStore,
+		statestore: statestore.WithNamespace(o.StateStore, statestore.PullerNamespace),
 		topology:   o.Topology,
 		syncer:     o.PullSync,
 		logger:     o.Logger,
@@ -527,6 +530,93 @@ func peerIntervalKey(peer swarm.Address, bin uint8) string {
 	return k
 }
 
+// ResetIntervals removes the synced intervals of all peers from the state
+// store, so that chunks are synced again after they are removed from the
+// local store.
+func ResetIntervals(s storage.StateStorer) error {
+	var keys []string
+	if err := s.Iterate(string(statestore.PullerNamespace), func(key, _ []byte) (stop bool, err error) {
+		keys = append(keys, string(key))
+		return false, nil
+	}); err != nil {
+		return err
+	}
+	legacy, err := legacyIntervals(s)
+	if err != nil {
+		return err
+	}
+	return s.Update(func(tx storage.StateTx) error {
+		for _, k := range keys {
+			if err := tx.Delete(k); err != nil {
+				return err
+			}
+		}
+		for k := range legacy {
+			if err := tx.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MigrateIntervals moves the synced intervals that are stored without the
+// puller namespace into the namespace in a single transaction, and returns
+// the number of moved intervals.
+func MigrateIntervals(s storage.StateStorer) (count int, err error) {
+	legacy, err := legacyIntervals(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(legacy) == 0 {
+		return 0, nil
+	}
+	err = s.Update(func(tx storage.StateTx) error {
+		for k, v := range legacy {
+			if err := tx.Put(string(statestore.PullerNamespace)+k, v); err != nil {
+				return err
+			}
+			if err := tx.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(legacy), nil
+}
+
+// legacyIntervals returns the synced intervals that are stored
+// without the puller namespace by their keys.
+func legacyIntervals(s storage.StateStorer) (map[string]statestore.RawValue, error) {
+	intervals := make(map[string]statestore.RawValue)
+	if err := s.Iterate("", func(key, value []byte) (stop bool, err error) {
+		if isPeerIntervalKey(string(key)) {
+			intervals[string(key)] = append(statestore.RawValue(nil), value...)
+		}
+		return false, nil
+	}); err != nil {
+		return nil, err
+	}
+	return intervals, nil
+}
+
+// isPeerIntervalKey reports whether the state store key is constructed
+// by peerIntervalKey.
+func isPeerIntervalKey(key string) bool {
+	i := strings.IndexByte(key, '|')
+	if i != 2*swarm.HashSize {
+		return false
+	}
+	if _, err := swarm.ParseHexAddress(key[:i]); err != nil {
+		return false
+	}
+	_, err := strconv.ParseUint(key[i+1:], 10, 8)
+	return err == nil
+}
+
 type syncPeer struct {
 	address        swarm.Address
 	binCancelFuncs map[uint8]func() // slice of context cancel funcs for historical sync. index is bin
@@ -6,6 +6,7 @@ package puller_test
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"math"
 	"runtime"
@@ -17,6 +18,7 @@ import (
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/puller"
 	mockps "github.com/ethersphere/bee/pkg/pullsync/mock"
+	"github.com/ethersphere/bee/pkg/statestore"
 	"github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -401,6 +403,93 @@ func TestDepthChange(t *testing.T) {
 	}
 }
 
+// TestResetIntervals validates that intervals of all peers
+// are removed, leaving other state store entries.
+func TestResetIntervals(t *testing.T) {
+	s := mock.NewStateStore()
+	ns := statestore.WithNamespace(s, statestore.PullerNamespace)
+	addr := test.RandomAddress()
+	for _, b := range []uint8{0, 3, 15} {
+		if err := ns.Put(puller.PeerIntervalKey(addr, b), intervalstore.NewIntervals(1)); err != nil {
+			t.Fatal(err)
+		}
+		// intervals stored without the namespace
+		if err := s.Put(puller.PeerIntervalKey(addr, b), intervalstore.NewIntervals(1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, k := range []string{"overlay", addr.String(), "addressbook_entry_" + addr.String()} {
+		if err := s.Put(k, "value"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := puller.ResetIntervals(s); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b := range []uint8{0, 3, 15} {
+		for _, store := range []storage.StateStorer{s, ns} {
+			err := store.Get(puller.PeerIntervalKey(addr, b), &intervalstore.Intervals{})
+			if !errors.Is(err, storage.ErrNotFound) {
+				t.Errorf("bin %v: got error %v, want %v", b, err, storage.ErrNotFound)
+			}
+		}
+	}
+	for _, k := range []string{"overlay", addr.String(), "addressbook_entry_" + addr.String()} {
+		var v string
+		if err := s.Get(k, &v); err != nil {
+			t.Errorf("key %q: %v", k, err)
+		}
+	}
+}
+
+// TestMigrateIntervals validates that intervals stored without
+// the namespace are moved into the namespace.
+func TestMigrateIntervals(t *testing.T) {
+	s := mock.NewStateStore()
+	addr := test.RandomAddress()
+	for _, b := range []uint8{0, 3, 15} {
+		i := intervalstore.NewIntervals(1)
+		i.Add(1, uint64(b)+1)
+		if err := s.Put(puller.PeerIntervalKey(addr, b), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Put("overlay", "value"); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := puller.MigrateIntervals(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("got %v migrated intervals, want 3", count)
+	}
+
+	ns := statestore.WithNamespace(s, statestore.PullerNamespace)
+	for _, b := range []uint8{0, 3, 15} {
+		checkIntervals(t, ns, addr, fmt.Sprintf("[[1 %d]]", b+1), b)
+		err := s.Get(puller.PeerIntervalKey(addr, b), &intervalstore.Intervals{})
+		if !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("bin %v: got error %v, want %v", b, err, storage.ErrNotFound)
+		}
+	}
+	var v string
+	if err := s.Get("overlay", &v); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err = puller.MigrateIntervals(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("got %v migrated intervals, want 0", count)
+	}
+}
+
 func checkIntervals(t *testing.T, s storage.StateStorer, addr swarm.Address, expInterval string, bin uint8) {
 	t.Helper()
 
@@ -584,7 +673,7 @@ func newPuller(ops opts) (*puller.Puller, storage.StateStorer, *mockk.Mock, *moc
 		PullSync:   ps,
 		Logger:     logger,
 	}
-	return puller.New(o), s, kad, ps
+	return puller.New(o), statestore.WithNamespace(s, statestore.PullerNamespace), kad, ps
 }
 
 type c struct {
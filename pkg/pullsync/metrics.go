@@ -0,0 +1,41 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pullsync
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	// all metrics fields must be exported
+	// to be able to return them by Metrics()
+	// using reflection
+	DeliveryCounter     prometheus.Counter
+	InvalidChunkCounter prometheus.Counter
+}
+
+func newMetrics() metrics {
+	subsystem := "pullsync"
+
+	return metrics{
+		DeliveryCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "chunk_delivery_count",
+			Help:      "Total chunks delivered by peers.",
+		}),
+		InvalidChunkCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "invalid_chunk_count",
+			Help:      "Total invalid chunks delivered by peers.",
+		}),
+	}
+}
+
+func (s *Syncer) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(s.metrics)
+}
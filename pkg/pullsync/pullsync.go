@@ -35,6 +35,7 @@ const (
 
 var (
 	ErrUnsolicitedChunk = errors.New("peer sent unsolicited chunk")
+	ErrInvalidChunk     = errors.New("peer sent invalid chunk")
 )
 
 // how many maximum chunks in a batch
@@ -47,11 +48,14 @@ type Interface interface {
 }
 
 type Syncer struct {
-	streamer p2p.Streamer
-	logger   logging.Logger
-	storage  pullstorage.Storer
-	quit     chan struct{}
-	wg       sync.WaitGroup
+	streamer   p2p.Streamer
+	logger     logging.Logger
+	storage    pullstorage.Storer
+	validators []swarm.ChunkValidator
+	penalizer  p2p.Penalizer
+	metrics    metrics
+	quit       chan struct{}
+	wg         sync.WaitGroup
 
 	ruidMtx sync.Mutex
 	ruidCtx map[uint32]func()
@@ -63,18 +67,27 @@ type Syncer struct {
 type Options struct {
 	Streamer p2p.Streamer
 	Storage  pullstorage.Storer
+	// Validators are used to validate delivered chunks before they are
+	// stored. A chunk is valid if any of them validates it. If no
+	// validators are set, delivered chunks are not validated.
+	Validators []swarm.ChunkValidator
+	// Penalizer is notified about peers that deliver invalid chunks.
+	Penalizer p2p.Penalizer
 
 	Logger logging.Logger
 }
 
 func New(o Options) *Syncer {
 	return &Syncer{
-		streamer: o.Streamer,
-		storage:  o.Storage,
-		logger:   o.Logger,
-		ruidCtx:  make(map[uint32]func()),
-		wg:       sync.WaitGroup{},
-		quit:     make(chan struct{}),
+		streamer:   o.Streamer,
+		storage:    o.Storage,
+		validators: o.Validators,
+		penalizer:  o.Penalizer,
+		logger:     o.Logger,
+		metrics:    newMetrics(),
+		ruidCtx:    make(map[uint32]func()),
+		wg:         sync.WaitGroup{},
+		quit:       make(chan struct{}),
 	}
 }
 
@@ -196,8 +209,18 @@ func (s *Syncer) SyncInterval(ctx context.Context, peer swarm.Address, bin uint8
 		}
 
 		delete(wantChunks, addr.String())
+		s.metrics.DeliveryCounter.Inc()
+
+		chunk := swarm.NewChunk(addr, delivery.Data)
+		if !swarm.ValidateChunk(chunk, s.validators...) {
+			s.metrics.InvalidChunkCounter.Inc()
+			if s.penalizer != nil {
+				s.penalizer.Penalize(peer, fmt.Errorf("pullsync chunk %s: %w", addr, ErrInvalidChunk))
+			}
+			return 0, ru.Ruid, ErrInvalidChunk
+		}
 
-		if err = s.storage.Put(ctx, storage.ModePutSync, swarm.NewChunk(addr, delivery.Data)); err != nil {
+		if err = s.storage.Put(ctx, storage.ModePutSync, chunk); err != nil {
 			return 0, ru.Ruid, fmt.Errorf("delivery put: %w", err)
 		}
 	}
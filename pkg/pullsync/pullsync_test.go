@@ -18,6 +18,7 @@ import (
 	"github.com/ethersphere/bee/pkg/p2p/streamtest"
 	"github.com/ethersphere/bee/pkg/pullsync"
 	"github.com/ethersphere/bee/pkg/pullsync/pullstorage/mock"
+	mockvalidator "github.com/ethersphere/bee/pkg/storage/mock/validator"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
@@ -167,6 +168,41 @@ func TestIncoming_UnsolicitedChunk(t *testing.T) {
 	}
 }
 
+func TestIncoming_InvalidChunk(t *testing.T) {
+	var (
+		mockTopmost = uint64(5)
+		ps, _       = newPullSync(nil, mock.WithIntervalsResp(addrs, mockTopmost, nil), mock.WithChunks(chunks...))
+		recorder    = streamtest.New(streamtest.WithProtocols(ps.Protocol()))
+		clientDb    = mock.NewPullStorage()
+		penalizer   = new(mockPenalizer)
+		validator   = mockvalidator.NewMockValidator(chunks[0].Address(), chunks[0].Data())
+	)
+	validator.AddPair(chunks[1].Address(), chunks[1].Data())
+
+	psClient := pullsync.New(pullsync.Options{
+		Streamer:   recorder,
+		Storage:    clientDb,
+		Validators: []swarm.ChunkValidator{validator},
+		Penalizer:  penalizer,
+		Logger:     logging.New(ioutil.Discard, 0),
+	})
+
+	_, _, err := psClient.SyncInterval(context.Background(), swarm.ZeroAddress, 0, 0, 5)
+	if !errors.Is(err, pullsync.ErrInvalidChunk) {
+		t.Fatalf("expected ErrInvalidChunk but got %v", err)
+	}
+
+	// only the valid chunks delivered before the invalid one are stored
+	haveChunks(t, clientDb, addrs[:2]...)
+	if p := clientDb.PutCalls(); p != 2 {
+		t.Fatalf("want %d puts but got %d", 2, p)
+	}
+
+	if len(penalizer.peers) != 1 || !penalizer.peers[0].Equal(swarm.ZeroAddress) {
+		t.Fatalf("got penalized peers %v, want %v", penalizer.peers, swarm.ZeroAddress)
+	}
+}
+
 func TestGetCursors(t *testing.T) {
 	var (
 		mockCursors = []uint64{100, 101, 102, 103}
@@ -227,6 +263,14 @@ func newPullSync(s p2p.Streamer, o ...mock.Option) (*pullsync.Syncer, *mock.Pull
 	return pullsync.New(pullsync.Options{Streamer: s, Storage: storage, Logger: logger}), storage
 }
 
+type mockPenalizer struct {
+	peers []swarm.Address
+}
+
+func (p *mockPenalizer) Penalize(peer swarm.Address, _ error) {
+	p.peers = append(p.peers, peer)
+}
+
 func waitSet(t *testing.T, db *mock.PullStorage, v int) {
 	time.Sleep(10 * time.Millisecond) // give leeway for the case where v==0
 	var s int
@@ -37,6 +37,8 @@ type PushSync struct {
 	streamer      p2p.Streamer
 	storer        storage.Putter
 	peerSuggester topology.ClosestPeerer
+	validators    []swarm.ChunkValidator
+	penalizer     p2p.Penalizer
 	logger        logging.Logger
 	metrics       metrics
 }
@@ -45,7 +47,13 @@ type Options struct {
 	Streamer      p2p.Streamer
 	Storer        storage.Putter
 	ClosestPeerer topology.ClosestPeerer
-	Logger        logging.Logger
+	// Validators are used to validate received chunks before they are
+	// stored or forwarded. A chunk is valid if any of them validates it.
+	// If no validators are set, received chunks are not validated.
+	Validators []swarm.ChunkValidator
+	// Penalizer is notified about peers that deliver invalid chunks.
+	Penalizer p2p.Penalizer
+	Logger    logging.Logger
 }
 
 var timeToWaitForReceipt = 3 * time.Second // time to wait to get a receipt for a chunk
@@ -55,6 +63,8 @@ func New(o Options) *PushSync {
 		streamer:      o.Streamer,
 		storer:        o.Storer,
 		peerSuggester: o.ClosestPeerer,
+		validators:    o.Validators,
+		penalizer:     o.Penalizer,
 		logger:        o.Logger,
 		metrics:       newMetrics(),
 	}
@@ -86,6 +96,15 @@ func (ps *PushSync) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream)
 		return fmt.Errorf("chunk delivery from peer %s: %w", p.Address.String(), err)
 	}
 
+	if !swarm.ValidateChunk(chunk, ps.validators...) {
+		ps.metrics.InvalidChunkReceived.Inc()
+		err := fmt.Errorf("chunk %s: %w", chunk.Address(), storage.ErrInvalidChunk)
+		if ps.penalizer != nil {
+			ps.penalizer.Penalize(p.Address, err)
+		}
+		return fmt.Errorf("chunk delivery from peer %s: %w", p.Address.String(), err)
+	}
+
 	// Select the closest peer to forward the chunk
 	peer, err := ps.peerSuggester.ClosestPeer(chunk.Address())
 	if err != nil {
@@ -7,6 +7,7 @@ package pushsync_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io/ioutil"
 	"testing"
 
@@ -16,6 +17,8 @@ import (
 	"github.com/ethersphere/bee/pkg/p2p/streamtest"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	"github.com/ethersphere/bee/pkg/pushsync/pb"
+	"github.com/ethersphere/bee/pkg/storage"
+	mockvalidator "github.com/ethersphere/bee/pkg/storage/mock/validator"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/topology"
 	"github.com/ethersphere/bee/pkg/topology/mock"
@@ -119,7 +122,56 @@ func TestHandler(t *testing.T) {
 	waitOnRecordAndTest(t, pivotPeer, pivotRecorder, chunkAddress, nil)
 }
 
+// TestHandlerInvalidChunk expects that a chunk which does not pass validation
+// is neither stored nor forwarded, and that the sending peer is penalized.
+func TestHandlerInvalidChunk(t *testing.T) {
+	chunkAddress := swarm.MustParseHexAddress("7000000000000000000000000000000000000000000000000000000000000000")
+	chunkData := []byte("1234")
+	chunk := swarm.NewChunk(chunkAddress, chunkData)
+
+	pivotPeer := swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000")
+	triggerPeer := swarm.MustParseHexAddress("6000000000000000000000000000000000000000000000000000000000000000")
+
+	// the pivot peer only accepts a different data for the chunk address
+	penalizer := new(mockPenalizer)
+	psPivot, storerPivotDB := createPushSyncNodeWithOptions(t, pivotPeer, nil, func(o *pushsync.Options) {
+		o.Validators = []swarm.ChunkValidator{mockvalidator.NewMockValidator(chunkAddress, []byte("4321"))}
+		o.Penalizer = penalizer
+	}, mock.WithClosestPeerErr(topology.ErrWantSelf))
+	defer storerPivotDB.Close()
+
+	pivotRecorder := streamtest.New(streamtest.WithProtocols(psPivot.Protocol()))
+
+	psTriggerPeer, triggerStorerDB := createPushSyncNode(t, triggerPeer, pivotRecorder, mock.WithClosestPeer(pivotPeer))
+	defer triggerStorerDB.Close()
+
+	if _, err := psTriggerPeer.PushChunkToClosest(context.Background(), chunk); err == nil {
+		t.Fatal("expected error")
+	}
+
+	records := pivotRecorder.WaitRecords(t, pivotPeer, pushsync.ProtocolName, pushsync.ProtocolVersion, pushsync.StreamName, 1, 5)
+	if err := records[0].Err(); !errors.Is(err, storage.ErrInvalidChunk) {
+		t.Fatalf("got handler error %v, want %v", err, storage.ErrInvalidChunk)
+	}
+
+	has, err := storerPivotDB.Has(context.Background(), chunkAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has {
+		t.Fatal("invalid chunk stored")
+	}
+
+	if len(penalizer.peers) != 1 || !penalizer.peers[0].Equal(pivotPeer) {
+		t.Fatalf("got penalized peers %v, want %v", penalizer.peers, pivotPeer)
+	}
+}
+
 func createPushSyncNode(t *testing.T, addr swarm.Address, recorder *streamtest.Recorder, mockOpts ...mock.Option) (*pushsync.PushSync, *localstore.DB) {
+	return createPushSyncNodeWithOptions(t, addr, recorder, nil, mockOpts...)
+}
+
+func createPushSyncNodeWithOptions(t *testing.T, addr swarm.Address, recorder *streamtest.Recorder, opts func(*pushsync.Options), mockOpts ...mock.Option) (*pushsync.PushSync, *localstore.DB) {
 	logger := logging.New(ioutil.Discard, 0)
 
 	storer, err := localstore.New("", addr.Bytes(), nil, logger)
@@ -129,16 +181,28 @@ func createPushSyncNode(t *testing.T, addr swarm.Address, recorder *streamtest.R
 
 	mockTopology := mock.NewTopologyDriver(mockOpts...)
 
-	ps := pushsync.New(pushsync.Options{
+	o := pushsync.Options{
 		Streamer:      recorder,
 		Storer:        storer,
 		ClosestPeerer: mockTopology,
 		Logger:        logger,
-	})
+	}
+	if opts != nil {
+		opts(&o)
+	}
+	ps := pushsync.New(o)
 
 	return ps, storer
 }
 
+type mockPenalizer struct {
+	peers []swarm.Address
+}
+
+func (p *mockPenalizer) Penalize(peer swarm.Address, _ error) {
+	p.peers = append(p.peers, peer)
+}
+
 func waitOnRecordAndTest(t *testing.T, peer swarm.Address, recorder *streamtest.Recorder, add swarm.Address, data []byte) {
 	t.Helper()
 	records := recorder.WaitRecords(t, peer, pushsync.ProtocolName, pushsync.ProtocolVersion, pushsync.StreamName, 1, 5)
@@ -0,0 +1,83 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Cache is a resolver that keeps the results of another resolver for the
+// configured time to live. Names that are not found are cached as well, so
+// that repeated lookups of unknown names do not reach the underlying
+// resolver. Other errors are not cached.
+type Cache struct {
+	resolver Interface
+	ttl      time.Duration
+	entries  map[string]cacheEntry
+	mu       sync.Mutex
+
+	now func() time.Time // used to mock time in tests
+}
+
+type cacheEntry struct {
+	addr    swarm.Address
+	found   bool
+	expires time.Time
+}
+
+// NewCache wraps the resolver with a cache of results with a time to live.
+func NewCache(r Interface, ttl time.Duration) *Cache {
+	return &Cache{
+		resolver: r,
+		ttl:      ttl,
+		entries:  make(map[string]cacheEntry),
+		now:      time.Now,
+	}
+}
+
+// Resolve returns the cached result for the name or resolves it using
+// the underlying resolver.
+func (c *Cache) Resolve(ctx context.Context, name string) (swarm.Address, error) {
+	c.mu.Lock()
+	e, ok := c.entries[name]
+	if ok && c.now().Before(e.expires) {
+		c.mu.Unlock()
+		if !e.found {
+			return swarm.ZeroAddress, ErrNotFound
+		}
+		return e.addr, nil
+	}
+	c.mu.Unlock()
+
+	addr, err := c.resolver.Resolve(ctx, name)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return swarm.ZeroAddress, err
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	// remove expired entries not to keep all names ever requested
+	for n, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, n)
+		}
+	}
+	c.entries[name] = cacheEntry{
+		addr:    addr,
+		found:   err == nil,
+		expires: now.Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		return swarm.ZeroAddress, ErrNotFound
+	}
+	return addr, nil
+}
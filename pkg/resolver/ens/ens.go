@@ -0,0 +1,261 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ens provides a resolver of ENS names which queries the ENS
+// contracts through the JSON-RPC API of an Ethereum compatible endpoint.
+//
+// The name is resolved by asking the ENS registry for the resolver contract
+// of the name and then asking the resolver contract for the content hash of
+// the name, as defined by EIP-1577. Only Swarm content hashes are supported.
+package ens
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/crypto/sha3"
+)
+
+var _ resolver.Interface = (*Resolver)(nil)
+
+// DefaultRegistryAddress is the address of the ENS registry
+// on the Ethereum mainnet and public test networks.
+const DefaultRegistryAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+var (
+	// ErrUnsupportedContentHash is returned when the content hash
+	// of the name is not a Swarm reference.
+	ErrUnsupportedContentHash = errors.New("ens: unsupported content hash")
+
+	// swarmContentHashPrefix is the EIP-1577 content hash prefix of
+	// Swarm references: swarm-ns codec, cid version 1, swarm-manifest
+	// codec and keccak-256 multihash of 32 bytes.
+	swarmContentHashPrefix = []byte{0xe4, 0x01, 0x01, 0xfa, 0x01, 0x1b, 0x20}
+
+	resolverMethodID    = methodID("resolver(bytes32)")
+	contentHashMethodID = methodID("contenthash(bytes32)")
+)
+
+// Resolver resolves ENS names to Swarm references.
+type Resolver struct {
+	endpoint string
+	registry string
+	client   *http.Client
+	id       uint64
+}
+
+// Options are optional parameters of the Resolver.
+type Options struct {
+	// RegistryAddress is the address of the ENS registry contract.
+	// If not set, DefaultRegistryAddress is used.
+	RegistryAddress string
+	// Client is the HTTP client used for JSON-RPC calls.
+	// If not set, http.DefaultClient is used.
+	Client *http.Client
+}
+
+// New constructs a new Resolver which calls the JSON-RPC endpoint.
+func New(endpoint string, o *Options) *Resolver {
+	if o == nil {
+		o = new(Options)
+	}
+	r := &Resolver{
+		endpoint: endpoint,
+		registry: o.RegistryAddress,
+		client:   o.Client,
+	}
+	if r.registry == "" {
+		r.registry = DefaultRegistryAddress
+	}
+	if r.client == nil {
+		r.client = http.DefaultClient
+	}
+	return r
+}
+
+// Resolve returns the Swarm reference from the content hash of the ENS name.
+func (r *Resolver) Resolve(ctx context.Context, name string) (swarm.Address, error) {
+	node := NameHash(name)
+
+	result, err := r.call(ctx, r.registry, resolverMethodID, node)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("ens registry: %w", err)
+	}
+	if len(result) != 32 {
+		return swarm.ZeroAddress, fmt.Errorf("ens registry: invalid result length %d", len(result))
+	}
+	resolverAddress := result[12:]
+	if isZero(resolverAddress) {
+		return swarm.ZeroAddress, resolver.ErrNotFound
+	}
+
+	result, err = r.call(ctx, "0x"+hex.EncodeToString(resolverAddress), contentHashMethodID, node)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("ens resolver: %w", err)
+	}
+	contentHash, err := decodeBytes(result)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("ens resolver: %w", err)
+	}
+	if len(contentHash) == 0 {
+		return swarm.ZeroAddress, resolver.ErrNotFound
+	}
+	if len(contentHash) != len(swarmContentHashPrefix)+swarm.HashSize || !bytes.HasPrefix(contentHash, swarmContentHashPrefix) {
+		return swarm.ZeroAddress, ErrUnsupportedContentHash
+	}
+	return swarm.NewAddress(contentHash[len(swarmContentHashPrefix):]), nil
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcCallParams struct {
+	To   string `json:"to"`
+	Data string `json:"data"`
+}
+
+type rpcResponse struct {
+	Result string    `json:"result"`
+	Error  *rpcError `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// call executes the eth_call JSON-RPC method on the contract with the
+// method ID and a single 32 byte argument and returns the decoded result.
+func (r *Resolver) call(ctx context.Context, to string, methodID, arg []byte) ([]byte, error) {
+	data := make([]byte, 0, len(methodID)+len(arg))
+	data = append(data, methodID...)
+	data = append(data, arg...)
+
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&r.id, 1),
+		Method:  "eth_call",
+		Params: []interface{}{
+			rpcCallParams{
+				To:   to,
+				Data: "0x" + hex.EncodeToString(data),
+			},
+			"latest",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", resolver.ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("json-rpc response status %s", resp.Status)
+	}
+
+	var rr rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return nil, fmt.Errorf("decode json-rpc response: %w", err)
+	}
+	if rr.Error != nil {
+		return nil, fmt.Errorf("json-rpc error %d: %s", rr.Error.Code, rr.Error.Message)
+	}
+	result, err := hex.DecodeString(strings.TrimPrefix(rr.Result, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decode json-rpc result: %w", err)
+	}
+	return result, nil
+}
+
+// NameHash returns the ENS node of the name as defined by EIP-137.
+func NameHash(name string) []byte {
+	node := make([]byte, 32)
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		h := sha3.NewLegacyKeccak256()
+		_, _ = h.Write(node)
+		_, _ = h.Write(keccak256([]byte(labels[i])))
+		node = h.Sum(nil)
+	}
+	return node
+}
+
+// decodeBytes decodes an ABI encoded dynamic bytes return value.
+func decodeBytes(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if len(b) < 64 {
+		return nil, fmt.Errorf("invalid bytes result length %d", len(b))
+	}
+	offset, ok := decodeUint(b[:32])
+	// the checks are written so that they do not overflow for large values
+	if !ok || offset > uint64(len(b))-32 {
+		return nil, errors.New("invalid bytes result offset")
+	}
+	length, ok := decodeUint(b[offset : offset+32])
+	if !ok || length > uint64(len(b))-32-offset {
+		return nil, errors.New("invalid bytes result length")
+	}
+	return b[offset+32 : offset+32+length], nil
+}
+
+// decodeUint decodes a 32 byte ABI encoded unsigned integer
+// which must fit into 64 bits.
+func decodeUint(b []byte) (uint64, bool) {
+	if !isZero(b[:24]) {
+		return 0, false
+	}
+	var v uint64
+	for _, c := range b[24:32] {
+		v = v<<8 | uint64(c)
+	}
+	return v, true
+}
+
+func methodID(signature string) []byte {
+	return keccak256([]byte(signature))[:4]
+}
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(data)
+	return h.Sum(nil)
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,240 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ens_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resolver/ens"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestNameHash(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		hash string
+	}{
+		{"", "0000000000000000000000000000000000000000000000000000000000000000"},
+		{"eth", "93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"},
+		{"foo.eth", "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+		{"Foo.ETH", "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+	} {
+		if got := hex.EncodeToString(ens.NameHash(tc.name)); got != tc.hash {
+			t.Errorf("name %q: got hash %s, want %s", tc.name, got, tc.hash)
+		}
+	}
+}
+
+func TestMethodIDs(t *testing.T) {
+	if got := hex.EncodeToString(ens.ResolverMethodID); got != "0178b8bf" {
+		t.Errorf("got resolver method id %s", got)
+	}
+	if got := hex.EncodeToString(ens.ContentHashMethodID); got != "bc1c58d1" {
+		t.Errorf("got contenthash method id %s", got)
+	}
+}
+
+func TestDecodeBytes(t *testing.T) {
+	// word returns the 32 byte ABI encoding of the number
+	// with the last n bytes set to the byte value
+	word := func(v byte, n int) []byte {
+		w := make([]byte, 32)
+		for i := 32 - n; i < 32; i++ {
+			w[i] = v
+		}
+		return w
+	}
+	concat := func(words ...[]byte) []byte {
+		return bytes.Join(words, nil)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		result  []byte
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "valid",
+			result: concat(word(32, 1), word(3, 1), []byte{1, 2, 3}, make([]byte, 29)),
+			want:   []byte{1, 2, 3},
+		},
+		{
+			name:    "short",
+			result:  make([]byte, 32),
+			wantErr: true,
+		},
+		{
+			name:    "max offset",
+			result:  concat(word(0xff, 8), word(0, 0)),
+			wantErr: true,
+		},
+		{
+			name:    "offset out of range",
+			result:  concat(word(64, 1), word(0, 0)),
+			wantErr: true,
+		},
+		{
+			name:    "max length",
+			result:  concat(word(32, 1), word(0xff, 8)),
+			wantErr: true,
+		},
+		{
+			name:    "length out of range",
+			result:  concat(word(32, 1), word(33, 1), make([]byte, 32)),
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ens.DecodeBytes(tc.result)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("got %x, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("got %x, want %x", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	const (
+		registryAddress = "0x1111111111111111111111111111111111111111"
+		resolverAddress = "0x2222222222222222222222222222222222222222"
+	)
+	reference := swarm.MustParseHexAddress("c10090961e7682a10890c334d759a28426647141213abda93b096b892824d2ef")
+
+	stub := &rpcStub{
+		registry: registryAddress,
+		resolvers: map[string]string{
+			hex.EncodeToString(ens.NameHash("mysite.eth")): resolverAddress,
+			hex.EncodeToString(ens.NameHash("empty.eth")):  resolverAddress,
+			hex.EncodeToString(ens.NameHash("ipfs.eth")):   resolverAddress,
+		},
+		contentHashes: map[string][]byte{
+			hex.EncodeToString(ens.NameHash("mysite.eth")): append(append([]byte{}, ens.SwarmContentHashPrefix...), reference.Bytes()...),
+			hex.EncodeToString(ens.NameHash("ipfs.eth")):   append([]byte{0xe3, 0x01, 0x01, 0x70, 0x12, 0x20}, reference.Bytes()...),
+		},
+	}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	r := ens.New(server.URL, &ens.Options{RegistryAddress: registryAddress})
+
+	addr, err := r.Resolve(context.Background(), "mysite.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.Equal(reference) {
+		t.Fatalf("got address %s, want %s", addr, reference)
+	}
+
+	for _, name := range []string{"unknown.eth", "empty.eth"} {
+		if _, err := r.Resolve(context.Background(), name); !errors.Is(err, resolver.ErrNotFound) {
+			t.Fatalf("name %q: got error %v, want %v", name, err, resolver.ErrNotFound)
+		}
+	}
+
+	if _, err := r.Resolve(context.Background(), "ipfs.eth"); !errors.Is(err, ens.ErrUnsupportedContentHash) {
+		t.Fatalf("got error %v, want %v", err, ens.ErrUnsupportedContentHash)
+	}
+
+	stub.fail = true
+	if _, err := r.Resolve(context.Background(), "mysite.eth"); err == nil || errors.Is(err, resolver.ErrNotFound) || errors.Is(err, resolver.ErrUnavailable) {
+		t.Fatalf("got error %v, want json-rpc error", err)
+	}
+
+	server.Close()
+	if _, err := r.Resolve(context.Background(), "mysite.eth"); !errors.Is(err, resolver.ErrUnavailable) {
+		t.Fatalf("got error %v, want %v", err, resolver.ErrUnavailable)
+	}
+}
+
+// rpcStub is a minimal Ethereum JSON-RPC server which answers eth_call
+// requests to the ENS registry and a single resolver contract.
+type rpcStub struct {
+	registry      string
+	resolvers     map[string]string // node to resolver address
+	contentHashes map[string][]byte // node to content hash
+	fail          bool
+}
+
+func (s *rpcStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+	}
+	defer func() {
+		_ = json.NewEncoder(w).Encode(resp)
+	}()
+
+	if s.fail {
+		resp["error"] = map[string]interface{}{"code": -32000, "message": "stub failure"}
+		return
+	}
+
+	var call struct {
+		To   string `json:"to"`
+		Data string `json:"data"`
+	}
+	if req.Method != "eth_call" || len(req.Params) != 2 || json.Unmarshal(req.Params[0], &call) != nil {
+		resp["error"] = map[string]interface{}{"code": -32600, "message": "invalid request"}
+		return
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(call.Data, "0x"))
+	if err != nil || len(data) != 36 {
+		resp["error"] = map[string]interface{}{"code": -32602, "message": "invalid data"}
+		return
+	}
+	method, node := data[:4], hex.EncodeToString(data[4:])
+
+	var result []byte
+	switch {
+	case strings.EqualFold(call.To, s.registry) && bytes.Equal(method, ens.ResolverMethodID):
+		result = make([]byte, 32)
+		if a, ok := s.resolvers[node]; ok {
+			b, _ := hex.DecodeString(strings.TrimPrefix(a, "0x"))
+			copy(result[12:], b)
+		}
+	case bytes.Equal(method, ens.ContentHashMethodID):
+		contentHash := s.contentHashes[node]
+		padded := (len(contentHash) + 31) / 32 * 32
+		result = make([]byte, 64+padded)
+		result[31] = 32
+		result[63] = byte(len(contentHash))
+		copy(result[64:], contentHash)
+	default:
+		resp["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+		return
+	}
+	resp["result"] = "0x" + hex.EncodeToString(result)
+}
@@ -0,0 +1,12 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ens
+
+var (
+	ResolverMethodID       = resolverMethodID
+	ContentHashMethodID    = contentHashMethodID
+	SwarmContentHashPrefix = swarmContentHashPrefix
+	DecodeBytes            = decodeBytes
+)
@@ -0,0 +1,68 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package feed provides a resolver which looks up references
+// in the latest updates of feeds with topics derived from names.
+//
+// Feed updates are looked up by a Lookuper, so the resolver does not depend
+// on how feeds are stored. The node does not add it to its resolver chain
+// until there is a Lookuper for feeds in the local store and the network.
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/crypto/sha3"
+)
+
+var _ resolver.Interface = (*Resolver)(nil)
+
+// Lookuper returns the payload of the latest update of the feed
+// with the given topic. It should return storage.ErrNotFound if the
+// feed has no updates.
+type Lookuper interface {
+	Lookup(ctx context.Context, topic []byte) (payload []byte, err error)
+}
+
+// Resolver resolves names to references that are published as feed
+// updates. The topic of the feed is the keccak256 hash of the lowercase
+// name and the payload of the update is the reference.
+type Resolver struct {
+	lookuper Lookuper
+}
+
+// New constructs a new Resolver.
+func New(l Lookuper) *Resolver {
+	return &Resolver{
+		lookuper: l,
+	}
+}
+
+// Resolve returns the reference from the latest update of the feed for the name.
+func (r *Resolver) Resolve(ctx context.Context, name string) (swarm.Address, error) {
+	payload, err := r.lookuper.Lookup(ctx, Topic(name))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return swarm.ZeroAddress, resolver.ErrNotFound
+		}
+		return swarm.ZeroAddress, fmt.Errorf("feed lookup: %w", err)
+	}
+	if len(payload) != swarm.HashSize {
+		return swarm.ZeroAddress, fmt.Errorf("feed update for %q: invalid reference length %d", name, len(payload))
+	}
+	return swarm.NewAddress(payload), nil
+}
+
+// Topic returns the feed topic for the name.
+func Topic(name string) []byte {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write([]byte(strings.ToLower(name)))
+	return h.Sum(nil)
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feed_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resolver/feed"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestResolve(t *testing.T) {
+	reference := swarm.MustParseHexAddress("c10090961e7682a10890c334d759a28426647141213abda93b096b892824d2ef")
+	updates := map[string][]byte{
+		string(feed.Topic("mysite.eth")): reference.Bytes(),
+		string(feed.Topic("broken.eth")): []byte("short"),
+	}
+	r := feed.New(lookuperFunc(func(_ context.Context, topic []byte) ([]byte, error) {
+		payload, ok := updates[string(topic)]
+		if !ok {
+			return nil, storage.ErrNotFound
+		}
+		return payload, nil
+	}))
+
+	addr, err := r.Resolve(context.Background(), "MySite.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.Equal(reference) {
+		t.Fatalf("got address %s, want %s", addr, reference)
+	}
+
+	if _, err := r.Resolve(context.Background(), "unknown.eth"); !errors.Is(err, resolver.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, resolver.ErrNotFound)
+	}
+
+	if _, err := r.Resolve(context.Background(), "broken.eth"); err == nil || errors.Is(err, resolver.ErrNotFound) {
+		t.Fatalf("got error %v, want invalid reference error", err)
+	}
+}
+
+type lookuperFunc func(ctx context.Context, topic []byte) ([]byte, error)
+
+func (f lookuperFunc) Lookup(ctx context.Context, topic []byte) ([]byte, error) {
+	return f(ctx, topic)
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resolver defines the interface for resolving human readable
+// names to Swarm references and provides generic resolvers that combine
+// other resolvers.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var (
+	// ErrNotFound is returned by resolvers when the name is not registered.
+	ErrNotFound = errors.New("resolver: name not found")
+	// ErrUnavailable is returned by resolvers when the service
+	// that they look up names in can not be reached.
+	ErrUnavailable = errors.New("resolver: service unavailable")
+)
+
+// Interface resolves human readable names to Swarm references.
+type Interface interface {
+	Resolve(ctx context.Context, name string) (swarm.Address, error)
+}
+
+// chain is a resolver that queries a list of resolvers in order.
+type chain []Interface
+
+// NewChain returns a resolver which returns the reference from the first
+// resolver in the list that resolves the name. If none of the resolvers
+// resolve it, ErrNotFound is returned, unless a resolver returned a
+// different error, which is returned instead.
+func NewChain(resolvers ...Interface) Interface {
+	return chain(resolvers)
+}
+
+func (c chain) Resolve(ctx context.Context, name string) (swarm.Address, error) {
+	var lastErr error
+	for _, r := range c {
+		addr, err := r.Resolve(ctx, name)
+		if err == nil {
+			return addr, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			lastErr = err
+		}
+		if ctx.Err() != nil {
+			return swarm.ZeroAddress, ctx.Err()
+		}
+	}
+	if lastErr != nil {
+		return swarm.ZeroAddress, fmt.Errorf("resolve %s: %w", name, lastErr)
+	}
+	return swarm.ZeroAddress, ErrNotFound
+}
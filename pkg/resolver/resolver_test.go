@@ -0,0 +1,108 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resolver/static"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var (
+	addr1 = swarm.MustParseHexAddress("c10090961e7682a10890c334d759a28426647141213abda93b096b892824d2ef")
+	addr2 = swarm.MustParseHexAddress("3047d841077898c26bbe6be652a2ec590a5d9bd7cd45d290ea42511b48753c09")
+)
+
+func TestChain(t *testing.T) {
+	errTest := errors.New("test error")
+
+	r := resolver.NewChain(
+		static.New(map[string]swarm.Address{"first.eth": addr1}),
+		resolverFunc(func(_ context.Context, name string) (swarm.Address, error) {
+			if name == "failing.eth" {
+				return swarm.ZeroAddress, errTest
+			}
+			return swarm.ZeroAddress, resolver.ErrNotFound
+		}),
+		static.New(map[string]swarm.Address{"first.eth": addr2, "second.eth": addr2}),
+	)
+
+	for _, tc := range []struct {
+		name    string
+		addr    swarm.Address
+		wantErr error
+	}{
+		{name: "first.eth", addr: addr1},
+		{name: "second.eth", addr: addr2},
+		{name: "unknown.eth", wantErr: resolver.ErrNotFound},
+		{name: "failing.eth", wantErr: errTest},
+	} {
+		addr, err := r.Resolve(context.Background(), tc.name)
+		if !errors.Is(err, tc.wantErr) {
+			t.Fatalf("%s: got error %v, want %v", tc.name, err, tc.wantErr)
+		}
+		if !addr.Equal(tc.addr) {
+			t.Fatalf("%s: got address %s, want %s", tc.name, addr, tc.addr)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	var calls int
+	names := map[string]swarm.Address{"mysite.eth": addr1}
+	r := resolver.NewCache(resolverFunc(func(_ context.Context, name string) (swarm.Address, error) {
+		calls++
+		addr, ok := names[name]
+		if !ok {
+			return swarm.ZeroAddress, resolver.ErrNotFound
+		}
+		return addr, nil
+	}), time.Minute)
+
+	now := time.Now()
+	r.SetTimeFunc(func() time.Time { return now })
+
+	for i := 0; i < 3; i++ {
+		addr, err := r.Resolve(context.Background(), "mysite.eth")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !addr.Equal(addr1) {
+			t.Fatalf("got address %s, want %s", addr, addr1)
+		}
+		if _, err := r.Resolve(context.Background(), "unknown.eth"); !errors.Is(err, resolver.ErrNotFound) {
+			t.Fatalf("got error %v, want %v", err, resolver.ErrNotFound)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("got %d resolver calls, want %d", calls, 2)
+	}
+
+	// update the name and expire the cache
+	names["mysite.eth"] = addr2
+	now = now.Add(2 * time.Minute)
+
+	addr, err := r.Resolve(context.Background(), "mysite.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.Equal(addr2) {
+		t.Fatalf("got address %s, want %s", addr, addr2)
+	}
+	if calls != 3 {
+		t.Fatalf("got %d resolver calls, want %d", calls, 3)
+	}
+}
+
+type resolverFunc func(ctx context.Context, name string) (swarm.Address, error)
+
+func (f resolverFunc) Resolve(ctx context.Context, name string) (swarm.Address, error) {
+	return f(ctx, name)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package static provides a resolver with a fixed set of names,
+// which can be loaded from a JSON file.
+package static
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var _ resolver.Interface = (*Resolver)(nil)
+
+// Resolver resolves names from a fixed name to reference mapping.
+// Names are case insensitive.
+type Resolver struct {
+	names map[string]swarm.Address
+}
+
+// New constructs a new Resolver from the name to reference mapping.
+func New(names map[string]swarm.Address) *Resolver {
+	r := &Resolver{
+		names: make(map[string]swarm.Address, len(names)),
+	}
+	for name, addr := range names {
+		r.names[strings.ToLower(name)] = addr
+	}
+	return r
+}
+
+// NewFromFile constructs a new Resolver from a JSON file which contains
+// an object with names as keys and hex encoded references as values,
+// for example {"mysite.eth": "c10090961e7682a10890c334d759a28426647141213abda93b096b892824d2ef"}.
+func NewFromFile(path string) (*Resolver, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read names file: %w", err)
+	}
+	var hexNames map[string]string
+	if err := json.Unmarshal(data, &hexNames); err != nil {
+		return nil, fmt.Errorf("decode names file: %w", err)
+	}
+	names := make(map[string]swarm.Address, len(hexNames))
+	for name, ref := range hexNames {
+		addr, err := swarm.ParseHexAddress(ref)
+		if err != nil {
+			return nil, fmt.Errorf("parse reference for name %q: %w", name, err)
+		}
+		names[name] = addr
+	}
+	return New(names), nil
+}
+
+// Resolve returns the reference for the name or resolver.ErrNotFound.
+func (r *Resolver) Resolve(_ context.Context, name string) (swarm.Address, error) {
+	addr, ok := r.names[strings.ToLower(name)]
+	if !ok {
+		return swarm.ZeroAddress, resolver.ErrNotFound
+	}
+	return addr, nil
+}
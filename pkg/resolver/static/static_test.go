@@ -0,0 +1,66 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package static_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resolver/static"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestNewFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bee-resolver-static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "names.json")
+	if err := ioutil.WriteFile(path, []byte(`{"MySite.eth": "c10090961e7682a10890c334d759a28426647141213abda93b096b892824d2ef"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := static.NewFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := r.Resolve(context.Background(), "mysite.ETH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := swarm.MustParseHexAddress("c10090961e7682a10890c334d759a28426647141213abda93b096b892824d2ef")
+	if !addr.Equal(want) {
+		t.Fatalf("got address %s, want %s", addr, want)
+	}
+
+	if _, err := r.Resolve(context.Background(), "other.eth"); !errors.Is(err, resolver.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, resolver.ErrNotFound)
+	}
+}
+
+func TestNewFromFileInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bee-resolver-static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "names.json")
+	if err := ioutil.WriteFile(path, []byte(`{"mysite.eth": "not a reference"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := static.NewFromFile(path); err == nil {
+		t.Fatal("expected error")
+	}
+}
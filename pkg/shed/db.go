@@ -29,6 +29,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 var (
@@ -150,6 +151,11 @@ func (db *DB) WriteBatch(batch *leveldb.Batch) (err error) {
 	return nil
 }
 
+// Compact compacts the whole key range of the LevelDB database.
+func (db *DB) Compact() error {
+	return db.ldb.CompactRange(util.Range{})
+}
+
 // Close closes LevelDB database.
 func (db *DB) Close() (err error) {
 	close(db.quit)
@@ -40,7 +40,9 @@ import (
 type Item struct {
 	Address         []byte
 	Data            []byte
+	Location        []byte // location of the data stored outside of the index
 	AccessTimestamp int64
+	AccessCount     uint64
 	StoreTimestamp  int64
 	BinID           uint64
 	PinCounter      uint64 // maintains the no of time a chunk is pinned
@@ -57,9 +59,15 @@ func (i Item) Merge(i2 Item) Item {
 	if i.Data == nil {
 		i.Data = i2.Data
 	}
+	if i.Location == nil {
+		i.Location = i2.Location
+	}
 	if i.AccessTimestamp == 0 {
 		i.AccessTimestamp = i2.AccessTimestamp
 	}
+	if i.AccessCount == 0 {
+		i.AccessCount = i2.AccessCount
+	}
 	if i.StoreTimestamp == 0 {
 		i.StoreTimestamp = i2.StoreTimestamp
 	}
@@ -419,17 +427,27 @@ func incByteSlice(b []byte) (next []byte) {
 
 // Count returns the number of items in index.
 func (f Index) Count() (count int, err error) {
+	return f.Counter()()
+}
+
+// Counter returns a function that counts the items in index as they are
+// when Counter is called, ignoring changes made before the returned
+// function is called. The returned function must be called once to
+// release resources.
+func (f Index) Counter() (count func() (int, error)) {
 	it := f.db.NewIterator()
-	defer it.Release()
+	return func() (count int, err error) {
+		defer it.Release()
 
-	for ok := it.Seek(f.prefix); ok; ok = it.Next() {
-		key := it.Key()
-		if key[0] != f.prefix[0] {
-			break
+		for ok := it.Seek(f.prefix); ok; ok = it.Next() {
+			key := it.Key()
+			if key[0] != f.prefix[0] {
+				break
+			}
+			count++
 		}
-		count++
+		return count, it.Error()
 	}
-	return count, it.Error()
 }
 
 // CountFrom returns the number of items in index keys
@@ -786,6 +786,34 @@ func TestIndex_count(t *testing.T) {
 		}
 	})
 
+	t.Run("Counter", func(t *testing.T) {
+		count := index.Counter()
+
+		// items added after the counter are not counted
+		item := Item{
+			Address: []byte("iterate-hash-06"),
+			Data:    []byte("data6"),
+		}
+		if err := index.Put(item); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := index.Delete(item); err != nil {
+				t.Fatal(err)
+			}
+		}()
+
+		got, err := count()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := len(items)
+		if got != want {
+			t.Errorf("got %v items count, want %v", got, want)
+		}
+	})
+
 	t.Run("CountFrom", func(t *testing.T) {
 		got, err := index.CountFrom(Item{
 			Address: items[1].Address,
@@ -0,0 +1,13 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package journal
+
+// SetCompactionMinSize sets the journal size below which the journal
+// is not compacted, and returns the function that resets it.
+func SetCompactionMinSize(size int64) (reset func()) {
+	current := compactionMinSize
+	compactionMinSize = size
+	return func() { compactionMinSize = current }
+}
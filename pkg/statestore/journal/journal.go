@@ -0,0 +1,489 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package journal provides a state store that keeps all values in memory
+// and persists them by appending changes to a journal file. Every write
+// and transaction is a single checksummed record that is synced to disk
+// before the write returns, so it is either fully applied or not applied
+// at all after a crash. The journal is compacted when most of its records
+// are overwritten.
+package journal
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// FileName is the name of the journal file in the store directory.
+const FileName = "journal"
+
+const (
+	opPut    byte = 1
+	opDelete byte = 2
+
+	// headerSize is the size of the record header
+	// with the payload length and checksum.
+	headerSize = 8
+)
+
+// compactionMinSize is the journal size below
+// which the journal is never compacted.
+var compactionMinSize int64 = 1 << 20
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var _ storage.StateStorer = (*store)(nil)
+
+// store keeps values in memory and appends changes to the journal.
+type store struct {
+	path   string
+	file   *os.File
+	values map[string][]byte
+	// size is the size of the journal file and liveSize is
+	// the size of the journal file after the compaction.
+	size     int64
+	liveSize int64
+	mu       sync.Mutex
+	logger   logging.Logger
+}
+
+// NewStateStore opens the journal in the directory, creating
+// the directory and the journal if they do not exist.
+func NewStateStore(dir string, logger logging.Logger) (storage.StateStorer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, FileName)
+	// the journal is always complete, an interrupted
+	// compaction leaves only the temporary file
+	if err := os.Remove(path + ".tmp"); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	s := &store{
+		path:   path,
+		values: make(map[string][]byte),
+		logger: logger,
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	valid, err := s.replay(data)
+	if err != nil {
+		return nil, fmt.Errorf("journal %s: %w", path, err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	// the incomplete last record of an interrupted write is removed
+	if valid < int64(len(data)) {
+		if err := f.Truncate(valid); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if _, err := f.Seek(valid, 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.file = f
+	s.size = valid
+	return s, nil
+}
+
+// replay applies the records of the journal data and returns the size
+// of the data up to the incomplete record of an interrupted write at the
+// end of the data. A record that is not valid and that is followed by
+// other data is reported as an error, as it can not be a torn write.
+func (s *store) replay(data []byte) (valid int64, err error) {
+	for len(data)-int(valid) >= headerSize {
+		header := data[valid : valid+headerSize]
+		length := int64(binary.BigEndian.Uint32(header))
+		end := valid + headerSize + length
+		if end > int64(len(data)) {
+			break
+		}
+		payload := data[valid+headerSize : end]
+		if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:]) {
+			if end == int64(len(data)) {
+				break
+			}
+			return 0, fmt.Errorf("record at %d: %w", valid, errChecksumMismatch)
+		}
+		if err := decodeRecord(payload, s.apply); err != nil {
+			return 0, fmt.Errorf("record at %d: %w", valid, err)
+		}
+		valid = end
+	}
+	return valid, nil
+}
+
+// Get retrieves a value of the requested key. If no results are found,
+// storage.ErrNotFound will be returned.
+func (s *store) Get(key string, i interface{}) (err error) {
+	s.mu.Lock()
+	data, ok := s.values[key]
+	s.mu.Unlock()
+	if !ok {
+		return storage.ErrNotFound
+	}
+	return unmarshal(data, i)
+}
+
+// Put stores a value for an arbitrary key. BinaryMarshaler
+// interface method will be called on the provided value
+// with fallback to JSON serialization.
+func (s *store) Put(key string, i interface{}) (err error) {
+	data, err := marshal(i)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.write([]change{{key: key, value: data}})
+}
+
+// Delete removes entries stored under a specific key.
+func (s *store) Delete(key string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.values[key]; !ok {
+		return nil
+	}
+	return s.write([]change{{key: key}})
+}
+
+// Iterate entries that match the supplied prefix, ordered by their keys.
+// Changes to the store by iterFunc are not iterated on.
+func (s *store) Iterate(prefix string, iterFunc storage.StateIterFunc) (err error) {
+	s.mu.Lock()
+	keys := make([]string, 0)
+	values := make(map[string][]byte)
+	for k, v := range s.values {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+			values[k] = v
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		stop, err := iterFunc([]byte(k), append([]byte(nil), values[k]...))
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Update calls f with a transaction that keeps changes in memory until
+// they are written to the journal in a single record. The store is
+// locked until f returns.
+func (s *store) Update(f func(tx storage.StateTx) error) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &tx{
+		values:  s.values,
+		changes: make(map[string]int),
+	}
+	if err := f(t); err != nil {
+		return err
+	}
+	if len(t.log) == 0 {
+		return nil
+	}
+	return s.write(t.log)
+}
+
+// Close syncs and closes the journal file.
+func (s *store) Close() (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// change sets the value of the key, or deletes the key if the value is nil.
+type change struct {
+	key   string
+	value []byte
+}
+
+// write appends the changes to the journal as a single record, syncs
+// the journal and applies the changes. It must be called with mu held.
+func (s *store) write(changes []change) error {
+	record := encodeRecord(changes)
+	if err := s.append(record); err != nil {
+		// the record may be partially written, and it would
+		// be replayed with records that follow it
+		if terr := s.file.Truncate(s.size); terr != nil {
+			return fmt.Errorf("%v: truncate journal: %w", err, terr)
+		}
+		if _, serr := s.file.Seek(s.size, 0); serr != nil {
+			return fmt.Errorf("%v: seek journal: %w", err, serr)
+		}
+		return err
+	}
+	s.size += int64(len(record))
+	for _, c := range changes {
+		s.apply(c)
+	}
+	if s.size > compactionMinSize && s.size > 2*s.liveSize {
+		// the changes are already persisted, and
+		// the compaction is retried on the next write
+		if err := s.compact(); err != nil {
+			s.logger.Debugf("journal: compact %s: %v", s.path, err)
+			s.logger.Error("journal: failed to compact state store journal")
+		}
+	}
+	return nil
+}
+
+// append writes the record to the end of the journal and syncs it.
+func (s *store) append(record []byte) error {
+	if _, err := s.file.Write(record); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// apply sets the change in memory and updates the live size.
+func (s *store) apply(c change) {
+	if v, ok := s.values[c.key]; ok {
+		s.liveSize -= changeSize(c.key, v)
+	}
+	if c.value == nil {
+		delete(s.values, c.key)
+		return
+	}
+	s.values[c.key] = c.value
+	s.liveSize += changeSize(c.key, c.value)
+}
+
+// compact replaces the journal with a journal that has a single record
+// of all values. It must be called with mu held.
+func (s *store) compact() (err error) {
+	changes := make([]change, 0, len(s.values))
+	for k, v := range s.values {
+		changes = append(changes, change{key: k, value: v})
+	}
+	record := encodeRecord(changes)
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(record); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		f.Close()
+		return err
+	}
+	old := s.file
+	s.file = f
+	s.size = int64(len(record))
+	// the rename is durable only when the directory is synced,
+	// otherwise the old journal may be restored after a crash
+	if err := syncDir(filepath.Dir(s.path)); err != nil {
+		old.Close()
+		return err
+	}
+	return old.Close()
+}
+
+// syncDir commits the entries of the directory to stable storage.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
+
+// encodeRecord returns the record with the header
+// and the encoded changes as the payload.
+func encodeRecord(changes []change) []byte {
+	var b bytes.Buffer
+	b.Write(make([]byte, headerSize))
+	var buf [binary.MaxVarintLen64]byte
+	for _, c := range changes {
+		if c.value == nil {
+			b.WriteByte(opDelete)
+		} else {
+			b.WriteByte(opPut)
+		}
+		b.Write(buf[:binary.PutUvarint(buf[:], uint64(len(c.key)))])
+		b.WriteString(c.key)
+		if c.value != nil {
+			b.Write(buf[:binary.PutUvarint(buf[:], uint64(len(c.value)))])
+			b.Write(c.value)
+		}
+	}
+	record := b.Bytes()
+	payload := record[headerSize:]
+	binary.BigEndian.PutUint32(record, uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:], crc32.Checksum(payload, crcTable))
+	return record
+}
+
+var (
+	errInvalidRecord    = errors.New("invalid record")
+	errChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// decodeRecord calls f with every change encoded in the payload.
+func decodeRecord(payload []byte, f func(c change)) error {
+	r := bytes.NewReader(payload)
+	readBytes := func() ([]byte, error) {
+		l, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errInvalidRecord
+		}
+		if l > uint64(r.Len()) {
+			return nil, errInvalidRecord
+		}
+		b := make([]byte, l)
+		_, _ = r.Read(b)
+		return b, nil
+	}
+	for r.Len() > 0 {
+		op, _ := r.ReadByte()
+		key, err := readBytes()
+		if err != nil {
+			return err
+		}
+		c := change{key: string(key)}
+		switch op {
+		case opPut:
+			if c.value, err = readBytes(); err != nil {
+				return err
+			}
+		case opDelete:
+		default:
+			return errInvalidRecord
+		}
+		f(c)
+	}
+	return nil
+}
+
+// changeSize returns the size of the encoded put change.
+func changeSize(key string, value []byte) int64 {
+	return int64(1 + uvarintSize(len(key)) + len(key) + uvarintSize(len(value)) + len(value))
+}
+
+func uvarintSize(x int) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], uint64(x))
+}
+
+// tx is a state store transaction.
+type tx struct {
+	values map[string][]byte
+	// log is the list of changes in the transaction and changes
+	// maps their keys to the index of the last change in the log
+	log     []change
+	changes map[string]int
+}
+
+func (t *tx) Get(key string, i interface{}) (err error) {
+	var data []byte
+	if j, ok := t.changes[key]; ok {
+		data = t.log[j].value
+	} else {
+		data = t.values[key]
+	}
+	if data == nil {
+		return storage.ErrNotFound
+	}
+	return unmarshal(data, i)
+}
+
+func (t *tx) Put(key string, i interface{}) (err error) {
+	data, err := marshal(i)
+	if err != nil {
+		return err
+	}
+	t.set(change{key: key, value: data})
+	return nil
+}
+
+func (t *tx) Delete(key string) (err error) {
+	t.set(change{key: key})
+	return nil
+}
+
+func (t *tx) set(c change) {
+	if j, ok := t.changes[c.key]; ok {
+		t.log[j] = c
+		return
+	}
+	t.changes[c.key] = len(t.log)
+	t.log = append(t.log, c)
+}
+
+// marshal encodes the value with the BinaryMarshaler interface
+// method with fallback to JSON serialization. The returned data
+// is never nil, as nil values mark deleted keys.
+func marshal(i interface{}) (data []byte, err error) {
+	if marshaler, ok := i.(encoding.BinaryMarshaler); ok {
+		data, err = marshaler.MarshalBinary()
+	} else {
+		data, err = json.Marshal(i)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return data, nil
+}
+
+func unmarshal(data []byte, i interface{}) error {
+	if unmarshaler, ok := i.(encoding.BinaryUnmarshaler); ok {
+		return unmarshaler.UnmarshalBinary(data)
+	}
+	return json.Unmarshal(data, i)
+}
@@ -0,0 +1,275 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package journal_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/statestore/journal"
+	"github.com/ethersphere/bee/pkg/statestore/test"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+func TestJournalStateStore(t *testing.T) {
+	test.Run(t, func(t *testing.T) storage.StateStorer {
+		store, err := journal.NewStateStore(tempDir(t), logging.New(ioutil.Discard, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := store.Close(); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		return store
+	})
+
+	test.RunPersist(t, func(t *testing.T, dir string) storage.StateStorer {
+		store, err := journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return store
+	})
+}
+
+// TestIncompleteRecord validates that the incomplete record of
+// an interrupted write is discarded with all of its changes.
+func TestIncompleteRecord(t *testing.T) {
+	dir := tempDir(t)
+
+	store, err := journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("key1", "value1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, journal.FileName)
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := fi.Size()
+
+	store, err = journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Update(func(tx storage.StateTx) error {
+		if err := tx.Delete("key1"); err != nil {
+			return err
+		}
+		return tx.Put("key2", "value2")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// cut the last record
+	if err := os.Truncate(path, size+10); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v string
+	if err := store.Get("key1", &v); err != nil {
+		t.Fatal(err)
+	}
+	if v != "value1" {
+		t.Errorf("got value %q, want %q", v, "value1")
+	}
+	if err := store.Get("key2", &v); err != storage.ErrNotFound {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+
+	// new records are appended after the last complete record
+	if err := store.Put("key3", "value3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.Get("key3", &v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCorruptedRecord validates that a corrupted last record is discarded
+// as an interrupted write, and that a corrupted record which is followed
+// by other records fails to open the journal without changing it.
+func TestCorruptedRecord(t *testing.T) {
+	dir := tempDir(t)
+
+	store, err := journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"key1", "key2"} {
+		if err := store.Put(key, "value"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, journal.FileName)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordSize := len(data) / 2
+
+	// corrupt the payload of the first record
+	corrupted := append([]byte(nil), data...)
+	corrupted[recordSize-1] ^= 0xff
+	if err := ioutil.WriteFile(path, corrupted, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := journal.NewStateStore(dir, logging.New(ioutil.Discard, 0)); err == nil {
+		t.Fatal("got no error for a corrupted record followed by other records")
+	}
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(corrupted) {
+		t.Fatalf("got journal size %v, want %v", len(got), len(corrupted))
+	}
+
+	// corrupt the payload of the last record
+	corrupted = append([]byte(nil), data...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	if err := ioutil.WriteFile(path, corrupted, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err = journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	var v string
+	if err := store.Get("key1", &v); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Get("key2", &v); err != storage.ErrNotFound {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+// TestCompaction validates that the journal is compacted
+// when values are overwritten.
+func TestCompaction(t *testing.T) {
+	defer journal.SetCompactionMinSize(1024)()
+
+	dir := tempDir(t)
+
+	store, err := journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		if err := store.Put("key", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(filepath.Join(dir, journal.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() > 2048 {
+		t.Errorf("got journal size %v, want at most 2048", fi.Size())
+	}
+
+	store, err = journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	var v int
+	if err := store.Get("key", &v); err != nil {
+		t.Fatal(err)
+	}
+	if v != 999 {
+		t.Errorf("got value %v, want 999", v)
+	}
+}
+
+// TestCompactionFailure validates that writes do not fail
+// when the journal can not be compacted.
+func TestCompactionFailure(t *testing.T) {
+	defer journal.SetCompactionMinSize(1024)()
+
+	dir := tempDir(t)
+
+	store, err := journal.NewStateStore(dir, logging.New(ioutil.Discard, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// the temporary file of the compaction can not be created
+	tmp := filepath.Join(dir, journal.FileName+".tmp")
+	if err := os.Mkdir(tmp, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := store.Put("key", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Remove(tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("key", 100); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filepath.Join(dir, journal.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() > 1024 {
+		t.Errorf("got journal size %v, want at most 1024", fi.Size())
+	}
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "journal_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return dir
+}
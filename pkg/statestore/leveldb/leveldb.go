@@ -42,27 +42,17 @@ func (s *store) Get(key string, i interface{}) error {
 		}
 		return err
 	}
-
-	if unmarshaler, ok := i.(encoding.BinaryUnmarshaler); ok {
-		return unmarshaler.UnmarshalBinary(data)
-	}
-
-	return json.Unmarshal(data, i)
+	return unmarshal(data, i)
 }
 
 // Put stores a value for an arbitrary key. BinaryMarshaler
 // interface method will be called on the provided value
 // with fallback to JSON serialization.
 func (s *store) Put(key string, i interface{}) (err error) {
-	var bytes []byte
-	if marshaler, ok := i.(encoding.BinaryMarshaler); ok {
-		if bytes, err = marshaler.MarshalBinary(); err != nil {
-			return err
-		}
-	} else if bytes, err = json.Marshal(i); err != nil {
+	bytes, err := marshal(i)
+	if err != nil {
 		return err
 	}
-
 	return s.db.Put([]byte(key), bytes, nil)
 }
 
@@ -87,7 +77,66 @@ func (s *store) Iterate(prefix string, iterFunc storage.StateIterFunc) (err erro
 	return iter.Error()
 }
 
+// Update calls f with a LevelDB transaction. Other transactions and
+// writes are blocked until the transaction is committed or discarded.
+func (s *store) Update(f func(tx storage.StateTx) error) (err error) {
+	t, err := s.db.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	if err := f(&tx{t: t}); err != nil {
+		t.Discard()
+		return err
+	}
+	return t.Commit()
+}
+
 // Close releases the resources used by the store.
 func (s *store) Close() error {
 	return s.db.Close()
 }
+
+// tx is a state store transaction.
+type tx struct {
+	t *leveldb.Transaction
+}
+
+func (t *tx) Get(key string, i interface{}) (err error) {
+	data, err := t.t.Get([]byte(key), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return storage.ErrNotFound
+		}
+		return err
+	}
+	return unmarshal(data, i)
+}
+
+func (t *tx) Put(key string, i interface{}) (err error) {
+	bytes, err := marshal(i)
+	if err != nil {
+		return err
+	}
+	return t.t.Put([]byte(key), bytes, nil)
+}
+
+func (t *tx) Delete(key string) (err error) {
+	return t.t.Delete([]byte(key), nil)
+}
+
+// marshal encodes the value with the BinaryMarshaler
+// interface method with fallback to JSON serialization.
+func marshal(i interface{}) ([]byte, error) {
+	if marshaler, ok := i.(encoding.BinaryMarshaler); ok {
+		return marshaler.MarshalBinary()
+	}
+	return json.Marshal(i)
+}
+
+// unmarshal decodes the data encoded by marshal.
+func unmarshal(data []byte, i interface{}) error {
+	if unmarshaler, ok := i.(encoding.BinaryUnmarshaler); ok {
+		return unmarshaler.UnmarshalBinary(data)
+	}
+	return json.Unmarshal(data, i)
+}
@@ -34,27 +34,17 @@ func (s *store) Get(key string, i interface{}) (err error) {
 	if !ok {
 		return storage.ErrNotFound
 	}
-
-	if unmarshaler, ok := i.(encoding.BinaryUnmarshaler); ok {
-		return unmarshaler.UnmarshalBinary(data)
-	}
-
-	return json.Unmarshal(data, i)
+	return unmarshal(data, i)
 }
 
 func (s *store) Put(key string, i interface{}) (err error) {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	var bytes []byte
-	if marshaler, ok := i.(encoding.BinaryMarshaler); ok {
-		if bytes, err = marshaler.MarshalBinary(); err != nil {
-			return err
-		}
-	} else if bytes, err = json.Marshal(i); err != nil {
+	bytes, err := marshal(i)
+	if err != nil {
 		return err
 	}
-
 	s.store[key] = bytes
 	return nil
 }
@@ -90,6 +80,79 @@ func (s *store) Iterate(prefix string, iterFunc storage.StateIterFunc) (err erro
 	return nil
 }
 
+// Update calls f with a transaction that keeps changes in memory until
+// they are applied to the store. The store is locked until f returns.
+func (s *store) Update(f func(tx storage.StateTx) error) (err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	t := &tx{
+		store:   s.store,
+		changes: make(map[string][]byte),
+	}
+	if err := f(t); err != nil {
+		return err
+	}
+	for k, v := range t.changes {
+		if v == nil {
+			delete(s.store, k)
+			continue
+		}
+		s.store[k] = v
+	}
+	return nil
+}
+
 func (s *store) Close() (err error) {
 	return nil
 }
+
+// tx is a state store transaction.
+type tx struct {
+	store map[string][]byte
+	// changes are values that are set in the transaction,
+	// with nil values for deleted keys
+	changes map[string][]byte
+}
+
+func (t *tx) Get(key string, i interface{}) (err error) {
+	data, ok := t.changes[key]
+	if !ok {
+		data, ok = t.store[key]
+	}
+	if !ok || data == nil {
+		return storage.ErrNotFound
+	}
+	return unmarshal(data, i)
+}
+
+func (t *tx) Put(key string, i interface{}) (err error) {
+	bytes, err := marshal(i)
+	if err != nil {
+		return err
+	}
+	if bytes == nil {
+		bytes = []byte{}
+	}
+	t.changes[key] = bytes
+	return nil
+}
+
+func (t *tx) Delete(key string) (err error) {
+	t.changes[key] = nil
+	return nil
+}
+
+func marshal(i interface{}) ([]byte, error) {
+	if marshaler, ok := i.(encoding.BinaryMarshaler); ok {
+		return marshaler.MarshalBinary()
+	}
+	return json.Marshal(i)
+}
+
+func unmarshal(data []byte, i interface{}) error {
+	if unmarshaler, ok := i.(encoding.BinaryUnmarshaler); ok {
+		return unmarshaler.UnmarshalBinary(data)
+	}
+	return json.Unmarshal(data, i)
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statestore
+
+import (
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// Namespace is the key prefix that separates
+// state of a component from other components.
+type Namespace string
+
+// Namespaces of the components that keep state in the state store.
+const (
+	AddressbookNamespace Namespace = "addressbook_entry_"
+	AuthNamespace        Namespace = "auth_token_"
+	PullerNamespace      Namespace = "puller_interval_"
+)
+
+// Namespaces maps names of the namespaces to the namespaces.
+var Namespaces = map[string]Namespace{
+	"addressbook": AddressbookNamespace,
+	"auth":        AuthNamespace,
+	"puller":      PullerNamespace,
+}
+
+var _ storage.StateStorer = (*namespacedStore)(nil)
+
+// namespacedStore prefixes keys with the namespace.
+type namespacedStore struct {
+	store     storage.StateStorer
+	namespace Namespace
+}
+
+// WithNamespace returns the state store with keys of the namespace. Keys
+// passed to it and iterated by it are without the namespace prefix. Closing
+// the returned store does not close the underlying store.
+func WithNamespace(s storage.StateStorer, namespace Namespace) storage.StateStorer {
+	return &namespacedStore{
+		store:     s,
+		namespace: namespace,
+	}
+}
+
+func (s *namespacedStore) key(key string) string {
+	return string(s.namespace) + key
+}
+
+func (s *namespacedStore) Get(key string, i interface{}) (err error) {
+	return s.store.Get(s.key(key), i)
+}
+
+func (s *namespacedStore) Put(key string, i interface{}) (err error) {
+	return s.store.Put(s.key(key), i)
+}
+
+func (s *namespacedStore) Delete(key string) (err error) {
+	return s.store.Delete(s.key(key))
+}
+
+func (s *namespacedStore) Iterate(prefix string, iterFunc storage.StateIterFunc) (err error) {
+	return s.store.Iterate(s.key(prefix), func(key, value []byte) (stop bool, err error) {
+		return iterFunc([]byte(strings.TrimPrefix(string(key), string(s.namespace))), value)
+	})
+}
+
+func (s *namespacedStore) Update(f func(tx storage.StateTx) error) (err error) {
+	return s.store.Update(func(tx storage.StateTx) error {
+		return f(&namespacedTx{tx: tx, namespace: s.namespace})
+	})
+}
+
+func (s *namespacedStore) Close() (err error) {
+	return nil
+}
+
+// namespacedTx prefixes keys of the transaction with the namespace.
+type namespacedTx struct {
+	tx        storage.StateTx
+	namespace Namespace
+}
+
+func (t *namespacedTx) Get(key string, i interface{}) (err error) {
+	return t.tx.Get(string(t.namespace)+key, i)
+}
+
+func (t *namespacedTx) Put(key string, i interface{}) (err error) {
+	return t.tx.Put(string(t.namespace)+key, i)
+}
+
+func (t *namespacedTx) Delete(key string) (err error) {
+	return t.tx.Delete(string(t.namespace) + key)
+}
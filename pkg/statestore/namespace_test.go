@@ -0,0 +1,79 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statestore_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/statestore"
+	"github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/statestore/test"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+func TestNamespacedStateStore(t *testing.T) {
+	test.Run(t, func(t *testing.T) storage.StateStorer {
+		return statestore.WithNamespace(mock.NewStateStore(), statestore.PullerNamespace)
+	})
+}
+
+// TestNamespaceIsolation validates that keys of a namespace
+// are not visible in other namespaces.
+func TestNamespaceIsolation(t *testing.T) {
+	s := mock.NewStateStore()
+	addressbook := statestore.WithNamespace(s, statestore.AddressbookNamespace)
+	puller := statestore.WithNamespace(s, statestore.PullerNamespace)
+
+	if err := addressbook.Put("key", "addressbook value"); err != nil {
+		t.Fatal(err)
+	}
+	if err := puller.Update(func(tx storage.StateTx) error {
+		return tx.Put("key", "puller value")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		store storage.StateStorer
+		key   string
+		want  string
+	}{
+		{store: addressbook, key: "key", want: "addressbook value"},
+		{store: puller, key: "key", want: "puller value"},
+		{store: s, key: string(statestore.AddressbookNamespace) + "key", want: "addressbook value"},
+		{store: s, key: string(statestore.PullerNamespace) + "key", want: "puller value"},
+	} {
+		var v string
+		if err := tc.store.Get(tc.key, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v != tc.want {
+			t.Errorf("key %q: got value %q, want %q", tc.key, v, tc.want)
+		}
+	}
+
+	var keys []string
+	if err := puller.Iterate("", func(key, _ []byte) (stop bool, err error) {
+		keys = append(keys, string(key))
+		return false, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "key" {
+		t.Errorf("got iterated keys %v, want [key]", keys)
+	}
+
+	if err := puller.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	var v string
+	if err := addressbook.Get("key", &v); err != nil {
+		t.Fatal(err)
+	}
+	if err := puller.Get("key", &v); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package statestore opens state stores of the supported backends
+// and separates state of components in namespaces.
+package statestore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/statestore/journal"
+	"github.com/ethersphere/bee/pkg/statestore/leveldb"
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
+// Backends of persistent state stores.
+const (
+	BackendLevelDB = "leveldb"
+	BackendJournal = "journal"
+)
+
+// DefaultBackend is the backend of new state stores
+// if no backend is given.
+const DefaultBackend = BackendLevelDB
+
+// Open opens the state store of the backend in the directory. If the
+// backend is empty, the backend of the existing state store is used, or
+// DefaultBackend if there is none. A state store of a different backend
+// must be dumped and restored to change the backend.
+func Open(backend, dir string, logger logging.Logger) (storage.StateStorer, error) {
+	existing, err := DetectBackend(dir)
+	if err != nil {
+		return nil, err
+	}
+	if backend == "" {
+		backend = existing
+	}
+	if backend == "" {
+		backend = DefaultBackend
+	}
+	if existing != "" && existing != backend {
+		return nil, fmt.Errorf("state store in %s has the %s backend, not %s", dir, existing, backend)
+	}
+
+	switch backend {
+	case BackendLevelDB:
+		return leveldb.NewStateStore(dir)
+	case BackendJournal:
+		return journal.NewStateStore(dir, logger)
+	default:
+		return nil, fmt.Errorf("unknown state store backend %q", backend)
+	}
+}
+
+// DetectBackend returns the backend of the state store in the
+// directory, or an empty string if there is no state store.
+func DetectBackend(dir string) (backend string, err error) {
+	for _, b := range []struct {
+		name string
+		file string
+	}{
+		{name: BackendLevelDB, file: "CURRENT"},
+		{name: BackendJournal, file: journal.FileName},
+	} {
+		_, err := os.Stat(filepath.Join(dir, b.file))
+		if err == nil {
+			return b.name, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statestore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/statestore"
+)
+
+func TestOpen(t *testing.T) {
+	for _, tc := range []struct {
+		backend     string
+		wantBackend string
+	}{
+		{backend: "", wantBackend: statestore.DefaultBackend},
+		{backend: statestore.BackendLevelDB, wantBackend: statestore.BackendLevelDB},
+		{backend: statestore.BackendJournal, wantBackend: statestore.BackendJournal},
+	} {
+		t.Run(tc.wantBackend, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "statestore_test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			s, err := statestore.Open(tc.backend, dir, logging.New(ioutil.Discard, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Put("key", "value"); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			backend, err := statestore.DetectBackend(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if backend != tc.wantBackend {
+				t.Errorf("got backend %q, want %q", backend, tc.wantBackend)
+			}
+
+			// the existing backend is used if none is given
+			s, err = statestore.Open("", dir, logging.New(ioutil.Discard, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var v string
+			if err := s.Get("key", &v); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, other := range []string{statestore.BackendLevelDB, statestore.BackendJournal} {
+				if other == backend {
+					continue
+				}
+				if _, err := statestore.Open(other, dir, logging.New(ioutil.Discard, 0)); err == nil {
+					t.Errorf("opened %s state store with the %s backend", backend, other)
+				}
+			}
+		})
+	}
+
+	if _, err := statestore.Open("unknown", ".", logging.New(ioutil.Discard, 0)); err == nil {
+		t.Error("opened a state store with an unknown backend")
+	}
+}
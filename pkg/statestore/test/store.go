@@ -5,9 +5,11 @@
 package test
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -67,7 +69,6 @@ func RunPersist(t *testing.T, f func(t *testing.T, dir string) storage.StateStor
 
 	// bootstrap with the same old dir
 	persistedStore := f(t, dir)
-	defer persistedStore.Close()
 
 	// test that the iterator works
 	testStoreIterator(t, persistedStore, "some_prefix", 1000)
@@ -77,14 +78,189 @@ func RunPersist(t *testing.T, f func(t *testing.T, dir string) storage.StateStor
 
 	// check again
 	testStoreIterator(t, persistedStore, "some_other_prefix", 1000)
+
+	// change values in a transaction
+	if err := persistedStore.Update(func(tx storage.StateTx) error {
+		if err := tx.Delete("some_prefix0"); err != nil {
+			return err
+		}
+		return tx.Put(key1, value1)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := persistedStore.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// check that the transaction is persisted
+	updatedStore := f(t, dir)
+	defer updatedStore.Close()
+
+	testStoreIterator(t, updatedStore, "some_prefix", 999)
+	v := &Serializing{}
+	if err := updatedStore.Get(key1, v); err != nil {
+		t.Fatal(err)
+	}
+	if v.value != value1.value {
+		t.Fatalf("expected persisted to be %s but got %s", value1.value, v.value)
+	}
 }
 
+// Run is the conformance test suite of state stores,
+// which every state store implementation has to pass.
 func Run(t *testing.T, f func(t *testing.T) storage.StateStorer) {
 	t.Helper()
 
 	t.Run("test_put_get", func(t *testing.T) { testPutGet(t, f) })
+	t.Run("test_get_not_found", func(t *testing.T) { testGetNotFound(t, f) })
 	t.Run("test_delete", func(t *testing.T) { testDelete(t, f) })
 	t.Run("test_iterator", func(t *testing.T) { testIterator(t, f) })
+	t.Run("test_iterator_stop", func(t *testing.T) { testIteratorStop(t, f) })
+	t.Run("test_update", func(t *testing.T) { testUpdate(t, f) })
+	t.Run("test_update_discard", func(t *testing.T) { testUpdateDiscard(t, f) })
+}
+
+func testGetNotFound(t *testing.T, f func(t *testing.T) storage.StateStorer) {
+	t.Helper()
+
+	store := f(t)
+
+	var v string
+	if err := store.Get(key1, &v); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+
+	// deleting a missing key is not an error
+	if err := store.Delete(key1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testIteratorStop(t *testing.T, f func(t *testing.T) storage.StateStorer) {
+	t.Helper()
+
+	store := f(t)
+
+	insert(t, store, "some_prefix", 10)
+
+	var count int
+	err := store.Iterate("some_prefix", func(key, value []byte) (stop bool, err error) {
+		count++
+		return count == 3, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Fatalf("got %d iterated entries, want 3", count)
+	}
+
+	errTest := errors.New("test error")
+	err = store.Iterate("some_prefix", func(key, value []byte) (stop bool, err error) {
+		return false, errTest
+	})
+	if !errors.Is(err, errTest) {
+		t.Fatalf("got error %v, want %v", err, errTest)
+	}
+}
+
+func testUpdate(t *testing.T, f func(t *testing.T) storage.StateStorer) {
+	t.Helper()
+
+	store := f(t)
+
+	insert(t, store, "some_prefix", 10)
+
+	err := store.Update(func(tx storage.StateTx) error {
+		// values that are stored before the transaction
+		var v int
+		if err := tx.Get("some_prefix1", &v); err != nil {
+			return err
+		}
+		if v != 1 {
+			return fmt.Errorf("got value %v, want 1", v)
+		}
+
+		// values that are changed in the transaction
+		if err := tx.Put("some_prefix1", 100); err != nil {
+			return err
+		}
+		if err := tx.Get("some_prefix1", &v); err != nil {
+			return err
+		}
+		if v != 100 {
+			return fmt.Errorf("got value %v, want 100", v)
+		}
+		if err := tx.Delete("some_prefix2"); err != nil {
+			return err
+		}
+		if err := tx.Get("some_prefix2", &v); !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("got error %v, want %v", err, storage.ErrNotFound)
+		}
+		if err := tx.Put(key1, value1); err != nil {
+			return err
+		}
+		return tx.Put(key2, value2)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v int
+	if err := store.Get("some_prefix1", &v); err != nil {
+		t.Fatal(err)
+	}
+	if v != 100 {
+		t.Fatalf("got value %v, want 100", v)
+	}
+	if err := store.Get("some_prefix2", &v); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	testPersistedValues(t, store, key1, key2, value1, value2)
+	testStoreIterator(t, store, "some_prefix", 9)
+}
+
+func testUpdateDiscard(t *testing.T, f func(t *testing.T) storage.StateStorer) {
+	t.Helper()
+
+	store := f(t)
+
+	insert(t, store, "some_prefix", 10)
+
+	errTest := errors.New("test error")
+	err := store.Update(func(tx storage.StateTx) error {
+		if err := tx.Put("some_prefix1", 100); err != nil {
+			return err
+		}
+		if err := tx.Delete("some_prefix2"); err != nil {
+			return err
+		}
+		if err := tx.Put(key1, value1); err != nil {
+			return err
+		}
+		return errTest
+	})
+	if !errors.Is(err, errTest) {
+		t.Fatalf("got error %v, want %v", err, errTest)
+	}
+
+	// no change is applied
+	var v int
+	if err := store.Get("some_prefix1", &v); err != nil {
+		t.Fatal(err)
+	}
+	if v != 1 {
+		t.Fatalf("got value %v, want 1", v)
+	}
+	if err := store.Get(key1, &Serializing{}); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, storage.ErrNotFound)
+	}
+	testStoreIterator(t, store, "some_prefix", 10)
+
+	// the store is usable after the transaction
+	if err := store.Put(key1, value1); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func testDelete(t *testing.T, f func(t *testing.T) storage.StateStorer) {
@@ -160,7 +336,7 @@ func insert(t *testing.T, store storage.StateStorer, prefix string, count int) {
 	t.Helper()
 
 	for i := 0; i < count; i++ {
-		k := prefix + string(i)
+		k := prefix + strconv.Itoa(i)
 
 		err := store.Put(k, i)
 		if err != nil {
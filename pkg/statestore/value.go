@@ -0,0 +1,20 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statestore
+
+// RawValue is a state store value that is stored and
+// retrieved as is, like values that are moved between keys.
+type RawValue []byte
+
+// MarshalBinary returns the value.
+func (v RawValue) MarshalBinary() (data []byte, err error) {
+	return v, nil
+}
+
+// UnmarshalBinary sets the value to a copy of the data.
+func (v *RawValue) UnmarshalBinary(data []byte) (err error) {
+	*v = append((*v)[:0], data...)
+	return nil
+}
@@ -25,6 +25,7 @@ type MockStorer struct {
 	pinSetMu        sync.Mutex
 	subpull         []storage.Descriptor
 	partialInterval bool
+	putErr          error
 	validator       swarm.ChunkValidator
 	tags            *tags.Tags
 	morePull        chan struct{}
@@ -47,6 +48,13 @@ func WithPartialInterval(v bool) Option {
 	})
 }
 
+// WithPutError makes Put return the error.
+func WithPutError(err error) Option {
+	return optionFunc(func(m *MockStorer) {
+		m.putErr = err
+	})
+}
+
 func NewStorer(opts ...Option) *MockStorer {
 	s := &MockStorer{
 		store:     make(map[string][]byte),
@@ -89,6 +97,10 @@ func (m *MockStorer) Put(ctx context.Context, mode storage.ModePut, chs ...swarm
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
+	if m.putErr != nil {
+		return nil, m.putErr
+	}
+
 	for _, ch := range chs {
 		if m.validator != nil {
 			if !m.validator.Validate(ch) {
@@ -14,8 +14,9 @@ import (
 )
 
 var (
-	ErrNotFound     = errors.New("storage: not found")
-	ErrInvalidChunk = errors.New("storage: invalid chunk")
+	ErrNotFound          = errors.New("storage: not found")
+	ErrInvalidChunk      = errors.New("storage: invalid chunk")
+	ErrInsufficientSpace = errors.New("storage: insufficient disk space")
 )
 
 // ModeGet enumerates different Getter modes.
@@ -174,8 +175,20 @@ type StateStorer interface {
 	Put(key string, i interface{}) (err error)
 	Delete(key string) (err error)
 	Iterate(prefix string, iterFunc StateIterFunc) (err error)
+	// Update calls f with a transaction and atomically applies all of its
+	// changes if f returns nil, or discards them if f returns an error. The
+	// store must not be used by f, only the transaction.
+	Update(f func(tx StateTx) error) (err error)
 	io.Closer
 }
 
+// StateTx gets, sets and deletes values in a StateStorer transaction.
+// Values that are set or deleted in the transaction are returned by Get.
+type StateTx interface {
+	Get(key string, i interface{}) (err error)
+	Put(key string, i interface{}) (err error)
+	Delete(key string) (err error)
+}
+
 // StateIterFunc is used when iterating through StateStorer key/value pairs
 type StateIterFunc func(key, value []byte) (stop bool, err error)
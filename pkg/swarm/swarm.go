@@ -154,3 +154,17 @@ func (c *chunk) Equal(cp Chunk) bool {
 type ChunkValidator interface {
 	Validate(ch Chunk) (valid bool)
 }
+
+// ValidateChunk reports whether the chunk is valid for any of the
+// validators. The chunk is valid if there are no validators.
+func ValidateChunk(ch Chunk, validators ...ChunkValidator) bool {
+	if len(validators) == 0 {
+		return true
+	}
+	for _, v := range validators {
+		if v.Validate(ch) {
+			return true
+		}
+	}
+	return false
+}
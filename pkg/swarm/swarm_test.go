@@ -81,3 +81,31 @@ func TestAddress_jsonMarshalling(t *testing.T) {
 		t.Error("unmarshalled address is not equal to the original")
 	}
 }
+
+func TestValidateChunk(t *testing.T) {
+	ch := swarm.NewChunk(swarm.MustParseHexAddress("aabbcc"), []byte("data"))
+	valid := validatorFunc(func(swarm.Chunk) bool { return true })
+	invalid := validatorFunc(func(swarm.Chunk) bool { return false })
+
+	for _, tc := range []struct {
+		name       string
+		validators []swarm.ChunkValidator
+		want       bool
+	}{
+		{name: "no validators", want: true},
+		{name: "valid", validators: []swarm.ChunkValidator{invalid, valid}, want: true},
+		{name: "invalid", validators: []swarm.ChunkValidator{invalid, invalid}, want: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := swarm.ValidateChunk(ch, tc.validators...); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+type validatorFunc func(ch swarm.Chunk) bool
+
+func (f validatorFunc) Validate(ch swarm.Chunk) bool {
+	return f(ch)
+}
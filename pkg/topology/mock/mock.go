@@ -18,6 +18,7 @@ type mock struct {
 	closestPeerErr  error
 	addPeerErr      error
 	marshalJSONFunc func() ([]byte, error)
+	depth           uint8
 	mtx             sync.Mutex
 }
 
@@ -45,6 +46,12 @@ func WithMarshalJSONFunc(f func() ([]byte, error)) Option {
 	})
 }
 
+func WithNeighborhoodDepth(depth uint8) Option {
+	return optionFunc(func(d *mock) {
+		d.depth = depth
+	})
+}
+
 func NewTopologyDriver(opts ...Option) topology.Driver {
 	d := new(mock)
 	for _, o := range opts {
@@ -83,8 +90,8 @@ func (d *mock) SubscribePeersChange() (c <-chan struct{}, unsubscribe func()) {
 	return c, unsubscribe
 }
 
-func (_ *mock) NeighborhoodDepth() uint8 {
-	return 0
+func (d *mock) NeighborhoodDepth() uint8 {
+	return d.depth
 }
 
 // EachPeer iterates from closest bin to farthest
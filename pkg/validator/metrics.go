@@ -0,0 +1,49 @@
+// Copyright 2020 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	// all metrics fields must be exported
+	// to be able to return them by Metrics()
+	// using reflection
+	ValidatedCounter prometheus.Counter
+	InvalidCounter   prometheus.Counter
+	ValidationTime   prometheus.Histogram
+}
+
+func newMetrics() metrics {
+	subsystem := "validator"
+
+	return metrics{
+		ValidatedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "validated_chunk_count",
+			Help:      "Number of validated chunks.",
+		}),
+		InvalidCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "invalid_chunk_count",
+			Help:      "Number of chunks that failed validation.",
+		}),
+		ValidationTime: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "validation_time_histogram",
+			Help:      "Histogram of time spent validating a chunk.",
+			Buckets:   []float64{0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01},
+		}),
+	}
+}
+
+func (v *ContentAddressValidator) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(v.metrics)
+}
@@ -7,42 +7,53 @@ package validator
 
 import (
 	"encoding/binary"
-	"hash"
+	"time"
 
+	"github.com/ethersphere/bee/pkg/bmtpool"
 	"github.com/ethersphere/bee/pkg/swarm"
-	bmtlegacy "github.com/ethersphere/bmt/legacy"
-	"golang.org/x/crypto/sha3"
 )
 
 var _ swarm.ChunkValidator = (*ContentAddressValidator)(nil)
 
-func hashFunc() hash.Hash {
-	return sha3.NewLegacyKeccak256()
-}
-
 // ContentAddressValidator validates that the address of a given chunk
 // is the content address of its contents
+//
+// It uses hashers from the shared bmt pool, so a single instance
+// can be used concurrently by all components that validate chunks.
 type ContentAddressValidator struct {
+	metrics metrics
 }
 
 // New constructs a new ContentAddressValidator
-func NewContentAddressValidator() swarm.ChunkValidator {
-
-	return &ContentAddressValidator{}
+func NewContentAddressValidator() *ContentAddressValidator {
+	return &ContentAddressValidator{
+		metrics: newMetrics(),
+	}
 }
 
 // Validate performs the validation check
 func (v *ContentAddressValidator) Validate(ch swarm.Chunk) (valid bool) {
-	p := bmtlegacy.NewTreePool(hashFunc, swarm.Branches, bmtlegacy.PoolSize)
-	hasher := bmtlegacy.New(p)
+	start := time.Now()
+	defer func() {
+		v.metrics.ValidationTime.Observe(time.Since(start).Seconds())
+		v.metrics.ValidatedCounter.Inc()
+		if !valid {
+			v.metrics.InvalidCounter.Inc()
+		}
+	}()
 
 	// prepare data
 	data := ch.Data()
+	if len(data) < 8 {
+		return false
+	}
 	address := ch.Address()
 	span := binary.LittleEndian.Uint64(data[:8])
 
+	hasher := bmtpool.Get()
+	defer bmtpool.Put(hasher)
+
 	// execute hash, compare and return result
-	hasher.Reset()
 	err := hasher.SetSpan(int64(span))
 	if err != nil {
 		return false
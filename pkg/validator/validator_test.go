@@ -4,9 +4,16 @@
 package validator_test
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/binary"
+	"fmt"
+	"sync"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/splitter"
+	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/validator"
 )
@@ -40,3 +47,77 @@ func TestContentAddressValidator(t *testing.T) {
 		t.Fatalf("data '%s' should not have validated to hash '%s'", ch.Data(), ch.Address())
 	}
 }
+
+// TestContentAddressValidatorConcurrent checks that a single validator
+// instance gives correct results when used concurrently.
+func TestContentAddressValidatorConcurrent(t *testing.T) {
+	v := validator.NewContentAddressValidator()
+	valid, invalid := testChunks(t)
+
+	var wg sync.WaitGroup
+	errC := make(chan error, 100)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !v.Validate(valid) {
+				errC <- fmt.Errorf("chunk %s should be valid", valid.Address())
+			}
+			if v.Validate(invalid) {
+				errC <- fmt.Errorf("chunk %s should not be valid", invalid.Address())
+			}
+		}()
+	}
+	wg.Wait()
+	close(errC)
+	for err := range errC {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkContentAddressValidator(b *testing.B) {
+	v := validator.NewContentAddressValidator()
+	ch, _ := testChunks(b)
+
+	b.Run("sequential", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if !v.Validate(ch) {
+				b.Fatal("invalid chunk")
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if !v.Validate(ch) {
+					b.Fatal("invalid chunk")
+				}
+			}
+		})
+	})
+}
+
+// testChunks returns a full valid chunk and the same chunk
+// with the last byte of data modified.
+func testChunks(tb testing.TB) (valid, invalid swarm.Chunk) {
+	tb.Helper()
+
+	data := make([]byte, 8+swarm.ChunkSize)
+	binary.LittleEndian.PutUint64(data, swarm.ChunkSize)
+	if _, err := rand.Read(data[8:]); err != nil {
+		tb.Fatal(err)
+	}
+	s := splitter.NewSimpleSplitter(mock.NewStorer())
+	addr, err := s.Split(context.Background(), file.NewSimpleReadCloser(data[8:]), swarm.ChunkSize)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	invalidData := make([]byte, len(data))
+	copy(invalidData, data)
+	invalidData[len(invalidData)-1]++
+
+	return swarm.NewChunk(addr, data), swarm.NewChunk(addr, invalidData)
+}